@@ -1,10 +1,19 @@
 package main
 
 import (
+	"context"
 	"log"
+	"os"
+	"os/signal"
+	"syscall"
 
+	"backend/root/internal/categories"
 	"backend/root/internal/config"
+	"backend/root/internal/database"
 	httpserver "backend/root/internal/http"
+	"backend/root/internal/reminders"
+	"backend/root/internal/tasks"
+	"backend/root/internal/trash"
 	"github.com/gin-gonic/gin"
 )
 
@@ -15,15 +24,69 @@ func main() {
 	// Set Gin mode based on configuration
 	gin.SetMode(cfg.Server.Mode)
 
+	// Connect to PostgreSQL
+	db, err := database.Open(cfg.Database)
+	if err != nil {
+		log.Fatalf("Database error: %v", err)
+	}
+	defer db.Close()
+	log.Printf("Using PostgreSQL database: %s@%s:%s/%s",
+		cfg.Database.User, cfg.Database.Host, cfg.Database.Port, cfg.Database.Name)
+
+	// Stop background jobs on SIGINT/SIGTERM
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Start the reminder scheduler; replicas coordinate through row locks in the database
+	if cfg.Reminders.Enabled {
+		reminderRepo := reminders.NewPostgresRepository(db)
+		notifiers := map[string]reminders.Notifier{
+			reminders.ChannelInApp: reminders.NewInAppNotifier(reminderRepo),
+			reminders.ChannelEmail: reminders.NewEmailNotifier(reminders.SMTPConfig{
+				Host:     cfg.SMTP.Host,
+				Port:     cfg.SMTP.Port,
+				Username: cfg.SMTP.Username,
+				Password: cfg.SMTP.Password,
+				From:     cfg.SMTP.From,
+			}),
+			reminders.ChannelWebhook: reminders.NewWebhookNotifier(cfg.Reminders.WebhookTimeout),
+		}
+		scheduler := reminders.NewScheduler(reminderRepo, notifiers, reminders.SystemClock{}, reminders.SchedulerConfig{
+			PollInterval: cfg.Reminders.PollInterval,
+			BatchSize:    cfg.Reminders.BatchSize,
+			Lease:        cfg.Reminders.Lease,
+			MaxAttempts:  cfg.Reminders.MaxAttempts,
+			RetryBackoff: cfg.Reminders.RetryBackoff,
+		})
+		go scheduler.Run(ctx)
+	}
+
+	// Start the trash purger; purging is idempotent so replicas may run it concurrently
+	if cfg.Trash.PurgeEnabled {
+		purger := trash.NewPurger(
+			tasks.NewPostgresRepository(db),
+			categories.NewPostgresRepository(db),
+			cfg.Trash.Retention,
+			cfg.Trash.PurgeInterval,
+		)
+		go purger.Run(ctx)
+	}
+
+	// Start the board rebalancer; rewriting a column is idempotent so replicas may run it concurrently
+	if cfg.Board.RebalanceEnabled {
+		rebalancer := tasks.NewRebalancer(tasks.NewPostgresRepository(db), cfg.Board.RebalanceInterval)
+		go rebalancer.Run(ctx)
+	}
+
 	// Create router with configuration
-	router := httpserver.NewRouter(cfg)
+	router := httpserver.NewRouter(cfg, db)
 
 	// Start server
 	addr := cfg.Server.Host + ":" + cfg.Server.Port
-	log.Printf("Starting %s v%s on %s (env: %s)", 
+	log.Printf("Starting %s v%s on %s (env: %s)",
 		cfg.App.Name, cfg.App.Version, addr, cfg.App.Env)
 
 	if err := router.Run(addr); err != nil {
 		log.Fatal("Failed to start server:", err)
 	}
-}
\ No newline at end of file
+}
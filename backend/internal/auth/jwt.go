@@ -5,6 +5,8 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+
+	"backend/root/internal/i18n"
 )
 
 // TokenManager encapsulates JWT signing and verification.
@@ -54,11 +56,11 @@ func (t TokenManager) VerifyToken(tokenString string) (jwt.MapClaims, error) {
 		return nil, err
 	}
 	if !token.Valid {
-		return nil, errors.New("invalid token")
+		return nil, i18n.NewError("token_invalid")
 	}
 	claims, ok := token.Claims.(jwt.MapClaims)
 	if !ok {
-		return nil, errors.New("invalid token claims")
+		return nil, i18n.NewError("token_claims_invalid")
 	}
 	return claims, nil
 }
@@ -0,0 +1,528 @@
+package calendar
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"backend/root/internal/etag"
+	"backend/root/internal/i18n"
+	"backend/root/internal/tasks"
+	"backend/root/internal/workspaces"
+)
+
+// DAVPath is where the CalDAV endpoint is mounted. Below it, /<username>/ is both the
+// principal and the calendar home of a user, /<username>/<calendar>/ a calendar and
+// /<username>/<calendar>/<name>.ics a to-do.
+const DAVPath = "/caldav/"
+
+// DAVMethods are the HTTP methods the CalDAV endpoint answers
+var DAVMethods = []string{"OPTIONS", "PROPFIND", "REPORT", "GET", "HEAD", "PUT", "DELETE"}
+
+// maxObjectBytes caps the size of a calendar object sent with PUT
+const maxObjectBytes = 256 << 10
+
+// objectContentType is the media type of calendar object resources
+const objectContentType = "text/calendar; charset=utf-8; component=VTODO"
+
+// WellKnown handles /.well-known/caldav - points calendar clients to the CalDAV endpoint (RFC 6764)
+func (h *Handler) WellKnown(c *gin.Context) {
+	c.Redirect(http.StatusMovedPermanently, DAVPath)
+}
+
+// DAV handles every request below /caldav/. Calendar clients authenticate with the username
+// and password of the account; they see the personal tasks of the user.
+func (h *Handler) DAV(c *gin.Context) {
+	if c.Request.Method == http.MethodOptions {
+		c.Header("DAV", "1, 3, calendar-access")
+		c.Header("Allow", strings.Join(DAVMethods, ", "))
+		c.Status(http.StatusOK)
+		return
+	}
+
+	userID, err := getUserIDFromClaims(c)
+	if err != nil {
+		i18n.RespondError(c, http.StatusUnauthorized, err)
+		return
+	}
+	username, err := getUsernameFromClaims(c)
+	if err != nil {
+		i18n.RespondError(c, http.StatusUnauthorized, err)
+		return
+	}
+
+	// Calendars always show the personal space
+	c.Request = c.Request.WithContext(workspaces.WithScope(c.Request.Context(), workspaces.Scope{}))
+
+	segments := strings.FieldsFunc(c.Param("path"), func(r rune) bool { return r == '/' })
+	if len(segments) > 3 || (len(segments) > 0 && segments[0] != username) {
+		i18n.RespondError(c, http.StatusNotFound, ErrCollectionNotFound)
+		return
+	}
+	target := davTarget{userID: userID, username: username}
+	if len(segments) > 1 {
+		target.collection, err = h.service.GetCollection(c.Request.Context(), segments[1])
+		if err != nil {
+			davFail(c, err)
+			return
+		}
+	}
+	if len(segments) > 2 {
+		target.name = segments[2]
+	}
+
+	switch c.Request.Method {
+	case "PROPFIND":
+		h.propfind(c, target, len(segments))
+	case "REPORT":
+		if len(segments) != 2 {
+			i18n.RespondError(c, http.StatusMethodNotAllowed, i18n.NewError("caldav_report_target"))
+			return
+		}
+		h.report(c, target)
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+		if len(segments) != 3 {
+			i18n.RespondError(c, http.StatusMethodNotAllowed, i18n.NewError("caldav_object_target"))
+			return
+		}
+		switch c.Request.Method {
+		case http.MethodPut:
+			h.putObject(c, target)
+		case http.MethodDelete:
+			h.deleteObject(c, target)
+		default:
+			h.getObject(c, target)
+		}
+	default:
+		i18n.RespondError(c, http.StatusMethodNotAllowed, i18n.NewError("caldav_method_not_allowed"))
+	}
+}
+
+// davTarget is the resource a CalDAV request addresses
+type davTarget struct {
+	userID     int64
+	username   string
+	collection *Collection // nil above the calendars
+	name       string      // to-do resource name, "" above the to-dos
+}
+
+// propfind answers PROPFIND on the root, the calendar home, a calendar or a to-do
+func (h *Handler) propfind(c *gin.Context, target davTarget, level int) {
+	request, err := parseXML(c.Request.Body)
+	if err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, err)
+		return
+	}
+	names, all := propNames(request)
+	ctx := c.Request.Context()
+	members := depth(c.GetHeader("Depth")) == 1
+
+	var resources []*davResource
+	switch level {
+	case 0:
+		resources = append(resources, rootResource(target.username))
+		if members {
+			resources = append(resources, homeResource(target.username))
+		}
+	case 1:
+		resources = append(resources, homeResource(target.username))
+		if members {
+			collections, err := h.service.GetCollections(ctx)
+			if err != nil {
+				davFail(c, err)
+				return
+			}
+			token, err := h.service.GetSyncToken(ctx, target.userID)
+			if err != nil {
+				davFail(c, err)
+				return
+			}
+			for _, collection := range collections {
+				resources = append(resources, collectionResource(target.username, collection, token))
+			}
+		}
+	case 2:
+		token, err := h.service.GetSyncToken(ctx, target.userID)
+		if err != nil {
+			davFail(c, err)
+			return
+		}
+		resources = append(resources, collectionResource(target.username, target.collection, token))
+		if members {
+			objects, err := h.service.GetObjects(ctx, target.userID, target.collection)
+			if err != nil {
+				davFail(c, err)
+				return
+			}
+			for _, object := range objects {
+				resources = append(resources, objectResource(target.username, target.collection, object))
+			}
+		}
+	default:
+		object, err := h.service.GetObject(ctx, target.userID, target.collection, target.name)
+		if err != nil {
+			davFail(c, err)
+			return
+		}
+		resources = append(resources, objectResource(target.username, target.collection, object))
+	}
+
+	response := newMultistatus()
+	for _, resource := range resources {
+		response.add(resource, names, all)
+	}
+	c.Data(http.StatusMultiStatus, "application/xml; charset=utf-8", response.Bytes())
+}
+
+// report answers the calendar-query, calendar-multiget and sync-collection reports of a calendar
+func (h *Handler) report(c *gin.Context, target davTarget) {
+	request, err := parseXML(c.Request.Body)
+	if err != nil || request == nil {
+		i18n.RespondError(c, http.StatusBadRequest, errInvalidXML)
+		return
+	}
+	names, all := propNames(request)
+	ctx := c.Request.Context()
+	response := newMultistatus()
+
+	switch request.Name {
+	case reportCalendarMultiget:
+		for _, href := range request.Children {
+			if href.Name != (xml.Name{Space: nsDAV, Local: "href"}) {
+				continue
+			}
+			location := strings.TrimSpace(href.Text)
+			name, ok := objectName(location, target.username, target.collection)
+			if !ok {
+				response.status(location, http.StatusNotFound)
+				continue
+			}
+			object, err := h.service.GetObject(ctx, target.userID, target.collection, name)
+			if errors.Is(err, ErrObjectNotFound) {
+				response.status(location, http.StatusNotFound)
+				continue
+			}
+			if err != nil {
+				davFail(c, err)
+				return
+			}
+			response.add(objectResource(target.username, target.collection, object), names, all)
+		}
+
+	case reportCalendarQuery:
+		filter := parseQueryFilter(request.child(xml.Name{Space: nsCalDAV, Local: "filter"}))
+		objects, err := h.service.GetObjects(ctx, target.userID, target.collection)
+		if err != nil {
+			davFail(c, err)
+			return
+		}
+		for _, object := range objects {
+			if filter.matches(object.Task) {
+				response.add(objectResource(target.username, target.collection, object), names, all)
+			}
+		}
+
+	case reportSyncCollection:
+		token := strings.TrimSpace(request.child(propSyncToken).textOrEmpty())
+		changes, err := h.service.GetChanges(ctx, target.userID, target.collection, token)
+		if errors.Is(err, ErrInvalidSyncToken) {
+			c.Data(http.StatusForbidden, "application/xml; charset=utf-8", davError(xml.Name{Space: nsDAV, Local: "valid-sync-token"}))
+			return
+		}
+		if err != nil {
+			davFail(c, err)
+			return
+		}
+		for _, object := range changes.Objects {
+			response.add(objectResource(target.username, target.collection, object), names, all)
+		}
+		for _, name := range changes.Removed {
+			response.status(objectHref(target.username, target.collection, name), http.StatusNotFound)
+		}
+		response.syncToken(changes.SyncToken)
+
+	default:
+		c.Data(http.StatusForbidden, "application/xml; charset=utf-8", davError(xml.Name{Space: nsDAV, Local: "supported-report"}))
+		return
+	}
+
+	c.Data(http.StatusMultiStatus, "application/xml; charset=utf-8", response.Bytes())
+}
+
+// getObject answers GET and HEAD on a to-do
+func (h *Handler) getObject(c *gin.Context, target davTarget) {
+	object, err := h.service.GetObject(c.Request.Context(), target.userID, target.collection, target.name)
+	if err != nil {
+		davFail(c, err)
+		return
+	}
+
+	c.Header("ETag", etag.Format(object.Task.Version))
+	if etag.Matches(c.GetHeader("If-None-Match"), etag.Format(object.Task.Version)) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.Data(http.StatusOK, objectContentType, writeObject(object))
+}
+
+// putObject answers PUT on a to-do, creating or updating its task. No ETag is returned,
+// since the stored to-do only keeps the properties tasks have.
+func (h *Handler) putObject(c *gin.Context, target davTarget) {
+	ifMatch, err := etag.ParseIfMatch(c.GetHeader("If-Match"))
+	if err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	data, err := io.ReadAll(io.LimitReader(c.Request.Body, maxObjectBytes+1))
+	if err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, i18n.NewError("caldav_object_read_failed"))
+		return
+	}
+	if len(data) > maxObjectBytes {
+		i18n.RespondError(c, http.StatusRequestEntityTooLarge, i18n.NewError("caldav_object_too_large"))
+		return
+	}
+
+	_, created, err := h.service.PutObject(c.Request.Context(), target.userID, target.collection, target.name, PutObjectRequest{
+		Data:        data,
+		IfMatch:     ifMatch,
+		IfNoneMatch: strings.TrimSpace(c.GetHeader("If-None-Match")) == "*",
+	})
+	switch {
+	case errors.Is(err, ErrUnsupportedComponent):
+		c.Data(http.StatusForbidden, "application/xml; charset=utf-8", davError(xml.Name{Space: nsCalDAV, Local: "supported-calendar-component"}))
+		return
+	case errors.Is(err, errInvalidObject):
+		c.Data(http.StatusForbidden, "application/xml; charset=utf-8", davError(xml.Name{Space: nsCalDAV, Local: "valid-calendar-data"}))
+		return
+	case err != nil:
+		davFail(c, err)
+		return
+	}
+
+	if created {
+		c.Status(http.StatusCreated)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// deleteObject answers DELETE on a to-do, moving its task to the trash
+func (h *Handler) deleteObject(c *gin.Context, target davTarget) {
+	ifMatch, err := etag.ParseIfMatch(c.GetHeader("If-Match"))
+	if err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := h.service.DeleteObject(c.Request.Context(), target.userID, target.collection, target.name, ifMatch); err != nil {
+		davFail(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// davFail writes the error of a CalDAV request with the status the task API uses for it.
+// Other coded errors are rejected requests; errors without a code are internal failures.
+func davFail(c *gin.Context, err error) {
+	var coded *i18n.Error
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, ErrCollectionNotFound), errors.Is(err, ErrObjectNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, tasks.ErrVersionMismatch), errors.Is(err, ErrObjectExists):
+		status = http.StatusPreconditionFailed
+	case errors.Is(err, ErrOtherCollection), tasks.IsTransitionError(err):
+		status = http.StatusConflict
+	case errors.Is(err, tasks.ErrPermissionDenied), errors.Is(err, workspaces.ErrReadOnly):
+		status = http.StatusForbidden
+	case errors.As(err, &coded):
+		status = http.StatusBadRequest
+	}
+
+	i18n.RespondError(c, status, err)
+}
+
+// rootResource describes /caldav/, which points clients to the principal of the user
+func rootResource(username string) *davResource {
+	return &davResource{
+		Href: DAVPath,
+		Props: map[xml.Name]string{
+			propResourceType:         "<d:collection/>",
+			propCurrentUserPrincipal: hrefXML(homeHref(username)),
+		},
+	}
+}
+
+// homeResource describes the principal of a user, which is also the home of their calendars
+func homeResource(username string) *davResource {
+	home := hrefXML(homeHref(username))
+	return &davResource{
+		Href: homeHref(username),
+		Props: map[xml.Name]string{
+			propResourceType:         "<d:collection/><d:principal/>",
+			propDisplayName:          escapeXML(username),
+			propCurrentUserPrincipal: home,
+			propPrincipalURL:         home,
+			propCalendarHomeSet:      home,
+		},
+	}
+}
+
+// collectionResource describes a calendar. Its sync token and CTag change with any task of the user.
+func collectionResource(username string, collection *Collection, token string) *davResource {
+	props := map[xml.Name]string{
+		propResourceType:          "<d:collection/><c:calendar/>",
+		propDisplayName:           escapeXML(collection.DisplayName),
+		propSupportedComponentSet: `<c:comp name="VTODO"/>`,
+		propSyncToken:             escapeXML(token),
+		propGetCTag:               escapeXML(token),
+		propCurrentUserPrincipal:  hrefXML(homeHref(username)),
+		propOwner:                 hrefXML(homeHref(username)),
+		propSupportedReportSet: "<d:supported-report><d:report><c:calendar-query/></d:report></d:supported-report>" +
+			"<d:supported-report><d:report><c:calendar-multiget/></d:report></d:supported-report>" +
+			"<d:supported-report><d:report><d:sync-collection/></d:report></d:supported-report>",
+		propCurrentUserPrivileges: "<d:privilege><d:read/></d:privilege><d:privilege><d:write/></d:privilege>" +
+			"<d:privilege><d:write-content/></d:privilege><d:privilege><d:bind/></d:privilege><d:privilege><d:unbind/></d:privilege>",
+	}
+	if collection.Description != "" {
+		props[propCalendarDescription] = escapeXML(collection.Description)
+	}
+
+	return &davResource{Href: collectionHref(username, collection), Props: props}
+}
+
+// objectResource describes a to-do
+func objectResource(username string, collection *Collection, object *Object) *davResource {
+	return &davResource{
+		Href: objectHref(username, collection, object.Name),
+		Props: map[xml.Name]string{
+			propResourceType:   "",
+			propGetETag:        escapeXML(etag.Format(object.Task.Version)),
+			propGetContentType: objectContentType,
+			propCalendarData:   escapeXML(string(writeObject(object))),
+			propCurrentUserPrivileges: "<d:privilege><d:read/></d:privilege><d:privilege><d:write/></d:privilege>" +
+				"<d:privilege><d:write-content/></d:privilege>",
+		},
+	}
+}
+
+// homeHref returns the path of the principal and calendar home of a user
+func homeHref(username string) string {
+	return DAVPath + url.PathEscape(username) + "/"
+}
+
+// collectionHref returns the path of a calendar
+func collectionHref(username string, collection *Collection) string {
+	return homeHref(username) + url.PathEscape(collection.Name) + "/"
+}
+
+// objectHref returns the path of a to-do
+func objectHref(username string, collection *Collection, name string) string {
+	return collectionHref(username, collection) + url.PathEscape(name)
+}
+
+// objectName returns the resource name of a to-do href of a calendar, given as a path or an absolute URL
+func objectName(href string, username string, collection *Collection) (string, bool) {
+	location, err := url.Parse(href)
+	if err != nil {
+		return "", false
+	}
+
+	prefix := DAVPath + username + "/" + collection.Name + "/"
+	name, ok := strings.CutPrefix(location.Path, prefix)
+	if !ok || name == "" || strings.Contains(name, "/") {
+		return "", false
+	}
+	return name, true
+}
+
+// textOrEmpty returns the text of an element, "" when it is missing
+func (n *xmlNode) textOrEmpty() string {
+	if n == nil {
+		return ""
+	}
+	return n.Text
+}
+
+// queryFilter is the part of a calendar-query filter that is applied: the component, a
+// time range on the due date and whether the to-do is completed. Other conditions are
+// ignored, so clients may get more to-dos than they asked for.
+type queryFilter struct {
+	component string     // only VTODO matches anything
+	start     *time.Time // due on or after
+	end       *time.Time // due before
+	completed *bool      // only completed (true) or open (false) to-dos
+}
+
+// parseQueryFilter reads the filter of a calendar-query report
+func parseQueryFilter(filter *xmlNode) queryFilter {
+	result := queryFilter{component: "VTODO"}
+	compFilter := xml.Name{Space: nsCalDAV, Local: "comp-filter"}
+
+	calendar := filter.child(compFilter)
+	if calendar == nil {
+		return result
+	}
+	component := calendar.child(compFilter)
+	if component == nil {
+		return result
+	}
+	result.component = strings.ToUpper(component.attr("name"))
+
+	if timeRange := component.child(xml.Name{Space: nsCalDAV, Local: "time-range"}); timeRange != nil {
+		if start, err := time.Parse("20060102T150405Z", timeRange.attr("start")); err == nil {
+			result.start = &start
+		}
+		if end, err := time.Parse("20060102T150405Z", timeRange.attr("end")); err == nil {
+			result.end = &end
+		}
+	}
+
+	for _, prop := range component.Children {
+		if prop.Name != (xml.Name{Space: nsCalDAV, Local: "prop-filter"}) {
+			continue
+		}
+		switch strings.ToUpper(prop.attr("name")) {
+		case "COMPLETED":
+			completed := prop.child(xml.Name{Space: nsCalDAV, Local: "is-not-defined"}) == nil
+			result.completed = &completed
+		case "STATUS":
+			if match := prop.child(xml.Name{Space: nsCalDAV, Local: "text-match"}); match != nil && strings.EqualFold(strings.TrimSpace(match.Text), statusCompleted) {
+				completed := match.attr("negate-condition") != "yes"
+				result.completed = &completed
+			}
+		}
+	}
+
+	return result
+}
+
+// matches reports whether the to-do of a task passes the filter. To-dos without a due
+// date match any time range.
+func (f queryFilter) matches(task *tasks.TaskResponse) bool {
+	if f.component != "VTODO" {
+		return false
+	}
+	if f.completed != nil && *f.completed != (todoStatus(task) == statusCompleted) {
+		return false
+	}
+	if task.EndDate != nil {
+		dueStart, dueEnd := *task.EndDate, task.EndDate.AddDate(0, 0, 1)
+		if f.start != nil && !dueEnd.After(*f.start) {
+			return false
+		}
+		if f.end != nil && !dueStart.Before(*f.end) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,275 @@
+package calendar
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"backend/root/internal/i18n"
+)
+
+// XML namespaces of WebDAV (RFC 4918), CalDAV (RFC 4791) and the calendar server extensions
+const (
+	nsDAV    = "DAV:"
+	nsCalDAV = "urn:ietf:params:xml:ns:caldav"
+	nsCS     = "http://calendarserver.org/ns/"
+)
+
+// davPrefixes are the prefixes multistatus responses declare for the known namespaces
+var davPrefixes = map[string]string{nsDAV: "d", nsCalDAV: "c", nsCS: "cs"}
+
+// Properties served by the CalDAV endpoint
+var (
+	propResourceType          = xml.Name{Space: nsDAV, Local: "resourcetype"}
+	propDisplayName           = xml.Name{Space: nsDAV, Local: "displayname"}
+	propGetETag               = xml.Name{Space: nsDAV, Local: "getetag"}
+	propGetContentType        = xml.Name{Space: nsDAV, Local: "getcontenttype"}
+	propCurrentUserPrincipal  = xml.Name{Space: nsDAV, Local: "current-user-principal"}
+	propPrincipalURL          = xml.Name{Space: nsDAV, Local: "principal-URL"}
+	propOwner                 = xml.Name{Space: nsDAV, Local: "owner"}
+	propSyncToken             = xml.Name{Space: nsDAV, Local: "sync-token"}
+	propSupportedReportSet    = xml.Name{Space: nsDAV, Local: "supported-report-set"}
+	propCurrentUserPrivileges = xml.Name{Space: nsDAV, Local: "current-user-privilege-set"}
+	propCalendarHomeSet       = xml.Name{Space: nsCalDAV, Local: "calendar-home-set"}
+	propCalendarDescription   = xml.Name{Space: nsCalDAV, Local: "calendar-description"}
+	propSupportedComponentSet = xml.Name{Space: nsCalDAV, Local: "supported-calendar-component-set"}
+	propCalendarData          = xml.Name{Space: nsCalDAV, Local: "calendar-data"}
+	propGetCTag               = xml.Name{Space: nsCS, Local: "getctag"}
+)
+
+// Report types
+var (
+	reportCalendarQuery    = xml.Name{Space: nsCalDAV, Local: "calendar-query"}
+	reportCalendarMultiget = xml.Name{Space: nsCalDAV, Local: "calendar-multiget"}
+	reportSyncCollection   = xml.Name{Space: nsDAV, Local: "sync-collection"}
+)
+
+// maxDAVRequestBytes caps the size of PROPFIND and REPORT bodies
+const maxDAVRequestBytes = 1 << 20
+
+// errInvalidXML is returned for a request body that is not well-formed XML
+var errInvalidXML = i18n.NewError("caldav_xml_invalid")
+
+// xmlNode is an element of a parsed request body
+type xmlNode struct {
+	Name     xml.Name
+	Attrs    []xml.Attr
+	Text     string
+	Children []*xmlNode
+}
+
+// parseXML reads a request body into a tree of elements; an empty body gives nil
+func parseXML(r io.Reader) (*xmlNode, error) {
+	decoder := xml.NewDecoder(io.LimitReader(r, maxDAVRequestBytes))
+	var root *xmlNode
+	var stack []*xmlNode
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errInvalidXML
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			node := &xmlNode{Name: t.Name, Attrs: t.Attr}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.Children = append(parent.Children, node)
+			} else if root == nil {
+				root = node
+			}
+			stack = append(stack, node)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].Text += string(t)
+			}
+		}
+	}
+
+	return root, nil
+}
+
+// child returns the first child element with the given name, nil if there is none
+func (n *xmlNode) child(name xml.Name) *xmlNode {
+	if n == nil {
+		return nil
+	}
+	for _, child := range n.Children {
+		if child.Name == name {
+			return child
+		}
+	}
+	return nil
+}
+
+// attr returns the value of an attribute, "" if it is missing
+func (n *xmlNode) attr(local string) string {
+	for _, attr := range n.Attrs {
+		if attr.Name.Local == local {
+			return attr.Value
+		}
+	}
+	return ""
+}
+
+// propNames returns the names of the properties a request asks for, and whether it asks for
+// all of them: an empty PROPFIND body or DAV:allprop. calendar-data is only sent on request.
+func propNames(request *xmlNode) ([]xml.Name, bool) {
+	prop := request.child(xml.Name{Space: nsDAV, Local: "prop"})
+	if prop == nil {
+		return nil, true
+	}
+
+	names := make([]xml.Name, 0, len(prop.Children))
+	for _, child := range prop.Children {
+		names = append(names, child.Name)
+	}
+	return names, false
+}
+
+// davResource is a resource with the values of its properties, as inner XML
+type davResource struct {
+	Href  string
+	Props map[xml.Name]string
+}
+
+// multistatus builds a 207 Multi-Status response body
+type multistatus struct {
+	buf bytes.Buffer
+}
+
+// newMultistatus starts a response body declaring the known namespaces
+func newMultistatus() *multistatus {
+	m := &multistatus{}
+	m.buf.WriteString(xml.Header)
+	m.buf.WriteString(`<d:multistatus xmlns:d="DAV:" xmlns:c="` + nsCalDAV + `" xmlns:cs="` + nsCS + `">`)
+	return m
+}
+
+// add writes the requested properties of a resource, grouping those it does not have under 404
+func (m *multistatus) add(resource *davResource, names []xml.Name, all bool) {
+	m.buf.WriteString("<d:response><d:href>")
+	xml.EscapeText(&m.buf, []byte(resource.Href))
+	m.buf.WriteString("</d:href>")
+
+	if all {
+		names = names[:0:0]
+		for _, name := range allProps {
+			if _, ok := resource.Props[name]; ok {
+				names = append(names, name)
+			}
+		}
+	}
+
+	var missing []xml.Name
+	found := false
+	for _, name := range names {
+		if _, ok := resource.Props[name]; !ok {
+			missing = append(missing, name)
+			continue
+		}
+		if !found {
+			m.buf.WriteString("<d:propstat><d:prop>")
+			found = true
+		}
+		writeElement(&m.buf, name, resource.Props[name])
+	}
+	if found {
+		m.buf.WriteString("</d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat>")
+	}
+
+	if len(missing) > 0 {
+		m.buf.WriteString("<d:propstat><d:prop>")
+		for _, name := range missing {
+			writeElement(&m.buf, name, "")
+		}
+		m.buf.WriteString("</d:prop><d:status>HTTP/1.1 404 Not Found</d:status></d:propstat>")
+	}
+
+	m.buf.WriteString("</d:response>")
+}
+
+// status writes a response carrying only a status, e.g. 404 for a removed resource
+func (m *multistatus) status(href string, code int) {
+	m.buf.WriteString("<d:response><d:href>")
+	xml.EscapeText(&m.buf, []byte(href))
+	m.buf.WriteString("</d:href><d:status>HTTP/1.1 " + strconv.Itoa(code) + " " + http.StatusText(code) + "</d:status></d:response>")
+}
+
+// syncToken writes the token a sync-collection report ends with
+func (m *multistatus) syncToken(token string) {
+	m.buf.WriteString("<d:sync-token>")
+	xml.EscapeText(&m.buf, []byte(token))
+	m.buf.WriteString("</d:sync-token>")
+}
+
+// Bytes closes the response body and returns it
+func (m *multistatus) Bytes() []byte {
+	m.buf.WriteString("</d:multistatus>")
+	return m.buf.Bytes()
+}
+
+// allProps are the properties returned for DAV:allprop, in order
+var allProps = []xml.Name{
+	propResourceType, propDisplayName, propGetETag, propGetContentType, propCurrentUserPrincipal,
+	propPrincipalURL, propOwner, propSyncToken, propCalendarHomeSet, propCalendarDescription,
+	propSupportedComponentSet, propGetCTag,
+}
+
+// writeElement writes a property element with inner XML, declaring its namespace when it has no known prefix
+func writeElement(buf *bytes.Buffer, name xml.Name, inner string) {
+	tag, declaration := name.Local, ""
+	if prefix, ok := davPrefixes[name.Space]; ok {
+		tag = prefix + ":" + name.Local
+	} else if name.Space != "" {
+		tag = "x:" + name.Local
+		var escaped bytes.Buffer
+		xml.EscapeText(&escaped, []byte(name.Space))
+		declaration = ` xmlns:x="` + escaped.String() + `"`
+	}
+
+	if inner == "" {
+		buf.WriteString("<" + tag + declaration + "/>")
+		return
+	}
+	buf.WriteString("<" + tag + declaration + ">" + inner + "</" + tag + ">")
+}
+
+// hrefXML returns the inner XML of a property holding a single href
+func hrefXML(href string) string {
+	return "<d:href>" + escapeXML(href) + "</d:href>"
+}
+
+// escapeXML escapes text for use inside an element
+func escapeXML(text string) string {
+	var buf bytes.Buffer
+	xml.EscapeText(&buf, []byte(text))
+	return buf.String()
+}
+
+// davError returns the body of an error response naming the precondition that failed
+func davError(condition xml.Name) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	buf.WriteString(`<d:error xmlns:d="DAV:" xmlns:c="` + nsCalDAV + `">`)
+	writeElement(&buf, condition, "")
+	buf.WriteString("</d:error>")
+	return buf.Bytes()
+}
+
+// depth reads the Depth header of a PROPFIND: 0 for the resource alone, 1 to include its
+// members. Infinity is served as 1, which reaches every resource below a calendar.
+func depth(header string) int {
+	if strings.TrimSpace(header) == "0" {
+		return 0
+	}
+	return 1
+}
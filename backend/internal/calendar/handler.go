@@ -0,0 +1,163 @@
+package calendar
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+
+	"backend/root/internal/i18n"
+)
+
+// FeedPath is the public path calendar feeds are served under, followed by the token
+const FeedPath = "/api/ical/"
+
+// Handler handles HTTP requests for calendar feeds
+type Handler struct {
+	service Service
+}
+
+// NewHandler creates a new calendar feed handler
+func NewHandler(service Service) *Handler {
+	return &Handler{
+		service: service,
+	}
+}
+
+// GetFeed handles GET /calendar/feed - retrieves the address of the caller's feed
+func (h *Handler) GetFeed(c *gin.Context) {
+	userID, err := getUserIDFromClaims(c)
+	if err != nil {
+		i18n.RespondError(c, http.StatusUnauthorized, err)
+		return
+	}
+
+	feed, err := h.service.GetFeed(c.Request.Context(), userID)
+	if err != nil {
+		i18n.RespondError(c, http.StatusInternalServerError, i18n.NewError("calendar_feed_get_failed"))
+		return
+	}
+
+	feed.URL = feedURL(c, feed.Token)
+	c.JSON(http.StatusOK, feed)
+}
+
+// RegenerateToken handles POST /calendar/feed/regenerate - replaces the token of the caller's feed
+func (h *Handler) RegenerateToken(c *gin.Context) {
+	userID, err := getUserIDFromClaims(c)
+	if err != nil {
+		i18n.RespondError(c, http.StatusUnauthorized, err)
+		return
+	}
+
+	feed, err := h.service.RegenerateToken(c.Request.Context(), userID)
+	if err != nil {
+		i18n.RespondError(c, http.StatusInternalServerError, i18n.NewError("calendar_feed_regen_failed"))
+		return
+	}
+
+	feed.URL = feedURL(c, feed.Token)
+	c.JSON(http.StatusOK, feed)
+}
+
+// Feed handles GET /ical/:token.ics - serves a feed to calendar apps, authenticated by its token
+func (h *Handler) Feed(c *gin.Context) {
+	token, ok := strings.CutSuffix(c.Param("token"), FeedExtension)
+	if !ok || token == "" {
+		i18n.RespondError(c, http.StatusNotFound, ErrFeedNotFound)
+		return
+	}
+
+	var filter FeedFilter
+	if categoryIDsStr := c.Query("category_ids"); categoryIDsStr != "" {
+		for _, part := range strings.Split(categoryIDsStr, ",") {
+			categoryID, err := strconv.ParseInt(strings.TrimSpace(part), 10, 64)
+			if err != nil {
+				i18n.RespondError(c, http.StatusBadRequest, i18n.NewError("calendar_category_ids_invalid"))
+				return
+			}
+			filter.CategoryIDs = append(filter.CategoryIDs, categoryID)
+		}
+	}
+	filter.Component = strings.ToLower(c.Query("component"))
+	if onlyOpen := c.Query("only_open"); onlyOpen != "" {
+		value, err := strconv.ParseBool(onlyOpen)
+		if err != nil {
+			i18n.RespondError(c, http.StatusBadRequest, i18n.NewError("calendar_only_open_invalid"))
+			return
+		}
+		filter.OnlyOpen = value
+	}
+
+	body, err := h.service.Render(c.Request.Context(), token, filter)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrFeedNotFound):
+			i18n.RespondError(c, http.StatusNotFound, err)
+		case errors.Is(err, ErrInvalidComponent):
+			i18n.RespondError(c, http.StatusBadRequest, err)
+		default:
+			i18n.RespondError(c, http.StatusInternalServerError, i18n.NewError("calendar_feed_render_failed"))
+		}
+		return
+	}
+
+	c.Header("Cache-Control", "private, no-cache")
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", body)
+}
+
+// feedURL returns the absolute address of a feed as seen by the client of the request
+func feedURL(c *gin.Context, token string) string {
+	scheme := "http"
+	if c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+
+	return scheme + "://" + c.Request.Host + FeedPath + token + FeedExtension
+}
+
+// getUserIDFromClaims extracts the user ID from the JWT claims set by the auth middleware
+func getUserIDFromClaims(c *gin.Context) (int64, error) {
+	claims, exists := c.Get("claims")
+	if !exists {
+		return 0, i18n.NewError("user_not_authenticated")
+	}
+
+	claimsMap, ok := claims.(jwt.MapClaims)
+	if !ok {
+		return 0, errors.New("invalid claims")
+	}
+
+	switch v := claimsMap["uid"].(type) {
+	case float64:
+		return int64(v), nil
+	case int64:
+		return v, nil
+	case int:
+		return int64(v), nil
+	default:
+		return 0, i18n.NewError("user_id_missing")
+	}
+}
+
+// getUsernameFromClaims extracts the username from the claims set by the auth middleware
+func getUsernameFromClaims(c *gin.Context) (string, error) {
+	claims, exists := c.Get("claims")
+	if !exists {
+		return "", i18n.NewError("user_not_authenticated")
+	}
+
+	claimsMap, ok := claims.(jwt.MapClaims)
+	if !ok {
+		return "", errors.New("invalid claims")
+	}
+
+	username, ok := claimsMap["username"].(string)
+	if !ok || username == "" {
+		return "", i18n.NewError("caldav_username_missing")
+	}
+	return username, nil
+}
@@ -0,0 +1,339 @@
+package calendar
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"backend/root/internal/i18n"
+	"backend/root/internal/tasks"
+)
+
+// productID identifies this application as the creator of the calendars it writes
+const productID = "-//Proyecto 0//Tasks//EN"
+
+// uidDomain makes the UIDs of tasks globally unique, as RFC 5545 requires
+const uidDomain = "tasks.proyecto-0"
+
+// refreshInterval is how often calendar apps are asked to fetch a feed again
+const refreshInterval = "PT1H"
+
+// maxLineOctets is the length above which content lines are folded
+const maxLineOctets = 75
+
+// To-do statuses, which follow the state class of a task
+const (
+	statusNeedsAction = "NEEDS-ACTION" // Not Started
+	statusInProcess   = "IN-PROCESS"   // any other open state
+	statusCompleted   = "COMPLETED"    // any done state
+)
+
+// icalWriter writes the content lines of an iCalendar object (RFC 5545)
+type icalWriter struct {
+	buf bytes.Buffer
+}
+
+// line writes a property, folding it into lines of at most 75 octets that end with CRLF.
+// Continuation lines start with a space and never split a UTF-8 character.
+func (w *icalWriter) line(name string, value string) {
+	content := name + ":" + value
+	limit := maxLineOctets
+	for len(content) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(content[cut]) {
+			cut--
+		}
+		w.buf.WriteString(content[:cut])
+		w.buf.WriteString("\r\n ")
+		content = content[cut:]
+		limit = maxLineOctets - 1
+	}
+	w.buf.WriteString(content)
+	w.buf.WriteString("\r\n")
+}
+
+// text writes a property holding free text
+func (w *icalWriter) text(name string, value string) {
+	w.line(name, escapeText(value))
+}
+
+// Bytes returns what has been written so far
+func (w *icalWriter) Bytes() []byte {
+	return w.buf.Bytes()
+}
+
+// writeCalendar writes tasks with an end date as a calendar named name. stamp is the
+// time the calendar is generated at.
+func writeCalendar(name string, list []*tasks.TaskResponse, component string, stamp time.Time) []byte {
+	w := &icalWriter{}
+	w.line("BEGIN", "VCALENDAR")
+	w.line("VERSION", "2.0")
+	w.line("PRODID", productID)
+	w.line("CALSCALE", "GREGORIAN")
+	w.line("METHOD", "PUBLISH")
+	w.text("X-WR-CALNAME", name)
+	w.line("X-PUBLISHED-TTL", refreshInterval)
+	w.line("REFRESH-INTERVAL;VALUE=DURATION", refreshInterval)
+
+	for _, task := range list {
+		if task.EndDate != nil {
+			writeTask(w, task, taskUID(task.ID), component, stamp)
+		}
+	}
+
+	w.line("END", "VCALENDAR")
+	return w.Bytes()
+}
+
+// writeObject writes a task as the calendar object resource of a CalDAV collection: a calendar
+// holding a single to-do. Its content only depends on the task, so the task version is its ETag.
+func writeObject(object *Object) []byte {
+	w := &icalWriter{}
+	w.line("BEGIN", "VCALENDAR")
+	w.line("VERSION", "2.0")
+	w.line("PRODID", productID)
+	writeTask(w, object.Task, object.UID, ComponentTodo, object.Task.CreationDate)
+	w.line("END", "VCALENDAR")
+	return w.Bytes()
+}
+
+// writeTask writes a task as an all-day event or a to-do on its end date. Tasks without an
+// end date can only be written as to-dos.
+func writeTask(w *icalWriter, task *tasks.TaskResponse, uid string, component string, stamp time.Time) {
+	name := strings.ToUpper(component)
+	status := todoStatus(task)
+
+	w.line("BEGIN", name)
+	w.line("UID", uid)
+	w.line("DTSTAMP", formatTime(stamp))
+	w.line("CREATED", formatTime(task.CreationDate))
+	w.line("SEQUENCE", strconv.FormatInt(task.Version, 10))
+	w.text("SUMMARY", task.TaskText)
+
+	if component == ComponentTodo {
+		if task.EndDate != nil {
+			w.line("DUE;VALUE=DATE", formatDate(*task.EndDate))
+		}
+		w.line("STATUS", status)
+		if task.CompletedAt != nil {
+			w.line("COMPLETED", formatTime(*task.CompletedAt))
+		}
+		if status == statusCompleted {
+			w.line("PERCENT-COMPLETE", "100")
+		}
+	} else {
+		w.line("DTSTART;VALUE=DATE", formatDate(*task.EndDate))
+		w.line("DTEND;VALUE=DATE", formatDate(task.EndDate.AddDate(0, 0, 1)))
+		w.line("TRANSP", "TRANSPARENT") // Due tasks do not make the user busy
+		w.line("STATUS", eventStatus(status))
+	}
+
+	if priority := icalPriority(task.Priority); priority != 0 {
+		w.line("PRIORITY", strconv.Itoa(priority))
+	}
+	if task.Category != nil {
+		w.text("CATEGORIES", task.Category.Name)
+	}
+
+	w.line("END", name)
+}
+
+// todoStatus maps the state of a task to the status of a to-do. Tasks in a custom
+// state that is not done count as being worked on.
+func todoStatus(task *tasks.TaskResponse) string {
+	switch {
+	case task.State != nil && task.State.IsDone:
+		return statusCompleted
+	case task.State == nil || task.State.ID == tasks.StateNotStarted:
+		return statusNeedsAction
+	default:
+		return statusInProcess
+	}
+}
+
+// eventStatus maps the status of a to-do to the closest event status: tasks nobody
+// started yet are tentative, the others confirmed
+func eventStatus(todo string) string {
+	if todo == statusNeedsAction {
+		return "TENTATIVE"
+	}
+	return "CONFIRMED"
+}
+
+// icalPriority maps a task priority to the iCalendar scale, where 1 is the highest
+// and 9 the lowest priority; 0 means undefined
+func icalPriority(priority int) int {
+	switch priority {
+	case tasks.PriorityHigh:
+		return 1
+	case tasks.PriorityMedium:
+		return 5
+	case tasks.PriorityLow:
+		return 9
+	default:
+		return 0
+	}
+}
+
+// taskUID returns the globally unique identifier of a task in calendars
+func taskUID(id int64) string {
+	return "task-" + strconv.FormatInt(id, 10) + "@" + uidDomain
+}
+
+// formatDate writes a DATE value
+func formatDate(t time.Time) string {
+	return t.Format("20060102")
+}
+
+// formatTime writes a DATE-TIME value in UTC
+func formatTime(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+// escapeText escapes a TEXT value: backslashes, semicolons, commas and line breaks
+func escapeText(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`)
+	return replacer.Replace(value)
+}
+
+// todo holds the properties of a to-do sent by a calendar client that map onto a task
+type todo struct {
+	UID      string
+	Summary  string
+	Due      string // YYYY-MM-DD, "" without a due date
+	Priority int    // task priority
+	Status   string // statusNeedsAction, statusInProcess or statusCompleted
+}
+
+// errInvalidObject is returned for a body that is not a calendar holding a to-do
+var errInvalidObject = i18n.NewError("caldav_object_invalid")
+
+// parseTodo reads the first to-do of a calendar object. Properties without a task field, and
+// nested components such as alarms, are ignored. A cancelled to-do counts as completed.
+func parseTodo(data []byte) (*todo, error) {
+	unfolded := strings.NewReplacer("\r\n ", "", "\r\n\t", "", "\n ", "", "\n\t", "").Replace(string(data))
+
+	var components []string
+	var result *todo
+	calendar, other, read := false, false, false
+	for _, line := range strings.Split(unfolded, "\n") {
+		name, value, ok := splitContentLine(strings.TrimRight(line, "\r"))
+		if !ok {
+			continue
+		}
+
+		switch name {
+		case "BEGIN":
+			component := strings.ToUpper(value)
+			switch {
+			case component == "VCALENDAR" && len(components) == 0:
+				calendar = true
+			case component == "VTODO" && len(components) == 1 && result == nil:
+				result = &todo{Status: statusNeedsAction}
+			case component == "VEVENT" || component == "VJOURNAL":
+				other = true
+			}
+			components = append(components, component)
+			continue
+		case "END":
+			if len(components) == 2 && components[1] == "VTODO" {
+				read = true
+			}
+			if len(components) > 0 {
+				components = components[:len(components)-1]
+			}
+			continue
+		}
+
+		// Only read the properties of the first to-do itself
+		if result == nil || read || len(components) != 2 {
+			continue
+		}
+		switch name {
+		case "UID":
+			result.UID = value
+		case "SUMMARY":
+			result.Summary = unescapeText(value)
+		case "DUE":
+			due, err := time.Parse("20060102", value[:min(len(value), 8)])
+			if err != nil {
+				return nil, errInvalidObject
+			}
+			result.Due = due.Format("2006-01-02")
+		case "PRIORITY":
+			priority, _ := strconv.Atoi(value)
+			result.Priority = taskPriority(priority)
+		case "STATUS":
+			switch strings.ToUpper(value) {
+			case statusInProcess:
+				result.Status = statusInProcess
+			case statusCompleted, "CANCELLED":
+				result.Status = statusCompleted
+			}
+		case "COMPLETED":
+			result.Status = statusCompleted
+		}
+	}
+
+	if !calendar || result == nil {
+		if other {
+			return nil, ErrUnsupportedComponent
+		}
+		return nil, errInvalidObject
+	}
+	return result, nil
+}
+
+// splitContentLine splits a content line into its upper-cased property name and its value,
+// dropping the parameters. Colons inside quoted parameter values do not end the name.
+func splitContentLine(line string) (name string, value string, ok bool) {
+	quoted := false
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case '"':
+			quoted = !quoted
+		case ':':
+			if !quoted {
+				name, _, _ = strings.Cut(line[:i], ";")
+				return strings.ToUpper(name), line[i+1:], name != ""
+			}
+		}
+	}
+	return "", "", false
+}
+
+// taskPriority maps an iCalendar priority to a task priority: 1-4 high, 5 medium,
+// 6-9 low and 0 none
+func taskPriority(priority int) int {
+	switch {
+	case priority >= 1 && priority <= 4:
+		return tasks.PriorityHigh
+	case priority == 5:
+		return tasks.PriorityMedium
+	case priority >= 6 && priority <= 9:
+		return tasks.PriorityLow
+	default:
+		return tasks.PriorityNone
+	}
+}
+
+// unescapeText reverses escapeText
+func unescapeText(value string) string {
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] != '\\' || i == len(value)-1 {
+			b.WriteByte(value[i])
+			continue
+		}
+		i++
+		switch value[i] {
+		case 'n', 'N':
+			b.WriteByte('\n')
+		default:
+			b.WriteByte(value[i])
+		}
+	}
+	return b.String()
+}
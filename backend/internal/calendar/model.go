@@ -0,0 +1,98 @@
+package calendar
+
+import (
+	"time"
+
+	"backend/root/internal/i18n"
+	"backend/root/internal/tasks"
+)
+
+// Feed is the secret address of a user's iCalendar feed. Anyone holding the token can
+// read the feed, so it is only shown to its owner and can be replaced at any time.
+type Feed struct {
+	Token     string    `json:"token"`
+	URL       string    `json:"url"` // Address to subscribe to from calendar apps
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Feed errors
+var (
+	ErrFeedNotFound     = i18n.NewError("calendar_feed_not_found")
+	ErrInvalidComponent = i18n.NewError("calendar_component_invalid")
+)
+
+// Owner is the user a feed token belongs to
+type Owner struct {
+	UserID   int64
+	Username string
+}
+
+// FeedFilter narrows down the tasks listed in a feed
+type FeedFilter struct {
+	CategoryIDs []int64 // only tasks in one of these categories, all tasks when empty
+	Component   string  // ComponentEvent (default) or ComponentTodo
+	OnlyOpen    bool    // exclude completed tasks
+}
+
+// Calendar components a task can be exported as
+const (
+	ComponentEvent = "vevent" // all-day event on the end date, shown by every calendar app
+	ComponentTodo  = "vtodo"  // to-do due on the end date, with its completion status
+)
+
+// FeedExtension ends the path of every feed, as calendar apps expect
+const FeedExtension = ".ics"
+
+// Collection is a CalDAV calendar holding the to-dos of one category, or of the
+// tasks without a category
+type Collection struct {
+	Name        string // Path segment of the collection
+	CategoryID  *int64 // nil for the tasks without a category
+	DisplayName string
+	Description string
+}
+
+// Object is a task exposed as a CalDAV calendar object resource
+type Object struct {
+	Name string // Path segment of the resource, ending in .ics
+	UID  string
+	Task *tasks.TaskResponse
+}
+
+// Resource is the name and UID a calendar client chose for a task it created. Other
+// tasks are addressed by DefaultObjectName and taskUID.
+type Resource struct {
+	TaskID int64
+	Name   string
+	UID    string
+}
+
+// PutObjectRequest is the body and preconditions of a CalDAV PUT
+type PutObjectRequest struct {
+	Data        []byte
+	IfMatch     *int64 // version the client last saw, from the If-Match header
+	IfNoneMatch bool   // If-None-Match: * only allows creating a new resource
+}
+
+// Changes lists what changed in a collection since a sync token
+type Changes struct {
+	Objects   []*Object // created or changed resources
+	Removed   []string  // names of resources that are gone from the collection
+	SyncToken string    // token to send in the next sync request
+}
+
+// DefaultCollection holds the tasks without a category
+const DefaultCollection = "tasks"
+
+// categoryCollectionPrefix starts the name of the collection of a category, followed by its ID
+const categoryCollectionPrefix = "category-"
+
+// Calendar errors
+var (
+	ErrCollectionNotFound   = i18n.NewError("caldav_collection_not_found")
+	ErrObjectNotFound       = i18n.NewError("caldav_object_not_found")
+	ErrObjectExists         = i18n.NewError("caldav_object_exists")
+	ErrOtherCollection      = i18n.NewError("caldav_other_collection")
+	ErrUnsupportedComponent = i18n.NewError("caldav_component_unsupported")
+	ErrInvalidSyncToken     = i18n.NewError("caldav_sync_token_invalid")
+)
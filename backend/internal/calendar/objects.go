@@ -0,0 +1,402 @@
+package calendar
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+
+	"backend/root/internal/i18n"
+	"backend/root/internal/tasks"
+)
+
+// CalDAV exposes the personal tasks of a user as to-dos. Each category is a calendar
+// collection and each task a calendar object resource in the collection of its category.
+// Reads and writes go through the task service, so calendar clients are held to the same
+// rules as the API, and every write they make shows up in the task history.
+
+// syncTokenPrefix makes sync tokens URIs, as RFC 6578 requires. The ID of the latest
+// event in the history of the user's tasks follows it.
+const syncTokenPrefix = "http://" + uidDomain + "/sync/"
+
+// DefaultObjectName returns the resource name of a task that was not created by a calendar client
+func DefaultObjectName(taskID int64) string {
+	return "task-" + strconv.FormatInt(taskID, 10) + FeedExtension
+}
+
+// GetCollections lists the calendars of the request scope: the tasks without a category,
+// then one calendar per category
+func (s *service) GetCollections(ctx context.Context) ([]*Collection, error) {
+	list, err := s.categories.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	collections := []*Collection{{Name: DefaultCollection, DisplayName: "Tasks", Description: "Tasks without a category"}}
+	for _, category := range list {
+		id := category.ID
+		collections = append(collections, &Collection{
+			Name:        categoryCollectionPrefix + strconv.FormatInt(id, 10),
+			CategoryID:  &id,
+			DisplayName: category.Name,
+			Description: category.Description,
+		})
+	}
+
+	return collections, nil
+}
+
+// GetCollection retrieves a calendar by name
+func (s *service) GetCollection(ctx context.Context, name string) (*Collection, error) {
+	collections, err := s.GetCollections(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, collection := range collections {
+		if collection.Name == name {
+			return collection, nil
+		}
+	}
+	return nil, ErrCollectionNotFound
+}
+
+// GetObjects lists the to-dos of a calendar
+func (s *service) GetObjects(ctx context.Context, userID int64, collection *Collection) ([]*Object, error) {
+	objects, _, err := s.listObjects(ctx, userID, collection)
+	return objects, err
+}
+
+// GetObject retrieves a to-do of a calendar by resource name
+func (s *service) GetObject(ctx context.Context, userID int64, collection *Collection, name string) (*Object, error) {
+	index, err := s.loadIndex(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	task, err := s.findTask(ctx, userID, index, name)
+	if err != nil {
+		return nil, err
+	}
+	if task == nil || !inCollection(task, collection) {
+		return nil, ErrObjectNotFound
+	}
+
+	return index.object(task), nil
+}
+
+// PutObject creates or replaces a to-do. The summary, due date, priority and status are
+// copied to the task; other properties are not kept. The second result is true when a
+// task was created.
+func (s *service) PutObject(ctx context.Context, userID int64, collection *Collection, name string, req PutObjectRequest) (*Object, bool, error) {
+	if !strings.HasSuffix(name, FeedExtension) || strings.Contains(name, "/") {
+		return nil, false, i18n.NewError("caldav_object_name_invalid")
+	}
+
+	parsed, err := parseTodo(req.Data)
+	if err != nil {
+		return nil, false, err
+	}
+
+	index, err := s.loadIndex(ctx, userID)
+	if err != nil {
+		return nil, false, err
+	}
+	task, err := s.findTask(ctx, userID, index, name)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if task == nil {
+		if req.IfMatch != nil {
+			return nil, false, tasks.ErrVersionMismatch
+		}
+		object, err := s.createObject(ctx, userID, collection, name, parsed)
+		if err != nil {
+			return nil, false, err
+		}
+		return object, true, nil
+	}
+
+	if !inCollection(task, collection) {
+		return nil, false, ErrOtherCollection
+	}
+	if req.IfNoneMatch {
+		return nil, false, ErrObjectExists
+	}
+	if req.IfMatch != nil && *req.IfMatch != task.Version {
+		return nil, false, tasks.ErrVersionMismatch
+	}
+
+	if task.TaskText != parsed.Summary || endDate(task) != parsed.Due || task.Priority != parsed.Priority {
+		task, err = s.tasks.Update(ctx, task.ID, userID, tasks.UpdateTaskRequest{
+			TaskText:   parsed.Summary,
+			CategoryID: collection.CategoryID,
+			EndDate:    parsed.Due,
+			ParentID:   task.ParentID,
+			Priority:   &parsed.Priority,
+			IfMatch:    &task.Version,
+		})
+		if err != nil {
+			return nil, false, err
+		}
+	}
+
+	task, err = s.changeStatus(ctx, userID, task, parsed.Status)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return index.object(task), false, nil
+}
+
+// DeleteObject moves the task of a to-do to the trash
+func (s *service) DeleteObject(ctx context.Context, userID int64, collection *Collection, name string, ifMatch *int64) error {
+	object, err := s.GetObject(ctx, userID, collection, name)
+	if err != nil {
+		return err
+	}
+
+	return s.tasks.Delete(ctx, object.Task.ID, userID, ifMatch)
+}
+
+// GetSyncToken returns the token identifying the current state of all the user's calendars
+func (s *service) GetSyncToken(ctx context.Context, userID int64) (string, error) {
+	last, err := s.repo.GetLastChange(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+
+	return syncTokenPrefix + strconv.FormatInt(last, 10), nil
+}
+
+// GetChanges lists the to-dos of a calendar that changed or went away since a sync token,
+// or all of them without a token. Tasks that changed but were never in the calendar may be
+// reported as removed, which clients ignore.
+func (s *service) GetChanges(ctx context.Context, userID int64, collection *Collection, token string) (*Changes, error) {
+	// Read the token before the tasks, so changes made meanwhile are sent again next time
+	last, err := s.repo.GetLastChange(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var since int64
+	if token != "" {
+		since, err = strconv.ParseInt(strings.TrimPrefix(token, syncTokenPrefix), 10, 64)
+		if err != nil || !strings.HasPrefix(token, syncTokenPrefix) || since < 0 || since > last {
+			return nil, ErrInvalidSyncToken
+		}
+	}
+
+	objects, index, err := s.listObjects(ctx, userID, collection)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := &Changes{Objects: []*Object{}, Removed: []string{}, SyncToken: syncTokenPrefix + strconv.FormatInt(last, 10)}
+	if token == "" {
+		changes.Objects = objects
+		return changes, nil
+	}
+
+	changed, err := s.repo.GetChangedTasks(ctx, userID, since)
+	if err != nil {
+		return nil, err
+	}
+
+	byTask := make(map[int64]*Object, len(objects))
+	for _, object := range objects {
+		byTask[object.Task.ID] = object
+	}
+	for _, id := range changed {
+		if object, ok := byTask[id]; ok {
+			changes.Objects = append(changes.Objects, object)
+		} else {
+			changes.Removed = append(changes.Removed, index.name(id))
+		}
+	}
+
+	return changes, nil
+}
+
+// listObjects lists the to-dos of a calendar together with the index used to name them
+func (s *service) listObjects(ctx context.Context, userID int64, collection *Collection) ([]*Object, *objectIndex, error) {
+	list, err := s.tasks.GetAllByUser(ctx, userID, tasks.TaskFilter{CategoryID: collection.CategoryID})
+	if err != nil {
+		return nil, nil, err
+	}
+	index, err := s.loadIndex(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	objects := make([]*Object, 0, len(list))
+	for _, task := range list {
+		if task.UserID == userID && inCollection(task, collection) {
+			objects = append(objects, index.object(task))
+		}
+	}
+
+	return objects, index, nil
+}
+
+// createObject creates the task of a new to-do and remembers the name and UID the client chose
+func (s *service) createObject(ctx context.Context, userID int64, collection *Collection, name string, parsed *todo) (*Object, error) {
+	task, err := s.tasks.Create(ctx, userID, tasks.CreateTaskRequest{
+		TaskText:   parsed.Summary,
+		EndDate:    parsed.Due,
+		CategoryID: collection.CategoryID,
+		Priority:   &parsed.Priority,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resource := &Resource{TaskID: task.ID, Name: name, UID: parsed.UID}
+	if resource.UID == "" {
+		resource.UID = taskUID(task.ID)
+	}
+	if resource.Name != DefaultObjectName(task.ID) || resource.UID != taskUID(task.ID) {
+		if err := s.repo.SaveResource(ctx, userID, resource); err != nil {
+			return nil, err
+		}
+	}
+
+	task, err = s.changeStatus(ctx, userID, task, parsed.Status)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Object{Name: resource.Name, UID: resource.UID, Task: task}, nil
+}
+
+// changeStatus runs the transitions that give a task the state class of a to-do status
+func (s *service) changeStatus(ctx context.Context, userID int64, task *tasks.TaskResponse, status string) (*tasks.TaskResponse, error) {
+	for _, action := range statusActions(todoStatus(task), status) {
+		var err error
+		task, err = s.tasks.Transition(ctx, task.ID, userID, tasks.TransitionRequest{Action: action, IfMatch: &task.Version})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return task, nil
+}
+
+// statusActions lists the transitions that bring a task from one to-do status to another
+func statusActions(from string, to string) []string {
+	switch {
+	case from == to:
+		return nil
+	case to == statusCompleted:
+		return []string{tasks.ActionComplete}
+	case to == statusInProcess && from == statusCompleted:
+		return []string{tasks.ActionReopen, tasks.ActionStart}
+	case to == statusInProcess:
+		return []string{tasks.ActionStart}
+	case from == statusCompleted:
+		return []string{tasks.ActionReopen}
+	default:
+		return []string{tasks.ActionReset}
+	}
+}
+
+// findTask returns the live personal task of the user a resource name stands for, nil if there is none
+func (s *service) findTask(ctx context.Context, userID int64, index *objectIndex, name string) (*tasks.TaskResponse, error) {
+	taskID, ok := index.taskID(name)
+	if !ok {
+		return nil, nil
+	}
+
+	task, err := s.tasks.GetByID(ctx, taskID, userID)
+	if err != nil {
+		if errors.Is(err, tasks.ErrTaskNotFound) || errors.Is(err, tasks.ErrPermissionDenied) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if task.UserID != userID || task.WorkspaceID != nil {
+		return nil, nil
+	}
+
+	return task, nil
+}
+
+// objectIndex resolves the resource names of a user's tasks in both directions
+type objectIndex struct {
+	byName map[string]*Resource
+	byTask map[int64]*Resource
+}
+
+// loadIndex loads the names and UIDs clients chose for the tasks of a user
+func (s *service) loadIndex(ctx context.Context, userID int64) (*objectIndex, error) {
+	resources, err := s.repo.GetResources(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	index := &objectIndex{
+		byName: make(map[string]*Resource, len(resources)),
+		byTask: make(map[int64]*Resource, len(resources)),
+	}
+	for _, resource := range resources {
+		index.byName[resource.Name] = resource
+		index.byTask[resource.TaskID] = resource
+	}
+
+	return index, nil
+}
+
+// taskID returns the task a resource name stands for. A task a client named is only
+// reachable under that name.
+func (i *objectIndex) taskID(name string) (int64, bool) {
+	if resource, ok := i.byName[name]; ok {
+		return resource.TaskID, true
+	}
+
+	digits, ok := strings.CutPrefix(strings.TrimSuffix(name, FeedExtension), "task-")
+	if !ok || !strings.HasSuffix(name, FeedExtension) {
+		return 0, false
+	}
+	id, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil || id <= 0 || i.byTask[id] != nil {
+		return 0, false
+	}
+
+	return id, true
+}
+
+// name returns the resource name of a task
+func (i *objectIndex) name(taskID int64) string {
+	if resource, ok := i.byTask[taskID]; ok {
+		return resource.Name
+	}
+	return DefaultObjectName(taskID)
+}
+
+// object exposes a task as a calendar object resource
+func (i *objectIndex) object(task *tasks.TaskResponse) *Object {
+	if resource, ok := i.byTask[task.ID]; ok {
+		return &Object{Name: resource.Name, UID: resource.UID, Task: task}
+	}
+	return &Object{Name: DefaultObjectName(task.ID), UID: taskUID(task.ID), Task: task}
+}
+
+// inCollection reports whether a task belongs in a calendar
+func inCollection(task *tasks.TaskResponse, collection *Collection) bool {
+	if task.WorkspaceID != nil {
+		return false
+	}
+	if task.Category == nil || collection.CategoryID == nil {
+		return task.Category == nil && collection.CategoryID == nil
+	}
+	return task.Category.ID == *collection.CategoryID
+}
+
+// endDate returns the end date of a task as YYYY-MM-DD, "" without one
+func endDate(task *tasks.TaskResponse) string {
+	if task.EndDate == nil {
+		return ""
+	}
+	return task.EndDate.Format("2006-01-02")
+}
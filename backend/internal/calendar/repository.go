@@ -0,0 +1,145 @@
+package calendar
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// Repository defines the interface for calendar feed and CalDAV persistence
+type Repository interface {
+	GetByUser(ctx context.Context, userID int64) (*Feed, error)
+	Save(ctx context.Context, userID int64, token string) (*Feed, error)
+	GetOwner(ctx context.Context, token string) (*Owner, error)
+	GetResources(ctx context.Context, userID int64) ([]*Resource, error)
+	SaveResource(ctx context.Context, userID int64, resource *Resource) error
+	GetLastChange(ctx context.Context, userID int64) (int64, error)
+	GetChangedTasks(ctx context.Context, userID int64, since int64) ([]int64, error)
+}
+
+// PostgresRepository implements Repository using PostgreSQL
+type PostgresRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresRepository creates a new PostgreSQL calendar feed repository
+func NewPostgresRepository(db *sql.DB) *PostgresRepository {
+	return &PostgresRepository{db: db}
+}
+
+// GetByUser retrieves the feed of a user
+func (r *PostgresRepository) GetByUser(ctx context.Context, userID int64) (*Feed, error) {
+	var feed Feed
+	err := r.db.QueryRowContext(ctx, `SELECT token, created_at FROM calendar_feeds WHERE user_id = $1`, userID).
+		Scan(&feed.Token, &feed.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrFeedNotFound
+		}
+		return nil, fmt.Errorf("failed to get calendar feed: %w", err)
+	}
+
+	return &feed, nil
+}
+
+// Save stores the token of a user's feed, replacing the previous one
+func (r *PostgresRepository) Save(ctx context.Context, userID int64, token string) (*Feed, error) {
+	query := `
+		INSERT INTO calendar_feeds (user_id, token)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE SET token = EXCLUDED.token, created_at = NOW()
+		RETURNING token, created_at`
+
+	var feed Feed
+	if err := r.db.QueryRowContext(ctx, query, userID, token).Scan(&feed.Token, &feed.CreatedAt); err != nil {
+		return nil, fmt.Errorf("failed to save calendar feed: %w", err)
+	}
+
+	return &feed, nil
+}
+
+// GetOwner retrieves the user a feed token belongs to
+func (r *PostgresRepository) GetOwner(ctx context.Context, token string) (*Owner, error) {
+	query := `
+		SELECT u.id, u.username
+		FROM calendar_feeds cf
+		JOIN users u ON u.id = cf.user_id
+		WHERE cf.token = $1`
+
+	var owner Owner
+	if err := r.db.QueryRowContext(ctx, query, token).Scan(&owner.UserID, &owner.Username); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrFeedNotFound
+		}
+		return nil, fmt.Errorf("failed to get calendar feed: %w", err)
+	}
+
+	return &owner, nil
+}
+
+// GetResources retrieves the names and UIDs clients chose for the tasks of a user
+func (r *PostgresRepository) GetResources(ctx context.Context, userID int64) ([]*Resource, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT task_id, name, uid FROM calendar_objects WHERE user_id = $1`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get calendar objects: %w", err)
+	}
+	defer rows.Close()
+
+	var resources []*Resource
+	for rows.Next() {
+		var resource Resource
+		if err := rows.Scan(&resource.TaskID, &resource.Name, &resource.UID); err != nil {
+			return nil, fmt.Errorf("failed to scan calendar object: %w", err)
+		}
+		resources = append(resources, &resource)
+	}
+
+	return resources, rows.Err()
+}
+
+// SaveResource stores the name and UID a client chose for a task
+func (r *PostgresRepository) SaveResource(ctx context.Context, userID int64, resource *Resource) error {
+	query := `
+		INSERT INTO calendar_objects (task_id, user_id, name, uid)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (task_id) DO UPDATE SET name = EXCLUDED.name, uid = EXCLUDED.uid`
+
+	if _, err := r.db.ExecContext(ctx, query, resource.TaskID, userID, resource.Name, resource.UID); err != nil {
+		return fmt.Errorf("failed to save calendar object: %w", err)
+	}
+
+	return nil
+}
+
+// GetLastChange returns the ID of the latest event in the history of a user's tasks, 0 when there is none.
+// Every write to a task records an event, so it identifies the state of all the user's tasks.
+func (r *PostgresRepository) GetLastChange(ctx context.Context, userID int64) (int64, error) {
+	var id int64
+	err := r.db.QueryRowContext(ctx, `SELECT COALESCE(MAX(id), 0) FROM task_events WHERE user_id = $1`, userID).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get last change: %w", err)
+	}
+
+	return id, nil
+}
+
+// GetChangedTasks returns the IDs of the user's tasks with events after the given one,
+// including tasks that were deleted since
+func (r *PostgresRepository) GetChangedTasks(ctx context.Context, userID int64, since int64) ([]int64, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT DISTINCT task_id FROM task_events WHERE user_id = $1 AND id > $2`, userID, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get changed tasks: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan changed task: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
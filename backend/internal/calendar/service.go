@@ -0,0 +1,139 @@
+package calendar
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"backend/root/internal/categories"
+	"backend/root/internal/i18n"
+	"backend/root/internal/tasks"
+	"backend/root/internal/workspaces"
+)
+
+// Service defines the interface for calendar feed and CalDAV business logic
+type Service interface {
+	GetFeed(ctx context.Context, userID int64) (*Feed, error)
+	RegenerateToken(ctx context.Context, userID int64) (*Feed, error)
+	Render(ctx context.Context, token string, filter FeedFilter) ([]byte, error)
+	GetCollections(ctx context.Context) ([]*Collection, error)
+	GetCollection(ctx context.Context, name string) (*Collection, error)
+	GetObjects(ctx context.Context, userID int64, collection *Collection) ([]*Object, error)
+	GetObject(ctx context.Context, userID int64, collection *Collection, name string) (*Object, error)
+	PutObject(ctx context.Context, userID int64, collection *Collection, name string, req PutObjectRequest) (*Object, bool, error)
+	DeleteObject(ctx context.Context, userID int64, collection *Collection, name string, ifMatch *int64) error
+	GetSyncToken(ctx context.Context, userID int64) (string, error)
+	GetChanges(ctx context.Context, userID int64, collection *Collection, token string) (*Changes, error)
+}
+
+// TaskService defines the task operations feeds and CalDAV collections are mapped onto
+type TaskService interface {
+	GetAllByUser(ctx context.Context, userID int64, filter tasks.TaskFilter) ([]*tasks.TaskResponse, error)
+	GetByID(ctx context.Context, taskID int64, userID int64) (*tasks.TaskResponse, error)
+	Create(ctx context.Context, userID int64, req tasks.CreateTaskRequest) (*tasks.TaskResponse, error)
+	Update(ctx context.Context, taskID int64, userID int64, req tasks.UpdateTaskRequest) (*tasks.TaskResponse, error)
+	Transition(ctx context.Context, taskID int64, userID int64, req tasks.TransitionRequest) (*tasks.TaskResponse, error)
+	Delete(ctx context.Context, taskID int64, userID int64, ifMatch *int64) error
+}
+
+// CategoryLister defines the minimal interface needed to list the categories shown as calendars
+type CategoryLister interface {
+	GetAll(ctx context.Context) ([]*categories.Category, error)
+}
+
+// service implements the Service interface
+type service struct {
+	repo       Repository
+	tasks      TaskService
+	categories CategoryLister
+}
+
+// NewService creates a new calendar service
+func NewService(repo Repository, tasks TaskService, categories CategoryLister) Service {
+	return &service{
+		repo:       repo,
+		tasks:      tasks,
+		categories: categories,
+	}
+}
+
+// GetFeed retrieves the feed of a user, creating its token on first use
+func (s *service) GetFeed(ctx context.Context, userID int64) (*Feed, error) {
+	feed, err := s.repo.GetByUser(ctx, userID)
+	if err == nil {
+		return feed, nil
+	}
+	if !errors.Is(err, ErrFeedNotFound) {
+		return nil, err
+	}
+
+	return s.RegenerateToken(ctx, userID)
+}
+
+// RegenerateToken gives the feed of a user a new token; the previous address stops working
+func (s *service) RegenerateToken(ctx context.Context, userID int64) (*Feed, error) {
+	token, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.Save(ctx, userID, token)
+}
+
+// Render writes the feed a token gives access to as an iCalendar file. Feeds list the
+// owner's personal tasks that have an end date.
+func (s *service) Render(ctx context.Context, token string, filter FeedFilter) ([]byte, error) {
+	if filter.Component == "" {
+		filter.Component = ComponentEvent
+	}
+	if filter.Component != ComponentEvent && filter.Component != ComponentTodo {
+		return nil, ErrInvalidComponent
+	}
+
+	owner, err := s.repo.GetOwner(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	// Feeds are read without a session, so the request carries no tenant scope yet
+	ctx = workspaces.WithScope(ctx, workspaces.Scope{})
+	list, err := s.tasks.GetAllByUser(ctx, owner.UserID, tasks.TaskFilter{OnlyOpen: filter.OnlyOpen, Sort: tasks.SortEndDate})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(filter.CategoryIDs) > 0 {
+		list = filterByCategory(list, filter.CategoryIDs)
+	}
+
+	return writeCalendar("Tasks of "+owner.Username, list, filter.Component, time.Now()), nil
+}
+
+// filterByCategory keeps the tasks in one of the given categories
+func filterByCategory(list []*tasks.TaskResponse, categoryIDs []int64) []*tasks.TaskResponse {
+	wanted := make(map[int64]bool, len(categoryIDs))
+	for _, id := range categoryIDs {
+		wanted[id] = true
+	}
+
+	filtered := make([]*tasks.TaskResponse, 0, len(list))
+	for _, task := range list {
+		if task.Category != nil && wanted[task.Category.ID] {
+			filtered = append(filtered, task)
+		}
+	}
+
+	return filtered
+}
+
+// newToken returns a random secret for a feed address
+func newToken() (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", i18n.NewError("calendar_feed_create_failed")
+	}
+
+	return hex.EncodeToString(buf), nil
+}
@@ -1,12 +1,20 @@
 package categories
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+
+	"backend/root/internal/etag"
+	"backend/root/internal/i18n"
+	"backend/root/internal/workspaces"
 )
 
+// errInvalidFormat is returned when the body of a request is not valid JSON for a category
+var errInvalidFormat = i18n.NewError("request_format_invalid")
+
 // Handler handles HTTP requests for categories
 type Handler struct {
 	service Service
@@ -23,13 +31,18 @@ func NewHandler(service Service) *Handler {
 func (h *Handler) Create(c *gin.Context) {
 	var req CreateCategoryRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format", "details": err.Error()})
+		c.Error(errInvalidFormat)
+		c.JSON(http.StatusBadRequest, gin.H{"error": errInvalidFormat.Error(), "details": err.Error()})
 		return
 	}
 
 	category, err := h.service.Create(c.Request.Context(), req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		if errors.Is(err, workspaces.ErrReadOnly) {
+			i18n.RespondError(c, http.StatusForbidden, err)
+			return
+		}
+		i18n.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -44,17 +57,24 @@ func (h *Handler) GetByID(c *gin.Context) {
 	idParam := c.Param("id")
 	id, err := strconv.ParseInt(idParam, 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category ID"})
+		i18n.RespondError(c, http.StatusBadRequest, i18n.NewError("category_id_invalid_title"))
 		return
 	}
 
 	category, err := h.service.GetByID(c.Request.Context(), id)
 	if err != nil {
-		if err.Error() == "category not found" {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Category not found"})
+		if errors.Is(err, ErrCategoryNotFound) {
+			i18n.RespondError(c, http.StatusNotFound, i18n.NewError("category_not_found_title"))
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve category"})
+		i18n.RespondError(c, http.StatusInternalServerError, i18n.NewError("category_get_failed"))
+		return
+	}
+
+	// Let clients revalidate their cached copy with If-None-Match
+	c.Header("ETag", etag.Format(category.Version))
+	if etag.Matches(c.GetHeader("If-None-Match"), etag.Format(category.Version)) {
+		c.Status(http.StatusNotModified)
 		return
 	}
 
@@ -67,7 +87,7 @@ func (h *Handler) GetByID(c *gin.Context) {
 func (h *Handler) GetAll(c *gin.Context) {
 	categories, err := h.service.GetAll(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve categories"})
+		i18n.RespondError(c, http.StatusInternalServerError, i18n.NewError("categories_get_failed"))
 		return
 	}
 
@@ -88,26 +108,42 @@ func (h *Handler) Update(c *gin.Context) {
 	idParam := c.Param("id")
 	id, err := strconv.ParseInt(idParam, 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category ID"})
+		i18n.RespondError(c, http.StatusBadRequest, i18n.NewError("category_id_invalid_title"))
 		return
 	}
 
 	var req UpdateCategoryRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format", "details": err.Error()})
+		c.Error(errInvalidFormat)
+		c.JSON(http.StatusBadRequest, gin.H{"error": errInvalidFormat.Error(), "details": err.Error()})
+		return
+	}
+
+	req.IfMatch, err = etag.ParseIfMatch(c.GetHeader("If-Match"))
+	if err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 
 	category, err := h.service.Update(c.Request.Context(), id, req)
 	if err != nil {
-		if err.Error() == "category not found" {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Category not found"})
+		if errors.Is(err, workspaces.ErrReadOnly) {
+			i18n.RespondError(c, http.StatusForbidden, err)
 			return
 		}
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		if errors.Is(err, ErrCategoryNotFound) {
+			i18n.RespondError(c, http.StatusNotFound, i18n.NewError("category_not_found_title"))
+			return
+		}
+		if errors.Is(err, ErrVersionMismatch) {
+			i18n.RespondError(c, http.StatusPreconditionFailed, err)
+			return
+		}
+		i18n.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 
+	c.Header("ETag", etag.Format(category.Version))
 	c.JSON(http.StatusOK, gin.H{
 		"message":  "Category updated successfully",
 		"category": category.ToResponse(),
@@ -119,21 +155,64 @@ func (h *Handler) Delete(c *gin.Context) {
 	idParam := c.Param("id")
 	id, err := strconv.ParseInt(idParam, 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category ID"})
+		i18n.RespondError(c, http.StatusBadRequest, i18n.NewError("category_id_invalid_title"))
 		return
 	}
 
-	err = h.service.Delete(c.Request.Context(), id)
+	ifMatch, err := etag.ParseIfMatch(c.GetHeader("If-Match"))
 	if err != nil {
-		if err.Error() == "category not found" {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Category not found"})
+		i18n.RespondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	err = h.service.Delete(c.Request.Context(), id, ifMatch)
+	if err != nil {
+		if errors.Is(err, workspaces.ErrReadOnly) {
+			i18n.RespondError(c, http.StatusForbidden, err)
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete category"})
+		if errors.Is(err, ErrCategoryNotFound) {
+			i18n.RespondError(c, http.StatusNotFound, i18n.NewError("category_not_found_title"))
+			return
+		}
+		if errors.Is(err, ErrVersionMismatch) {
+			i18n.RespondError(c, http.StatusPreconditionFailed, err)
+			return
+		}
+		i18n.RespondError(c, http.StatusInternalServerError, i18n.NewError("category_delete_failed"))
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Category deleted successfully",
 	})
-}
\ No newline at end of file
+}
+
+// Restore handles POST /categories/:id/restore
+func (h *Handler) Restore(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := strconv.ParseInt(idParam, 10, 64)
+	if err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, i18n.NewError("category_id_invalid_title"))
+		return
+	}
+
+	category, err := h.service.Restore(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, workspaces.ErrReadOnly) {
+			i18n.RespondError(c, http.StatusForbidden, err)
+			return
+		}
+		if errors.Is(err, ErrNotInTrash) {
+			i18n.RespondError(c, http.StatusNotFound, i18n.NewError("category_not_in_trash_title"))
+			return
+		}
+		i18n.RespondError(c, http.StatusInternalServerError, i18n.NewError("category_restore_failed"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "Category restored successfully",
+		"category": category.ToResponse(),
+	})
+}
@@ -1,12 +1,29 @@
 package categories
 
+import (
+	"time"
+
+	"backend/root/internal/i18n"
+)
+
 // Category represents a task category
 type Category struct {
 	ID          int64  `json:"id" db:"id"`
 	Name        string `json:"name" db:"name"`
 	Description string `json:"description" db:"description"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty" db:"deleted_at"` // Only set for categories in the trash
+	Version     int64      `json:"version" db:"version"`                 // Incremented on every write, exposed as the ETag
 }
 
+// ErrVersionMismatch is returned when a write is based on an outdated version of a category
+var ErrVersionMismatch = i18n.NewError("category_version_mismatch")
+
+// ErrCategoryNotFound is returned when a category does not exist or is in the trash
+var ErrCategoryNotFound = i18n.NewError("category_not_found")
+
+// ErrNotInTrash is returned when restoring a category that is not in the trash
+var ErrNotInTrash = i18n.NewError("category_not_found_in_trash")
+
 // CreateCategoryRequest represents the request payload for creating a category
 type CreateCategoryRequest struct {
 	Name        string `json:"name" binding:"required,min=1,max=100"`
@@ -17,6 +34,7 @@ type CreateCategoryRequest struct {
 type UpdateCategoryRequest struct {
 	Name        string `json:"name" binding:"required,min=1,max=100"`
 	Description string `json:"description" binding:"max=500"`
+	IfMatch     *int64 `json:"-"` // Version the client last saw, taken from the If-Match header
 }
 
 // CategoryResponse represents the response format for category data
@@ -24,6 +42,8 @@ type CategoryResponse struct {
 	ID          int64  `json:"id"`
 	Name        string `json:"name"`
 	Description string `json:"description"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"`
+	Version     int64      `json:"version"`
 }
 
 // ToResponse converts a Category to CategoryResponse
@@ -32,5 +52,7 @@ func (c *Category) ToResponse() CategoryResponse {
 		ID:          c.ID,
 		Name:        c.Name,
 		Description: c.Description,
+		DeletedAt:   c.DeletedAt,
+		Version:     c.Version,
 	}
 }
\ No newline at end of file
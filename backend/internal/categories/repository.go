@@ -3,8 +3,10 @@ package categories
 import (
 	"context"
 	"database/sql"
-	"errors"
 	"fmt"
+	"time"
+
+	"backend/root/internal/workspaces"
 )
 
 // Repository defines the interface for category data operations
@@ -12,9 +14,12 @@ type Repository interface {
 	Create(ctx context.Context, name, description string) (*Category, error)
 	GetByID(ctx context.Context, id int64) (*Category, error)
 	GetAll(ctx context.Context) ([]*Category, error)
-	Update(ctx context.Context, id int64, name, description string) (*Category, error)
-	Delete(ctx context.Context, id int64) error
+	Update(ctx context.Context, id int64, name, description string, version *int64) (*Category, error)
+	Delete(ctx context.Context, id int64, version *int64) error
 	Exists(ctx context.Context, id int64) (bool, error)
+	GetTrash(ctx context.Context) ([]*Category, error)
+	Restore(ctx context.Context, id int64) (*Category, error)
+	Purge(ctx context.Context, before time.Time) (int64, error)
 }
 
 // PostgresRepository implements Repository using PostgreSQL
@@ -30,13 +35,13 @@ func NewPostgresRepository(db *sql.DB) *PostgresRepository {
 // Create creates a new category
 func (r *PostgresRepository) Create(ctx context.Context, name, description string) (*Category, error) {
 	query := `
-		INSERT INTO categories (name, description) 
-		VALUES ($1, $2) 
-		RETURNING id, name, description`
+		INSERT INTO categories (name, description, workspace_id) 
+		VALUES ($1, $2, $3) 
+		RETURNING id, name, description, version`
 	
 	var category Category
-	err := r.db.QueryRowContext(ctx, query, name, description).Scan(
-		&category.ID, &category.Name, &category.Description,
+	err := r.db.QueryRowContext(ctx, query, name, description, workspaces.IDFromContext(ctx)).Scan(
+		&category.ID, &category.Name, &category.Description, &category.Version,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create category: %w", err)
@@ -47,15 +52,15 @@ func (r *PostgresRepository) Create(ctx context.Context, name, description strin
 
 // GetByID retrieves a category by its ID
 func (r *PostgresRepository) GetByID(ctx context.Context, id int64) (*Category, error) {
-	query := `SELECT id, name, description FROM categories WHERE id = $1`
+	query := `SELECT id, name, description, version FROM categories WHERE id = $1 AND deleted_at IS NULL AND ` + workspaces.Condition(ctx, "workspace_id")
 	
 	var category Category
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&category.ID, &category.Name, &category.Description,
+		&category.ID, &category.Name, &category.Description, &category.Version,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, errors.New("category not found")
+			return nil, ErrCategoryNotFound
 		}
 		return nil, fmt.Errorf("failed to get category: %w", err)
 	}
@@ -65,7 +70,7 @@ func (r *PostgresRepository) GetByID(ctx context.Context, id int64) (*Category,
 
 // GetAll retrieves all categories
 func (r *PostgresRepository) GetAll(ctx context.Context) ([]*Category, error) {
-	query := `SELECT id, name, description FROM categories ORDER BY name`
+	query := `SELECT id, name, description, version FROM categories WHERE deleted_at IS NULL AND ` + workspaces.Condition(ctx, "workspace_id") + ` ORDER BY name`
 	
 	rows, err := r.db.QueryContext(ctx, query)
 	if err != nil {
@@ -76,7 +81,7 @@ func (r *PostgresRepository) GetAll(ctx context.Context) ([]*Category, error) {
 	var categories []*Category
 	for rows.Next() {
 		var category Category
-		err := rows.Scan(&category.ID, &category.Name, &category.Description)
+		err := rows.Scan(&category.ID, &category.Name, &category.Description, &category.Version)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan category: %w", err)
 		}
@@ -90,21 +95,25 @@ func (r *PostgresRepository) GetAll(ctx context.Context) ([]*Category, error) {
 	return categories, nil
 }
 
-// Update updates an existing category
-func (r *PostgresRepository) Update(ctx context.Context, id int64, name, description string) (*Category, error) {
+// Update updates an existing category. When version is set the category must still be
+// at that version, otherwise ErrVersionMismatch is returned.
+func (r *PostgresRepository) Update(ctx context.Context, id int64, name, description string, version *int64) (*Category, error) {
 	query := `
 		UPDATE categories 
-		SET name = $2, description = $3 
-		WHERE id = $1 
-		RETURNING id, name, description`
+		SET name = $2, description = $3, version = version + 1
+		WHERE id = $1 AND deleted_at IS NULL AND ($4::BIGINT IS NULL OR version = $4) AND ` + workspaces.Condition(ctx, "workspace_id") + `
+		RETURNING id, name, description, version`
 	
 	var category Category
-	err := r.db.QueryRowContext(ctx, query, id, name, description).Scan(
-		&category.ID, &category.Name, &category.Description,
+	err := r.db.QueryRowContext(ctx, query, id, name, description, version).Scan(
+		&category.ID, &category.Name, &category.Description, &category.Version,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, errors.New("category not found")
+			if version != nil {
+				return nil, ErrVersionMismatch
+			}
+			return nil, ErrCategoryNotFound
 		}
 		return nil, fmt.Errorf("failed to update category: %w", err)
 	}
@@ -112,11 +121,14 @@ func (r *PostgresRepository) Update(ctx context.Context, id int64, name, descrip
 	return &category, nil
 }
 
-// Delete removes a category by ID
-func (r *PostgresRepository) Delete(ctx context.Context, id int64) error {
-	query := `DELETE FROM categories WHERE id = $1`
+// Delete moves a category to the trash. When version is set the category must still be
+// at that version, otherwise ErrVersionMismatch is returned.
+func (r *PostgresRepository) Delete(ctx context.Context, id int64, version *int64) error {
+	query := `
+		UPDATE categories SET deleted_at = NOW(), version = version + 1
+		WHERE id = $1 AND deleted_at IS NULL AND ($2::BIGINT IS NULL OR version = $2) AND ` + workspaces.Condition(ctx, "workspace_id")
 	
-	result, err := r.db.ExecContext(ctx, query, id)
+	result, err := r.db.ExecContext(ctx, query, id, version)
 	if err != nil {
 		return fmt.Errorf("failed to delete category: %w", err)
 	}
@@ -127,7 +139,10 @@ func (r *PostgresRepository) Delete(ctx context.Context, id int64) error {
 	}
 	
 	if rowsAffected == 0 {
-		return errors.New("category not found")
+		if version != nil {
+			return ErrVersionMismatch
+		}
+		return ErrCategoryNotFound
 	}
 	
 	return nil
@@ -135,7 +150,7 @@ func (r *PostgresRepository) Delete(ctx context.Context, id int64) error {
 
 // Exists checks if a category exists by ID
 func (r *PostgresRepository) Exists(ctx context.Context, id int64) (bool, error) {
-	query := `SELECT EXISTS(SELECT 1 FROM categories WHERE id = $1)`
+	query := `SELECT EXISTS(SELECT 1 FROM categories WHERE id = $1 AND deleted_at IS NULL AND ` + workspaces.Condition(ctx, "workspace_id") + `)`
 	
 	var exists bool
 	err := r.db.QueryRowContext(ctx, query, id).Scan(&exists)
@@ -144,4 +159,62 @@ func (r *PostgresRepository) Exists(ctx context.Context, id int64) (bool, error)
 	}
 	
 	return exists, nil
-}
\ No newline at end of file
+}
+
+// GetTrash retrieves the deleted categories, most recently deleted first
+func (r *PostgresRepository) GetTrash(ctx context.Context) ([]*Category, error) {
+	query := `
+		SELECT id, name, description, deleted_at, version FROM categories
+		WHERE deleted_at IS NOT NULL AND ` + workspaces.Condition(ctx, "workspace_id") + `
+		ORDER BY deleted_at DESC, id`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deleted categories: %w", err)
+	}
+	defer rows.Close()
+
+	categories := []*Category{}
+	for rows.Next() {
+		var category Category
+		if err := rows.Scan(&category.ID, &category.Name, &category.Description, &category.DeletedAt, &category.Version); err != nil {
+			return nil, fmt.Errorf("failed to scan category: %w", err)
+		}
+		categories = append(categories, &category)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating categories: %w", err)
+	}
+
+	return categories, nil
+}
+
+// Restore takes a category out of the trash
+func (r *PostgresRepository) Restore(ctx context.Context, id int64) (*Category, error) {
+	query := `
+		UPDATE categories SET deleted_at = NULL, version = version + 1
+		WHERE id = $1 AND deleted_at IS NOT NULL AND ` + workspaces.Condition(ctx, "workspace_id") + `
+		RETURNING id, name, description, version`
+
+	var category Category
+	err := r.db.QueryRowContext(ctx, query, id).Scan(&category.ID, &category.Name, &category.Description, &category.Version)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotInTrash
+		}
+		return nil, fmt.Errorf("failed to restore category: %w", err)
+	}
+
+	return &category, nil
+}
+
+// Purge permanently removes the categories that were moved to the trash before the given time
+func (r *PostgresRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM categories WHERE deleted_at < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge categories: %w", err)
+	}
+
+	return result.RowsAffected()
+}
@@ -2,8 +2,10 @@ package categories
 
 import (
 	"context"
-	"errors"
 	"strings"
+
+	"backend/root/internal/i18n"
+	"backend/root/internal/workspaces"
 )
 
 // Service defines the interface for category business logic
@@ -12,7 +14,9 @@ type Service interface {
 	GetByID(ctx context.Context, id int64) (*Category, error)
 	GetAll(ctx context.Context) ([]*Category, error)
 	Update(ctx context.Context, id int64, req UpdateCategoryRequest) (*Category, error)
-	Delete(ctx context.Context, id int64) error
+	Delete(ctx context.Context, id int64, ifMatch *int64) error
+	GetTrash(ctx context.Context) ([]*Category, error)
+	Restore(ctx context.Context, id int64) (*Category, error)
 }
 
 // service implements the Service interface
@@ -29,20 +33,25 @@ func NewService(repo Repository) Service {
 
 // Create creates a new category with validation
 func (s *service) Create(ctx context.Context, req CreateCategoryRequest) (*Category, error) {
+	// Guests of a workspace cannot change its categories
+	if err := workspaces.CheckWritable(ctx); err != nil {
+		return nil, err
+	}
+	
 	// Validate and clean input
 	name := strings.TrimSpace(req.Name)
 	description := strings.TrimSpace(req.Description)
 	
 	if name == "" {
-		return nil, errors.New("category name cannot be empty")
+		return nil, i18n.NewError("category_name_empty")
 	}
 	
 	if len(name) > 100 {
-		return nil, errors.New("category name cannot exceed 100 characters")
+		return nil, i18n.NewError("category_name_too_long")
 	}
 	
 	if len(description) > 500 {
-		return nil, errors.New("category description cannot exceed 500 characters")
+		return nil, i18n.NewError("category_description_long")
 	}
 	
 	return s.repo.Create(ctx, name, description)
@@ -51,7 +60,7 @@ func (s *service) Create(ctx context.Context, req CreateCategoryRequest) (*Categ
 // GetByID retrieves a category by ID
 func (s *service) GetByID(ctx context.Context, id int64) (*Category, error) {
 	if id <= 0 {
-		return nil, errors.New("invalid category ID")
+		return nil, i18n.NewError("category_id_invalid")
 	}
 	
 	return s.repo.GetByID(ctx, id)
@@ -65,7 +74,10 @@ func (s *service) GetAll(ctx context.Context) ([]*Category, error) {
 // Update updates an existing category with validation
 func (s *service) Update(ctx context.Context, id int64, req UpdateCategoryRequest) (*Category, error) {
 	if id <= 0 {
-		return nil, errors.New("invalid category ID")
+		return nil, i18n.NewError("category_id_invalid")
+	}
+	if err := workspaces.CheckWritable(ctx); err != nil {
+		return nil, err
 	}
 	
 	// Validate and clean input
@@ -73,15 +85,15 @@ func (s *service) Update(ctx context.Context, id int64, req UpdateCategoryReques
 	description := strings.TrimSpace(req.Description)
 	
 	if name == "" {
-		return nil, errors.New("category name cannot be empty")
+		return nil, i18n.NewError("category_name_empty")
 	}
 	
 	if len(name) > 100 {
-		return nil, errors.New("category name cannot exceed 100 characters")
+		return nil, i18n.NewError("category_name_too_long")
 	}
 	
 	if len(description) > 500 {
-		return nil, errors.New("category description cannot exceed 500 characters")
+		return nil, i18n.NewError("category_description_long")
 	}
 	
 	// Check if category exists
@@ -90,16 +102,19 @@ func (s *service) Update(ctx context.Context, id int64, req UpdateCategoryReques
 		return nil, err
 	}
 	if !exists {
-		return nil, errors.New("category not found")
+		return nil, ErrCategoryNotFound
 	}
 	
-	return s.repo.Update(ctx, id, name, description)
+	return s.repo.Update(ctx, id, name, description, req.IfMatch)
 }
 
-// Delete removes a category by ID
-func (s *service) Delete(ctx context.Context, id int64) error {
+// Delete moves a category to the trash; when ifMatch is set the category must still be at that version
+func (s *service) Delete(ctx context.Context, id int64, ifMatch *int64) error {
 	if id <= 0 {
-		return errors.New("invalid category ID")
+		return i18n.NewError("category_id_invalid")
+	}
+	if err := workspaces.CheckWritable(ctx); err != nil {
+		return err
 	}
 	
 	// Check if category exists
@@ -108,8 +123,25 @@ func (s *service) Delete(ctx context.Context, id int64) error {
 		return err
 	}
 	if !exists {
-		return errors.New("category not found")
+		return ErrCategoryNotFound
+	}
+	
+	return s.repo.Delete(ctx, id, ifMatch)
+}
+
+// GetTrash retrieves the deleted categories
+func (s *service) GetTrash(ctx context.Context) ([]*Category, error) {
+	return s.repo.GetTrash(ctx)
+}
+
+// Restore takes a category out of the trash
+func (s *service) Restore(ctx context.Context, id int64) (*Category, error) {
+	if id <= 0 {
+		return nil, i18n.NewError("category_id_invalid")
+	}
+	if err := workspaces.CheckWritable(ctx); err != nil {
+		return nil, err
 	}
 	
-	return s.repo.Delete(ctx, id)
-}
\ No newline at end of file
+	return s.repo.Restore(ctx, id)
+}
@@ -0,0 +1,191 @@
+package comments
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+
+	"backend/root/internal/i18n"
+	"backend/root/internal/tasks"
+	"backend/root/internal/workspaces"
+)
+
+// Handler handles HTTP requests for task comments
+type Handler struct {
+	service Service
+}
+
+// NewHandler creates a new comments handler
+func NewHandler(service Service) *Handler {
+	return &Handler{
+		service: service,
+	}
+}
+
+// Create handles POST /tasks/:id/comments - adds a comment to a task
+func (h *Handler) Create(c *gin.Context) {
+	taskID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, i18n.NewError("task_id_invalid"))
+		return
+	}
+
+	var req CreateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, i18n.NewError("request_invalid_detail", err))
+		return
+	}
+
+	userID, err := getUserIDFromClaims(c)
+	if err != nil {
+		i18n.RespondError(c, http.StatusUnauthorized, err)
+		return
+	}
+
+	comment, err := h.service.Create(c.Request.Context(), taskID, userID, req)
+	if err != nil {
+		i18n.RespondError(c, statusFor(err), err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, comment)
+}
+
+// GetByTask handles GET /tasks/:id/comments - lists a task's comments (?limit=50&offset=0)
+func (h *Handler) GetByTask(c *gin.Context) {
+	taskID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, i18n.NewError("task_id_invalid"))
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "0"))
+	if err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, i18n.NewError("task_limit_invalid"))
+		return
+	}
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, i18n.NewError("task_offset_invalid"))
+		return
+	}
+
+	userID, err := getUserIDFromClaims(c)
+	if err != nil {
+		i18n.RespondError(c, http.StatusUnauthorized, err)
+		return
+	}
+
+	page, err := h.service.GetByTask(c.Request.Context(), taskID, userID, limit, offset)
+	if err != nil {
+		i18n.RespondError(c, statusFor(err), err)
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// Update handles PATCH /tasks/:id/comments/:comment_id - edits a comment
+func (h *Handler) Update(c *gin.Context) {
+	taskID, commentID, ok := parseIDs(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, i18n.NewError("request_invalid_detail", err))
+		return
+	}
+
+	userID, err := getUserIDFromClaims(c)
+	if err != nil {
+		i18n.RespondError(c, http.StatusUnauthorized, err)
+		return
+	}
+
+	comment, err := h.service.Update(c.Request.Context(), taskID, commentID, userID, req)
+	if err != nil {
+		i18n.RespondError(c, statusFor(err), err)
+		return
+	}
+
+	c.JSON(http.StatusOK, comment)
+}
+
+// Delete handles DELETE /tasks/:id/comments/:comment_id - removes a comment
+func (h *Handler) Delete(c *gin.Context) {
+	taskID, commentID, ok := parseIDs(c)
+	if !ok {
+		return
+	}
+
+	userID, err := getUserIDFromClaims(c)
+	if err != nil {
+		i18n.RespondError(c, http.StatusUnauthorized, err)
+		return
+	}
+
+	if err := h.service.Delete(c.Request.Context(), taskID, commentID, userID); err != nil {
+		i18n.RespondError(c, statusFor(err), err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "comment deleted successfully"})
+}
+
+// parseIDs reads the task and comment IDs from the path, writing a 400 response when either is invalid
+func parseIDs(c *gin.Context) (int64, int64, bool) {
+	taskID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, i18n.NewError("task_id_invalid"))
+		return 0, 0, false
+	}
+
+	commentID, err := strconv.ParseInt(c.Param("comment_id"), 10, 64)
+	if err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, i18n.NewError("comment_id_invalid"))
+		return 0, 0, false
+	}
+
+	return taskID, commentID, true
+}
+
+// statusFor maps service errors to HTTP status codes
+func statusFor(err error) int {
+	switch {
+	case errors.Is(err, tasks.ErrTaskNotFound), errors.Is(err, ErrCommentNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrNotAuthor), errors.Is(err, workspaces.ErrReadOnly):
+		return http.StatusForbidden
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+// getUserIDFromClaims extracts the user ID from the JWT claims set by the auth middleware
+func getUserIDFromClaims(c *gin.Context) (int64, error) {
+	claims, exists := c.Get("claims")
+	if !exists {
+		return 0, i18n.NewError("user_not_authenticated")
+	}
+
+	claimsMap, ok := claims.(jwt.MapClaims)
+	if !ok {
+		return 0, errors.New("invalid claims")
+	}
+
+	switch v := claimsMap["uid"].(type) {
+	case float64:
+		return int64(v), nil
+	case int64:
+		return v, nil
+	case int:
+		return int64(v), nil
+	default:
+		return 0, i18n.NewError("user_id_missing")
+	}
+}
@@ -0,0 +1,53 @@
+package comments
+
+import (
+	"time"
+
+	"backend/root/internal/i18n"
+)
+
+// Comment represents a note left on a task. The body is stored as Markdown
+// and rendered by clients.
+type Comment struct {
+	ID        int64      `json:"id" db:"id"`
+	TaskID    int64      `json:"task_id" db:"task_id"`
+	AuthorID  int64      `json:"author_id" db:"author_id"`
+	Author    string     `json:"author" db:"author"` // Username of the author
+	Body      string     `json:"body" db:"body"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt *time.Time `json:"updated_at" db:"updated_at"` // Set on the last edit
+	Edited    bool       `json:"edited"`
+}
+
+// CreateCommentRequest represents the request payload for adding a comment
+type CreateCommentRequest struct {
+	Body string `json:"body" binding:"required,min=1,max=10000"`
+}
+
+// UpdateCommentRequest represents the request payload for editing a comment
+type UpdateCommentRequest struct {
+	Body string `json:"body" binding:"required,min=1,max=10000"`
+}
+
+// CommentPage is one page of a task's comments, oldest first
+type CommentPage struct {
+	Comments []*Comment `json:"comments"`
+	Total    int        `json:"total"`
+	Limit    int        `json:"limit"`
+	Offset   int        `json:"offset"`
+}
+
+// Pagination limits for listing comments
+const (
+	DefaultPageSize = 50
+	MaxPageSize     = 100
+)
+
+// MaxBodyLength caps the size of a comment body in characters
+const MaxBodyLength = 10000
+
+// Errors returned when a comment cannot be found or changed
+var (
+	ErrCommentNotFound = i18n.NewError("comment_not_found")
+	ErrNotAuthor       = i18n.NewError("comment_not_author")
+)
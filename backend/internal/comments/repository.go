@@ -0,0 +1,146 @@
+package comments
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// Repository defines the interface for comment persistence
+type Repository interface {
+	Create(ctx context.Context, taskID int64, authorID int64, body string) (*Comment, error)
+	GetByID(ctx context.Context, id int64) (*Comment, error)
+	GetByTask(ctx context.Context, taskID int64, limit int, offset int) ([]*Comment, int, error)
+	Update(ctx context.Context, id int64, body string) (*Comment, error)
+	Delete(ctx context.Context, id int64) error
+}
+
+// commentSelect selects a comment with its author's username, in the order expected by scanComment
+const commentSelect = `
+		SELECT tc.id, tc.task_id, COALESCE(tc.author_id, 0), COALESCE(u.username, ''), tc.body, tc.created_at, tc.updated_at
+		FROM task_comments tc
+		LEFT JOIN users u ON u.id = tc.author_id`
+
+// PostgresRepository implements Repository using PostgreSQL
+type PostgresRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresRepository creates a new PostgreSQL comments repository
+func NewPostgresRepository(db *sql.DB) *PostgresRepository {
+	return &PostgresRepository{db: db}
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanComment(row rowScanner) (*Comment, error) {
+	var comment Comment
+	err := row.Scan(
+		&comment.ID, &comment.TaskID, &comment.AuthorID, &comment.Author, &comment.Body,
+		&comment.CreatedAt, &comment.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	comment.Edited = comment.UpdatedAt != nil
+	return &comment, nil
+}
+
+// Create stores a new comment on a task
+func (r *PostgresRepository) Create(ctx context.Context, taskID int64, authorID int64, body string) (*Comment, error) {
+	query := `INSERT INTO task_comments (task_id, author_id, body) VALUES ($1, $2, $3) RETURNING id`
+
+	var id int64
+	if err := r.db.QueryRowContext(ctx, query, taskID, authorID, body).Scan(&id); err != nil {
+		return nil, fmt.Errorf("failed to create comment: %w", err)
+	}
+
+	return r.GetByID(ctx, id)
+}
+
+// GetByID retrieves a comment by its ID
+func (r *PostgresRepository) GetByID(ctx context.Context, id int64) (*Comment, error) {
+	comment, err := scanComment(r.db.QueryRowContext(ctx, commentSelect+` WHERE tc.id = $1`, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrCommentNotFound
+		}
+		return nil, fmt.Errorf("failed to get comment: %w", err)
+	}
+
+	return comment, nil
+}
+
+// GetByTask retrieves one page of a task's comments, oldest first, along with the total number of comments
+func (r *PostgresRepository) GetByTask(ctx context.Context, taskID int64, limit int, offset int) ([]*Comment, int, error) {
+	var total int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM task_comments WHERE task_id = $1`, taskID).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count comments: %w", err)
+	}
+
+	query := commentSelect + ` WHERE tc.task_id = $1 ORDER BY tc.created_at, tc.id LIMIT $2 OFFSET $3`
+
+	rows, err := r.db.QueryContext(ctx, query, taskID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get comments: %w", err)
+	}
+	defer rows.Close()
+
+	comments := []*Comment{}
+	for rows.Next() {
+		comment, err := scanComment(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan comment: %w", err)
+		}
+		comments = append(comments, comment)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating comments: %w", err)
+	}
+
+	return comments, total, nil
+}
+
+// Update replaces the body of a comment and records when it was edited
+func (r *PostgresRepository) Update(ctx context.Context, id int64, body string) (*Comment, error) {
+	query := `UPDATE task_comments SET body = $2, updated_at = NOW() WHERE id = $1`
+
+	result, err := r.db.ExecContext(ctx, query, id, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update comment: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return nil, ErrCommentNotFound
+	}
+
+	return r.GetByID(ctx, id)
+}
+
+// Delete removes a comment by ID
+func (r *PostgresRepository) Delete(ctx context.Context, id int64) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM task_comments WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete comment: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrCommentNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,168 @@
+package comments
+
+import (
+	"context"
+	"strings"
+	"unicode/utf8"
+
+	"backend/root/internal/i18n"
+	"backend/root/internal/tasks"
+	"backend/root/internal/workspaces"
+)
+
+// Service defines the interface for comment business logic
+type Service interface {
+	Create(ctx context.Context, taskID int64, userID int64, req CreateCommentRequest) (*Comment, error)
+	GetByTask(ctx context.Context, taskID int64, userID int64, limit int, offset int) (*CommentPage, error)
+	Update(ctx context.Context, taskID int64, id int64, userID int64, req UpdateCommentRequest) (*Comment, error)
+	Delete(ctx context.Context, taskID int64, id int64, userID int64) error
+}
+
+// TaskRepository defines the minimal interface needed to validate access to a task
+type TaskRepository interface {
+	GetRole(ctx context.Context, id int64, userID int64) (string, error)
+}
+
+// service implements the Service interface
+type service struct {
+	repo     Repository
+	taskRepo TaskRepository
+}
+
+// NewService creates a new comment service
+func NewService(repo Repository, taskRepo TaskRepository) Service {
+	return &service{
+		repo:     repo,
+		taskRepo: taskRepo,
+	}
+}
+
+// Create adds a comment to one of the user's tasks
+func (s *service) Create(ctx context.Context, taskID int64, userID int64, req CreateCommentRequest) (*Comment, error) {
+	// Guests of a workspace cannot join the discussion of its tasks
+	if err := workspaces.CheckWritable(ctx); err != nil {
+		return nil, err
+	}
+	if err := s.checkTask(ctx, taskID, userID); err != nil {
+		return nil, err
+	}
+
+	body, err := normalizeBody(req.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.Create(ctx, taskID, userID, body)
+}
+
+// GetByTask retrieves one page of the comments of one of the user's tasks
+func (s *service) GetByTask(ctx context.Context, taskID int64, userID int64, limit int, offset int) (*CommentPage, error) {
+	if err := s.checkTask(ctx, taskID, userID); err != nil {
+		return nil, err
+	}
+
+	if limit <= 0 {
+		limit = DefaultPageSize
+	}
+	if limit > MaxPageSize {
+		limit = MaxPageSize
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	comments, total, err := s.repo.GetByTask(ctx, taskID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CommentPage{
+		Comments: comments,
+		Total:    total,
+		Limit:    limit,
+		Offset:   offset,
+	}, nil
+}
+
+// Update edits a comment; only its author may do so
+func (s *service) Update(ctx context.Context, taskID int64, id int64, userID int64, req UpdateCommentRequest) (*Comment, error) {
+	if err := workspaces.CheckWritable(ctx); err != nil {
+		return nil, err
+	}
+	if _, err := s.getAuthoredComment(ctx, taskID, id, userID); err != nil {
+		return nil, err
+	}
+
+	body, err := normalizeBody(req.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.Update(ctx, id, body)
+}
+
+// Delete removes a comment; only its author may do so
+func (s *service) Delete(ctx context.Context, taskID int64, id int64, userID int64) error {
+	if err := workspaces.CheckWritable(ctx); err != nil {
+		return err
+	}
+	if _, err := s.getAuthoredComment(ctx, taskID, id, userID); err != nil {
+		return err
+	}
+
+	return s.repo.Delete(ctx, id)
+}
+
+// getAuthoredComment loads a comment of a task and checks that the user wrote it
+func (s *service) getAuthoredComment(ctx context.Context, taskID int64, id int64, userID int64) (*Comment, error) {
+	if err := s.checkTask(ctx, taskID, userID); err != nil {
+		return nil, err
+	}
+	if id <= 0 {
+		return nil, i18n.NewError("comment_id_invalid")
+	}
+
+	comment, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if comment.TaskID != taskID {
+		return nil, ErrCommentNotFound
+	}
+	if comment.AuthorID != userID {
+		return nil, ErrNotAuthor
+	}
+
+	return comment, nil
+}
+
+// checkTask checks that a task exists and the user can see it: its owner and everyone
+// it is shared with, viewers included, take part in the discussion
+func (s *service) checkTask(ctx context.Context, taskID int64, userID int64) error {
+	if taskID <= 0 {
+		return i18n.NewError("task_id_invalid")
+	}
+
+	role, err := s.taskRepo.GetRole(ctx, taskID, userID)
+	if err != nil {
+		return err
+	}
+	if !tasks.HasRole(role, tasks.RoleViewer) {
+		return tasks.ErrTaskNotFound
+	}
+
+	return nil
+}
+
+// normalizeBody trims a Markdown comment body and validates its length
+func normalizeBody(body string) (string, error) {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return "", i18n.NewError("comment_body_empty")
+	}
+	if utf8.RuneCountInString(body) > MaxBodyLength {
+		return "", i18n.NewError("comment_body_long")
+	}
+
+	return body, nil
+}
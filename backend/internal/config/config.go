@@ -12,6 +12,12 @@ type Config struct {
 	JWT      JWTConfig
 	App      AppConfig
 	Database DatabaseConfig
+	Tasks     TasksConfig
+	Trash     TrashConfig
+	Board     BoardConfig
+	Reminders RemindersConfig
+	SMTP      SMTPConfig
+	I18n      I18nConfig
 }
 
 type ServerConfig struct {
@@ -44,6 +50,45 @@ type DatabaseConfig struct {
 	MaxIdleConns int
 }
 
+type TasksConfig struct {
+	CompleteParentPolicy string // cascade, block
+	DeleteParentPolicy   string // cascade, block
+	UrgentWithinDays     int    // tasks due within this many days are urgent in the Eisenhower matrix
+}
+
+type TrashConfig struct {
+	PurgeEnabled  bool          // run the background trash purger in this instance
+	Retention     time.Duration // how long deleted tasks and categories stay restorable
+	PurgeInterval time.Duration
+}
+
+type BoardConfig struct {
+	RebalanceEnabled  bool // run the background board rebalancer in this instance
+	RebalanceInterval time.Duration
+}
+
+type RemindersConfig struct {
+	Enabled        bool // run the background reminder scheduler in this instance
+	PollInterval   time.Duration
+	BatchSize      int
+	Lease          time.Duration // how long a claimed reminder is reserved for this instance
+	MaxAttempts    int
+	RetryBackoff   time.Duration // first retry delay, doubled on each further attempt
+	WebhookTimeout time.Duration
+}
+
+type SMTPConfig struct {
+	Host     string // empty disables email reminders
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+type I18nConfig struct {
+	DefaultLocale string // en or es, used when neither the user nor Accept-Language selects one
+}
+
 // Load reads configuration from environment variables with sensible defaults
 func Load() *Config {
 	return &Config{
@@ -73,6 +118,39 @@ func Load() *Config {
 			MaxOpenConns: getEnvInt("DB_MAX_OPEN_CONNS", 25),
 			MaxIdleConns: getEnvInt("DB_MAX_IDLE_CONNS", 5),
 		},
+		Tasks: TasksConfig{
+			CompleteParentPolicy: getEnv("TASK_PARENT_COMPLETE_POLICY", "block"),
+			DeleteParentPolicy:   getEnv("TASK_PARENT_DELETE_POLICY", "block"),
+			UrgentWithinDays:     getEnvInt("TASK_URGENT_WITHIN_DAYS", 2),
+		},
+		Trash: TrashConfig{
+			PurgeEnabled:  getEnvBool("TRASH_PURGE_ENABLED", true),
+			Retention:     getEnvDuration("TRASH_RETENTION", "720h"),
+			PurgeInterval: getEnvDuration("TRASH_PURGE_INTERVAL", "1h"),
+		},
+		Board: BoardConfig{
+			RebalanceEnabled:  getEnvBool("BOARD_REBALANCE_ENABLED", true),
+			RebalanceInterval: getEnvDuration("BOARD_REBALANCE_INTERVAL", "1h"),
+		},
+		Reminders: RemindersConfig{
+			Enabled:        getEnvBool("REMINDERS_ENABLED", true),
+			PollInterval:   getEnvDuration("REMINDERS_POLL_INTERVAL", "30s"),
+			BatchSize:      getEnvInt("REMINDERS_BATCH_SIZE", 50),
+			Lease:          getEnvDuration("REMINDERS_LEASE", "5m"),
+			MaxAttempts:    getEnvInt("REMINDERS_MAX_ATTEMPTS", 5),
+			RetryBackoff:   getEnvDuration("REMINDERS_RETRY_BACKOFF", "1m"),
+			WebhookTimeout: getEnvDuration("REMINDERS_WEBHOOK_TIMEOUT", "10s"),
+		},
+		SMTP: SMTPConfig{
+			Host:     getEnv("SMTP_HOST", ""),
+			Port:     getEnv("SMTP_PORT", "587"),
+			Username: getEnv("SMTP_USERNAME", ""),
+			Password: getEnv("SMTP_PASSWORD", ""),
+			From:     getEnv("SMTP_FROM", "no-reply@proyecto0.local"),
+		},
+		I18n: I18nConfig{
+			DefaultLocale: getEnv("I18N_DEFAULT_LOCALE", "en"),
+		},
 	}
 }
 
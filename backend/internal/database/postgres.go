@@ -0,0 +1,44 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	_ "github.com/lib/pq"
+
+	"backend/root/internal/config"
+)
+
+// Open connects to PostgreSQL using the database configuration and verifies the connection
+func Open(cfg config.DatabaseConfig) (*sql.DB, error) {
+	// Ensure PostgreSQL is configured
+	if cfg.Driver != "postgres" {
+		return nil, errors.New("only PostgreSQL database is supported. Set DB_DRIVER=postgres")
+	}
+
+	dsn := fmt.Sprintf(
+		"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
+		cfg.Host, cfg.Port, cfg.User,
+		cfg.Password, cfg.Name, cfg.SSLMode,
+	)
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	// Configure connection pool
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(time.Hour)
+
+	// Test connection
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	return db, nil
+}
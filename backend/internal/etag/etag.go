@@ -0,0 +1,70 @@
+// Package etag formats and parses the entity tags used for optimistic concurrency control.
+// A resource's ETag is its version number, incremented on every write, optionally followed
+// by a hash of the representation when it holds data the version does not track.
+package etag
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+
+	"backend/root/internal/i18n"
+)
+
+// Format returns the ETag header value for a resource version
+func Format(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// FormatContent returns the ETag header value for a resource version whose representation
+// also depends on other data, e.g. "7-3f2a9c0d1e4b5a68". If-Match compares the version alone,
+// while If-None-Match compares the whole tag, so a cached copy expires when the body changes.
+func FormatContent(version int64, body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + strconv.FormatInt(version, 10) + "-" + hex.EncodeToString(sum[:8]) + `"`
+}
+
+// ParseIfMatch reads an If-Match header value. It returns nil when the header is empty
+// or "*" (no precondition on an existing resource) and the expected version otherwise.
+func ParseIfMatch(header string) (*int64, error) {
+	header = strings.TrimSpace(header)
+	if header == "" || header == "*" {
+		return nil, nil
+	}
+
+	version, ok := parse(header)
+	if !ok {
+		return nil, i18n.NewError("task_if_match_invalid")
+	}
+
+	return &version, nil
+}
+
+// Matches reports whether an If-None-Match header value lists the given ETag,
+// meaning the client's cached copy is current. Weak tags are compared by value.
+func Matches(header string, etag string) bool {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parse extracts the version from a quoted ETag, ignoring the content hash if any
+func parse(tag string) (int64, bool) {
+	if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+		return 0, false
+	}
+
+	value, _, _ := strings.Cut(tag[1:len(tag)-1], "-")
+	version, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	return version, true
+}
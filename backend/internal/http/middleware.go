@@ -1,11 +1,20 @@
 package httpserver
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
 
 	"backend/root/internal/auth"
+	"backend/root/internal/i18n"
+	"backend/root/internal/users"
+	"backend/root/internal/workspaces"
 )
 
 // AuthMiddleware verifies JWT and optionally checks server-side revocation.
@@ -16,20 +25,205 @@ func AuthMiddleware(tokens auth.TokenManager, isRevoked func(token string) bool)
         authHeader := c.GetHeader("Authorization")
         const prefix = "Bearer "
         if len(authHeader) <= len(prefix) || authHeader[:len(prefix)] != prefix {
-            c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "missing or invalid authorization header"})
+            i18n.RespondError(c, http.StatusUnauthorized, i18n.NewError("auth_header_missing"))
             return
         }
         tokenString := authHeader[len(prefix):]
         if isRevoked != nil && isRevoked(tokenString) {
-            c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "token revoked"})
+            i18n.RespondError(c, http.StatusUnauthorized, i18n.NewError("token_revoked"))
             return
         }
         claims, err := tokens.VerifyToken(tokenString)
         if err != nil {
-            c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
+            i18n.RespondError(c, http.StatusUnauthorized, i18n.NewError("token_invalid"))
             return
         }
         c.Set("claims", claims)
         c.Next()
     }
 }
+
+// BasicAuthMiddleware authenticates clients that cannot obtain a JWT, such as calendar apps,
+// with the username and password of HTTP Basic authentication. It sets the same "uid" and
+// "username" claims as AuthMiddleware, so handlers read the user the same way.
+func BasicAuthMiddleware(realm string, authenticate func(ctx context.Context, username, password string) (*users.User, error)) gin.HandlerFunc {
+    challenge := `Basic realm="` + realm + `", charset="UTF-8"`
+    return func(c *gin.Context) {
+        username, password, ok := c.Request.BasicAuth()
+        if !ok {
+            c.Header("WWW-Authenticate", challenge)
+            i18n.RespondError(c, http.StatusUnauthorized, i18n.NewError("auth_header_required"))
+            return
+        }
+        user, err := authenticate(c.Request.Context(), username, password)
+        if err != nil {
+            c.Header("WWW-Authenticate", challenge)
+            i18n.RespondError(c, http.StatusUnauthorized, i18n.NewError("credentials_invalid"))
+            return
+        }
+        c.Set("claims", jwt.MapClaims{"uid": float64(user.ID), "username": user.Username})
+        c.Next()
+    }
+}
+
+// LocaleMiddleware selects the locale of the request from the Accept-Language header,
+// falling back to defaultLocale, and stores it in the request context.
+func LocaleMiddleware(defaultLocale string) gin.HandlerFunc {
+    if !i18n.IsSupported(defaultLocale) {
+        defaultLocale = i18n.DefaultLocale
+    }
+    return func(c *gin.Context) {
+        locale := i18n.Negotiate(c.GetHeader("Accept-Language"))
+        if locale == "" {
+            locale = defaultLocale
+        }
+        setLocale(c, locale)
+        c.Next()
+    }
+}
+
+// UserLocaleMiddleware overrides the negotiated locale with the preference stored for the
+// authenticated user. It must run after AuthMiddleware; lookup errors keep the negotiated locale.
+func UserLocaleMiddleware(lookup func(ctx context.Context, userID int64) (string, error)) gin.HandlerFunc {
+    return func(c *gin.Context) {
+        claims, _ := c.Get("claims")
+        if claims, ok := claims.(jwt.MapClaims); ok {
+            if uid, ok := claims["uid"].(float64); ok {
+                if locale, err := lookup(c.Request.Context(), int64(uid)); err == nil && i18n.IsSupported(locale) {
+                    setLocale(c, locale)
+                }
+            }
+        }
+        c.Next()
+    }
+}
+
+func setLocale(c *gin.Context, locale string) {
+    c.Set("locale", locale)
+    c.Request = c.Request.WithContext(i18n.WithLocale(c.Request.Context(), locale))
+    c.Header("Content-Language", locale)
+}
+
+// WorkspaceHeader carries the workspace a request works in; without it requests work in the
+// caller's personal space
+const WorkspaceHeader = "X-Workspace-ID"
+
+// WorkspaceMiddleware binds every request to a tenant scope: the workspace named by the
+// X-Workspace-ID header, which the caller must belong to, or their personal space. Repositories
+// read the scope from the request context to isolate the data of each tenant.
+func WorkspaceMiddleware(lookup func(ctx context.Context, workspaceID int64, userID int64) (string, error)) gin.HandlerFunc {
+    return func(c *gin.Context) {
+        scope := workspaces.Scope{}
+        if header := c.GetHeader(WorkspaceHeader); header != "" {
+            workspaceID, err := strconv.ParseInt(header, 10, 64)
+            if err != nil || workspaceID <= 0 {
+                i18n.RespondError(c, http.StatusBadRequest, i18n.NewError("workspace_header_invalid"))
+                return
+            }
+
+            var userID int64
+            if claims, ok := c.MustGet("claims").(jwt.MapClaims); ok {
+                if uid, ok := claims["uid"].(float64); ok {
+                    userID = int64(uid)
+                }
+            }
+            role, err := lookup(c.Request.Context(), workspaceID, userID)
+            if err != nil {
+                i18n.RespondError(c, http.StatusInternalServerError, i18n.NewError("workspace_membership_failed"))
+                return
+            }
+            if role == "" {
+                i18n.RespondError(c, http.StatusNotFound, i18n.NewError("workspace_not_found"))
+                return
+            }
+            scope = workspaces.Scope{WorkspaceID: workspaceID, Role: role}
+        }
+
+        c.Set("workspace_id", scope.WorkspaceID)
+        c.Request = c.Request.WithContext(workspaces.WithScope(c.Request.Context(), scope))
+        c.Next()
+    }
+}
+
+// LocalizeErrors renders the "error" message of JSON error responses in the request locale
+// and adds a stable machine-readable "code" next to it. The code comes from the i18n.Error
+// the handler recorded with i18n.RespondError; errors without one get a code derived from
+// the status, and server errors also get a generic text so internal details are not leaked.
+func LocalizeErrors() gin.HandlerFunc {
+    return func(c *gin.Context) {
+        writer := &errorWriter{ResponseWriter: c.Writer}
+        c.Writer = writer
+        c.Next()
+
+        if writer.body.Len() == 0 {
+            return
+        }
+
+        var body map[string]interface{}
+        isString := false
+        if err := json.Unmarshal(writer.body.Bytes(), &body); err == nil {
+            _, isString = body["error"].(string)
+        }
+        if !isString {
+            writer.ResponseWriter.Write(writer.body.Bytes())
+            return
+        }
+
+        locale := i18n.FromContext(c.Request.Context())
+        var coded *i18n.Error
+        if last := c.Errors.Last(); last != nil && errors.As(last.Err, &coded) {
+            body["error"] = coded.Localize(locale)
+            body["code"] = coded.Code
+        } else {
+            code := statusCode(writer.Status())
+            if writer.Status() >= http.StatusInternalServerError {
+                body["error"] = i18n.T(locale, code)
+            }
+            body["code"] = code
+        }
+
+        localized, err := json.Marshal(body)
+        if err != nil {
+            writer.ResponseWriter.Write(writer.body.Bytes())
+            return
+        }
+        writer.ResponseWriter.Write(localized)
+    }
+}
+
+// errorWriter holds back the body of error responses so LocalizeErrors can rewrite it
+type errorWriter struct {
+    gin.ResponseWriter
+    body bytes.Buffer
+}
+
+func (w *errorWriter) Write(data []byte) (int, error) {
+    if w.Status() < http.StatusBadRequest {
+        return w.ResponseWriter.Write(data)
+    }
+    return w.body.Write(data)
+}
+
+func (w *errorWriter) WriteString(s string) (int, error) {
+    return w.Write([]byte(s))
+}
+
+// statusCode is the generic error code of a status, used for errors without a code of their own
+func statusCode(status int) string {
+    switch status {
+    case http.StatusUnauthorized:
+        return "unauthorized"
+    case http.StatusForbidden:
+        return "forbidden"
+    case http.StatusNotFound:
+        return "not_found"
+    case http.StatusConflict:
+        return "conflict"
+    case http.StatusPreconditionFailed:
+        return "precondition_failed"
+    }
+    if status >= http.StatusInternalServerError {
+        return "internal_error"
+    }
+    return "bad_request"
+}
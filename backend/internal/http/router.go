@@ -2,67 +2,52 @@ package httpserver
 
 import (
 	"database/sql"
-	"fmt"
-	"log"
-	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gin-contrib/cors"
-	_ "github.com/lib/pq"
 
 	"backend/root/internal/auth"
+	"backend/root/internal/calendar"
 	"backend/root/internal/categories"
+	"backend/root/internal/comments"
 	"backend/root/internal/config"
+	"backend/root/internal/reminders"
+	"backend/root/internal/shares"
+	"backend/root/internal/states"
 	"backend/root/internal/storage"
+	"backend/root/internal/tags"
 	"backend/root/internal/tasks"
+	"backend/root/internal/timetracking"
+	"backend/root/internal/trash"
 	"backend/root/internal/users"
+	"backend/root/internal/workspaces"
 )
 
-// NewRouter creates and returns a configured Gin engine
-func NewRouter(cfg *config.Config) *gin.Engine {
+// NewRouter creates and returns a configured Gin engine backed by the given database
+func NewRouter(cfg *config.Config, db *sql.DB) *gin.Engine {
 	router := gin.New()
 	router.Use(gin.Logger(), gin.Recovery())
 
 	config := cors.DefaultConfig()
 	config.AllowOrigins = []string{"http://localhost:3000", "http://127.0.0.1:3000"}
 	config.AllowCredentials = true
-	config.AllowHeaders = []string{"Origin", "Content-Length", "Content-Type", "Authorization"}
+	config.AllowHeaders = []string{"Origin", "Content-Length", "Content-Type", "Authorization", WorkspaceHeader}
 	router.Use(cors.New(config))
+	router.Use(LocaleMiddleware(cfg.I18n.DefaultLocale), LocalizeErrors())
 
 
-    // Ensure PostgreSQL is configured
-    if cfg.Database.Driver != "postgres" {
-        log.Fatalf("Only PostgreSQL database is supported. Set DB_DRIVER=postgres")
-    }
-
-    // Initialize PostgreSQL connection
-    dsn := fmt.Sprintf(
-        "host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
-        cfg.Database.Host, cfg.Database.Port, cfg.Database.User, 
-        cfg.Database.Password, cfg.Database.Name, cfg.Database.SSLMode,
-    )
-
-    db, err := sql.Open("postgres", dsn)
-    if err != nil {
-        log.Fatalf("Failed to open database: %v", err)
-    }
-
-    // Configure connection pool
-    db.SetMaxOpenConns(cfg.Database.MaxOpenConns)
-    db.SetMaxIdleConns(cfg.Database.MaxIdleConns)
-    db.SetConnMaxLifetime(time.Hour)
-
-    // Test connection
-    if err := db.Ping(); err != nil {
-        log.Fatalf("Failed to ping database: %v", err)
-    }
-    
     // Initialize repositories
     userRepo := users.NewPostgresRepository(db)
     categoryRepo := categories.NewPostgresRepository(db)
     taskRepo := tasks.NewPostgresRepository(db)
-    log.Printf("Using PostgreSQL database: %s@%s:%s/%s", 
-        cfg.Database.User, cfg.Database.Host, cfg.Database.Port, cfg.Database.Name)
+    reminderRepo := reminders.NewPostgresRepository(db)
+    tagRepo := tags.NewPostgresRepository(db)
+    commentRepo := comments.NewPostgresRepository(db)
+    timeRepo := timetracking.NewPostgresRepository(db)
+    stateRepo := states.NewPostgresRepository(db)
+    shareRepo := shares.NewPostgresRepository(db)
+    workspaceRepo := workspaces.NewPostgresRepository(db)
+    calendarRepo := calendar.NewPostgresRepository(db)
     
     // Initialize profile picture service
     profilePicService := storage.NewProfilePictureService()
@@ -78,9 +63,40 @@ func NewRouter(cfg *config.Config) *gin.Engine {
     categorySvc := categories.NewService(categoryRepo)
     categoryHandler := categories.NewHandler(categorySvc)
     
-    taskSvc := tasks.NewService(taskRepo, categoryRepo)
+    taskSvc := tasks.NewService(taskRepo, categoryRepo, userRepo, tasks.Options{
+        CompleteParentPolicy: cfg.Tasks.CompleteParentPolicy,
+        DeleteParentPolicy:   cfg.Tasks.DeleteParentPolicy,
+        UrgentWithinDays:     cfg.Tasks.UrgentWithinDays,
+        AssignmentHooks:      []tasks.AssignmentHook{reminders.NewAssignmentNotifier(reminderRepo)},
+    })
     taskHandler := tasks.NewHandler(taskSvc)
 
+    reminderSvc := reminders.NewService(reminderRepo, taskRepo, reminders.SystemClock{})
+    reminderHandler := reminders.NewHandler(reminderSvc)
+
+    trashHandler := trash.NewHandler(taskSvc, categorySvc)
+
+    tagSvc := tags.NewService(tagRepo, taskRepo)
+    tagHandler := tags.NewHandler(tagSvc)
+
+    commentSvc := comments.NewService(commentRepo, taskRepo)
+    commentHandler := comments.NewHandler(commentSvc)
+
+    timeSvc := timetracking.NewService(timeRepo, taskRepo)
+    timeHandler := timetracking.NewHandler(timeSvc)
+
+    stateSvc := states.NewService(stateRepo)
+    stateHandler := states.NewHandler(stateSvc)
+
+    shareSvc := shares.NewService(shareRepo, taskRepo, categoryRepo, userRepo)
+    shareHandler := shares.NewHandler(shareSvc)
+
+    workspaceSvc := workspaces.NewService(workspaceRepo, userRepo)
+    workspaceHandler := workspaces.NewHandler(workspaceSvc)
+
+    calendarSvc := calendar.NewService(calendarRepo, taskSvc, categorySvc)
+    calendarHandler := calendar.NewHandler(calendarSvc)
+
     api := router.Group("/api")
     {
         authGroup := api.Group("/auth")
@@ -88,15 +104,19 @@ func NewRouter(cfg *config.Config) *gin.Engine {
         authGroup.POST("/login", userHandler.Login)
         authGroup.POST("/logout", userHandler.Logout)
 
+        // Calendar feeds are read by calendar apps, authenticated by the secret token in the path
+        api.GET("/ical/:token", calendarHandler.Feed) // GET /api/ical/<token>.ics
+
         // Protected routes requiring authentication
         protected := api.Group("/protected")
-        protected.Use(AuthMiddleware(tokenMgr, sessionStore.IsRevoked))
+        protected.Use(AuthMiddleware(tokenMgr, sessionStore.IsRevoked), UserLocaleMiddleware(userSvc.GetLocale), WorkspaceMiddleware(workspaceSvc.GetRole))
         {
             // User profile endpoint
             protected.GET("/me", func(c *gin.Context) {
                 claims, _ := c.Get("claims")
-                c.JSON(200, gin.H{"claims": claims, "app": cfg.App.Name})
+                c.JSON(200, gin.H{"claims": claims, "app": cfg.App.Name, "locale": c.GetString("locale")})
             })
+            protected.PUT("/me/locale", userHandler.SetLocale) // Set or clear the preferred language of API messages
 
             // Protected Categories endpoints
             categoriesGroup := protected.Group("/categories")
@@ -105,13 +125,107 @@ func NewRouter(cfg *config.Config) *gin.Engine {
             categoriesGroup.GET("/:id", categoryHandler.GetByID)
             categoriesGroup.PUT("/:id", categoryHandler.Update)
             categoriesGroup.DELETE("/:id", categoryHandler.Delete)
+            categoriesGroup.POST("/:id/restore", categoryHandler.Restore)
+            categoriesGroup.POST("/:id/shares", shareHandler.ShareCategory) // Share your tasks in a category
+            categoriesGroup.GET("/:id/shares", shareHandler.GetByCategory)  // Who your tasks in a category are shared with
 
             // Task endpoints
             protected.POST("/tasks", taskHandler.Create)       // Create task with category association
             protected.GET("/tasks", taskHandler.GetAll)        // Get all tasks with optional filtering
             protected.GET("/tasks/:id", taskHandler.GetByID)   // Get task details by ID
             protected.PUT("/tasks/:id", taskHandler.Update)    // Update task (text, state, end date)
-            protected.DELETE("/tasks/:id", taskHandler.Delete) // Delete task
+            protected.DELETE("/tasks/:id", taskHandler.Delete) // Move task to the trash
+            protected.POST("/tasks/:id/transition", taskHandler.Transition) // Start, complete, reset or reopen a task
+            protected.POST("/tasks/:id/move", taskHandler.Move)             // Place a card on the board, optionally changing its state
+            protected.GET("/board", taskHandler.GetBoard)                   // Tasks grouped by state in board order
+            protected.POST("/tasks/:id/restore", taskHandler.Restore) // Restore task from the trash
+            protected.GET("/trash", trashHandler.GetAll)              // Deleted tasks and categories
+            protected.POST("/tasks/bulk", taskHandler.Bulk)    // Batch create/update/delete/change_state/move_category
+            protected.POST("/tasks/quick", taskHandler.QuickAdd) // Create a task from a line such as "Pay rent tomorrow #Home !high"
+            protected.GET("/tasks/export.csv", taskHandler.Export) // Download the filtered task list as CSV
+            protected.GET("/tasks/export.txt", taskHandler.ExportTodoTxt) // Download the filtered task list in the todo.txt format
+            protected.GET("/tasks/export.md", taskHandler.ExportMarkdown) // Download the filtered task list as Markdown checklists
+            protected.POST("/tasks/import", taskHandler.Import)     // Create tasks from a CSV, todo.txt or Markdown file, optionally as a dry run
+            protected.GET("/tasks/matrix", taskHandler.GetMatrix) // Open tasks grouped into Eisenhower quadrants
+            protected.GET("/stats", taskHandler.GetStats)          // Counts, completion rate, streak and timeline for dashboards
+            protected.GET("/tasks/shared", taskHandler.GetShared) // Tasks other users shared with you
+            protected.GET("/tasks/:id/children", taskHandler.GetChildren) // Direct subtasks of a task
+            protected.GET("/tasks/:id/tree", taskHandler.GetTree)         // Task with all nested subtasks
+            protected.GET("/tasks/:id/history", taskHandler.GetHistory)   // Field-level change history of a task
+            protected.POST("/tasks/:id/blockers", taskHandler.AddBlocker)                // Add a blocking dependency
+            protected.DELETE("/tasks/:id/blockers/:blocker_id", taskHandler.RemoveBlocker) // Remove a blocking dependency
+            protected.POST("/tasks/:id/assign", taskHandler.Assign)                         // Make a user responsible for a task
+            protected.POST("/tasks/:id/unassign", taskHandler.Unassign)                     // Remove the assignee of a task
+            protected.POST("/tasks/:id/tags", tagHandler.Attach)                          // Attach a tag to a task
+            protected.DELETE("/tasks/:id/tags/:tag_id", tagHandler.Detach)                // Detach a tag from a task
+            protected.GET("/tasks/:id/comments", commentHandler.GetByTask)                  // Paginated comments of a task
+            protected.POST("/tasks/:id/comments", commentHandler.Create)                    // Comment on a task
+            protected.PATCH("/tasks/:id/comments/:comment_id", commentHandler.Update)       // Edit your comment
+            protected.DELETE("/tasks/:id/comments/:comment_id", commentHandler.Delete)      // Delete your comment
+            // Sharing endpoints
+            protected.POST("/tasks/:id/shares", shareHandler.ShareTask) // Share a task as viewer or editor
+            protected.GET("/tasks/:id/shares", shareHandler.GetByTask)  // Who has access to a task
+            protected.GET("/shares", shareHandler.GetAll)               // Shares you created and received
+            protected.PATCH("/shares/:id", shareHandler.Update)         // Change the role of a share
+            protected.DELETE("/shares/:id", shareHandler.Revoke)        // Revoke a share, or leave one shared with you
+            // Workspace endpoints; other endpoints work in the workspace named by the X-Workspace-ID header
+            protected.POST("/workspaces", workspaceHandler.Create)                                          // Create a workspace you own
+            protected.GET("/workspaces", workspaceHandler.GetAll)                                           // Workspaces you belong to
+            protected.GET("/workspaces/:id", workspaceHandler.GetByID)                                      // Workspace with its members
+            protected.PATCH("/workspaces/:id", workspaceHandler.Rename)                                     // Rename a workspace
+            protected.DELETE("/workspaces/:id", workspaceHandler.Delete)                                    // Delete a workspace with its tasks
+            protected.GET("/workspaces/:id/members", workspaceHandler.GetMembers)                           // Members and their roles
+            protected.PATCH("/workspaces/:id/members/:user_id", workspaceHandler.UpdateMember)              // Change the role of a member
+            protected.DELETE("/workspaces/:id/members/:user_id", workspaceHandler.RemoveMember)             // Remove a member, or leave
+            protected.POST("/workspaces/:id/invitations", workspaceHandler.Invite)                          // Invite by username or create a link
+            protected.GET("/workspaces/:id/invitations", workspaceHandler.GetInvitations)                   // Pending invitations
+            protected.DELETE("/workspaces/:id/invitations/:invitation_id", workspaceHandler.RevokeInvitation) // Revoke an invitation
+            protected.GET("/invitations", workspaceHandler.GetMyInvitations)                                // Invitations addressed to you
+            protected.POST("/invitations/:token/accept", workspaceHandler.Accept)                           // Join a workspace
+            protected.POST("/invitations/:token/decline", workspaceHandler.Decline)                         // Decline an invitation
+            // Calendar feed endpoints
+            protected.GET("/calendar/feed", calendarHandler.GetFeed)                    // Address of your iCalendar feed of due tasks
+            protected.POST("/calendar/feed/regenerate", calendarHandler.RegenerateToken) // Replace the feed token, disabling the old address
+            // Time tracking endpoints
+            protected.POST("/tasks/:id/timer/start", timeHandler.Start)           // Start a timer on a task (one running timer per user)
+            protected.POST("/timer/stop", timeHandler.Stop)                       // Stop the running timer
+            protected.GET("/timer", timeHandler.GetRunning)                       // Current running timer
+            protected.GET("/tasks/:id/time-entries", timeHandler.GetByTask)       // Time entries of a task
+            protected.POST("/tasks/:id/time-entries", timeHandler.Create)         // Log time manually
+            protected.GET("/time-entries/totals", timeHandler.Totals)             // Totals by task, category or day
+            protected.PATCH("/time-entries/:id", timeHandler.Update)              // Edit a time entry
+            protected.DELETE("/time-entries/:id", timeHandler.Delete)             // Delete a time entry
+            // Workflow state endpoints
+            protected.GET("/states", stateHandler.GetAll)        // Built-in and own states in workflow order
+            protected.POST("/states", stateHandler.Create)       // Create a workflow state
+            protected.PUT("/states/:id", stateHandler.Update)    // Rename, reorder or flag a state as done
+            protected.DELETE("/states/:id", stateHandler.Delete) // Delete a state no task is in
+            // Tag endpoints
+            protected.POST("/tags", tagHandler.Create)       // Create a tag
+            protected.GET("/tags", tagHandler.GetAll)        // List the user's tags
+            protected.PUT("/tags/:id", tagHandler.Update)    // Rename a tag
+            protected.DELETE("/tags/:id", tagHandler.Delete) // Delete a tag and detach it from every task
+            // Reminder and notification endpoints
+            protected.POST("/tasks/:id/reminders", reminderHandler.Create)                  // Schedule a reminder for a task
+            protected.GET("/tasks/:id/reminders", reminderHandler.GetByTask)                // List the reminders of a task
+            protected.DELETE("/reminders/:id", reminderHandler.Delete)                      // Cancel a reminder
+            protected.GET("/notifications", reminderHandler.GetNotifications)               // In-app notifications
+            protected.POST("/notifications/:id/read", reminderHandler.MarkNotificationRead) // Mark a notification as read
+
+            protected.GET("/tasks/series/:series_id", taskHandler.GetSeries)        // Occurrences of a recurring series
+            protected.PUT("/tasks/series/:series_id", taskHandler.UpdateSeries)     // Edit all open occurrences of a series
+            protected.POST("/tasks/series/:series_id/stop", taskHandler.StopSeries) // Stop a recurring series
+        }
+    }
+
+    // CalDAV endpoint for calendar apps, authenticated with the account's username and password
+    router.GET("/.well-known/caldav", calendarHandler.WellKnown)
+    router.Handle("PROPFIND", "/.well-known/caldav", calendarHandler.WellKnown)
+    dav := router.Group("/caldav")
+    dav.Use(BasicAuthMiddleware("Tasks", userSvc.Authenticate), UserLocaleMiddleware(userSvc.GetLocale))
+    {
+        for _, method := range calendar.DAVMethods {
+            dav.Handle(method, "/*path", calendarHandler.DAV) // /caldav/<username>/<calendar>/<name>.ics
         }
     }
 
@@ -0,0 +1,282 @@
+package i18n
+
+// english is the reference catalog. Services and handlers refer to its messages by code
+// with NewError, and Error returns the English text.
+var english = map[string]string{
+	// Generic codes, used for errors that have no entry of their own
+	"bad_request":         "bad request",
+	"unauthorized":        "unauthorized",
+	"forbidden":           "forbidden",
+	"not_found":           "not found",
+	"conflict":            "conflict",
+	"precondition_failed": "precondition failed",
+	"internal_error":      "internal server error",
+
+	// Built-in workflow states
+	"state_not_started": "Not Started",
+	"state_in_progress": "In Progress",
+	"state_completed":   "Completed",
+
+	// Authentication and users
+	"auth_header_missing":    "missing or invalid authorization header",
+	"auth_header_required":   "authorization header required",
+	"auth_header_invalid":    "invalid authorization header",
+	"token_revoked":          "token revoked",
+	"token_invalid":          "invalid token",
+	"token_claims_invalid":   "invalid token claims",
+	"token_create_failed":    "could not create token",
+	"user_not_authenticated": "user not authenticated",
+	"user_id_missing":        "user ID not found in token",
+	"user_id_invalid":        "invalid user ID format",
+	"user_not_found":         "user not found",
+	"username_taken":         "username already exists",
+	"credentials_required":   "username and password are required",
+	"credentials_invalid":    "invalid credentials",
+	"locale_unsupported":     "unsupported locale, use en or es",
+	"request_invalid":        "invalid request",
+	"request_invalid_detail": "invalid request: %s",
+	"request_format_invalid": "Invalid request format",
+
+	// Tasks
+	"task_id_invalid":              "invalid task ID",
+	"task_not_found":               "task not found",
+	"task_not_found_in_trash":      "task not found in trash",
+	"task_text_empty":              "task text cannot be empty",
+	"task_text_too_long":           "task text cannot exceed 1000 characters",
+	"task_version_mismatch":        "task was modified by another request",
+	"task_has_subtasks":            "task has subtasks, delete them first",
+	"task_unfinished_subtasks":     "task has unfinished subtasks",
+	"task_blocked":                 "task is blocked by unfinished tasks: %s",
+	"task_own_parent":              "a task cannot be its own parent",
+	"task_move_below_subtask":      "a task cannot be moved below one of its own subtasks",
+	"task_parent_not_found":        "parent task not found",
+	"task_parent_deleted":          "parent task is deleted, restore it first",
+	"task_state_not_found":         "task state not found",
+	"task_end_date_past":           "end date cannot be in the past",
+	"task_end_date_format":         "invalid end date format, use YYYY-MM-DD",
+	"task_priority_invalid":        "invalid priority, use 0 (none) to 3 (high)",
+	"task_sort_invalid":            "invalid sort parameter, use created, priority, end_date or position",
+	"task_id_param_invalid":        "invalid task_id parameter",
+	"task_limit_invalid":           "invalid limit parameter",
+	"task_offset_invalid":          "invalid offset parameter",
+	"task_tag_ids_invalid":         "invalid tag_ids parameter",
+	"task_tag_mode_invalid":        "invalid tag_mode parameter, use any or all",
+	"task_category_param_invalid":  "invalid category_id parameter",
+	"task_state_param_invalid":     "invalid state_id parameter",
+	"task_assigned_to_invalid":     "invalid assigned_to parameter, use me",
+	"task_created_by_invalid":      "invalid created_by parameter, use me",
+	"task_assignee_empty":          "assignee username cannot be empty",
+	"task_if_match_invalid":        "invalid If-Match header, expected a single ETag",
+	"task_transition_action":       "cannot %s a task that is %s",
+	"task_transition_illegal":      "illegal transition from %s to %s",
+	"task_transition_illegal_hint": "illegal transition from %s to %s, use the %s transition",
+	"task_transition_unknown":      "unknown transition: %s",
+	"tasks_trash_failed":           "failed to retrieve deleted tasks",
+	"task_permission_denied":       "you do not have permission to do this on the task",
+	"tasks_shared_failed":          "failed to retrieve shared tasks",
+	"task_board_changed":           "the board has changed, reload it and try again",
+	"task_move_self":               "a task cannot be moved next to itself",
+	"task_neighbor_not_found":      "neighbor task not found",
+	"task_neighbor_column":         "neighbor task is not in the target column",
+	"task_import_empty":            "the CSV file is empty",
+	"task_import_invalid":          "invalid CSV: %s",
+	"task_import_too_many":         "the CSV cannot have more than 1000 rows",
+	"task_import_no_rows":          "the CSV has no rows to import",
+	"task_import_unknown_field":    "unknown import field: %s",
+	"task_import_column_missing":   "column not found in the CSV header: %s",
+	"task_import_no_text":          "the CSV has no task_text column, map one with map[task_text]",
+	"task_import_important":        "invalid important value, use true or false",
+	"task_import_dry_run_invalid":  "invalid dry_run parameter, use true or false",
+	"task_import_file_missing":     "missing file in the file field",
+	"task_import_failed":           "failed to import tasks",
+	"task_import_format_invalid":   "invalid format parameter, use csv, todotxt or markdown",
+	"task_import_file_too_many":    "the file cannot have more than 1000 tasks",
+	"task_import_file_no_tasks":    "the file has no tasks to import",
+	"task_import_file_read_failed": "failed to read the file",
+	"task_import_todotxt_line":     "invalid todo.txt line %s: %s",
+	"task_import_markdown_line":    "invalid Markdown line %s: %s",
+	"task_import_pri_invalid":      "invalid pri value, use a letter from A to Z",
+	"task_import_completed_format": "invalid completion date format, use YYYY-MM-DD",
+	"task_stats_interval_invalid":  "invalid interval parameter, use day or week",
+	"task_stats_failed":            "failed to compute statistics",
+	"task_quick_category_missing":  "category not found: %s",
+
+	// Sharing
+	"share_id_invalid":        "invalid share ID",
+	"share_not_found":         "share not found",
+	"share_exists":            "already shared with this user",
+	"share_with_owner":        "a task cannot be shared with its owner",
+	"share_role_invalid":      "invalid role, use viewer or editor",
+	"share_owner_only":        "only the owner can manage the shares of a task",
+	"share_change_owner_only": "only the owner can change a share",
+	"shares_get_failed":       "failed to retrieve shares",
+	"share_in_workspace":      "workspace tasks are shared by inviting users to the workspace",
+
+	// Workspaces
+	"workspace_id_invalid":          "invalid workspace ID",
+	"workspace_header_invalid":      "invalid X-Workspace-ID header",
+	"workspace_not_found":           "workspace not found",
+	"workspace_membership_failed":   "failed to check workspace membership",
+	"workspace_name_empty":          "workspace name cannot be empty",
+	"workspace_name_long":           "workspace name cannot exceed 100 characters",
+	"workspace_permission_denied":   "you do not have permission to manage the workspace",
+	"workspace_read_only":           "guests have read-only access to the workspace",
+	"workspace_role_invalid":        "invalid role, use admin, member or guest",
+	"workspace_admins_owner_only":   "only the owner can manage admins",
+	"workspace_owner_role":          "the owner's role cannot be changed",
+	"workspace_owner_leave":         "the owner cannot leave the workspace, delete it instead",
+	"workspace_owner_remove":        "the owner cannot be removed from the workspace",
+	"workspace_member_exists":       "already a member of the workspace",
+	"workspace_member_not_found":    "member not found",
+	"workspace_assignee_not_member": "the assignee must be a member of the workspace",
+	"workspaces_get_failed":         "failed to retrieve workspaces",
+	"member_id_invalid":             "invalid user ID",
+	"invitation_id_invalid":         "invalid invitation ID",
+	"invitation_not_found":          "invitation not found",
+	"invitation_pending":            "user already has a pending invitation",
+	"invitation_create_failed":      "failed to create invitation",
+	"invitations_get_failed":        "failed to retrieve invitations",
+
+	// Bulk operations
+	"bulk_empty":          "no operations provided",
+	"bulk_too_large":      "a bulk request cannot exceed %s operations",
+	"bulk_mode_invalid":   "invalid bulk mode, use atomic or per_item",
+	"bulk_unknown_op":     "unknown operation: %s",
+	"bulk_state_required": "state_id is required for change_state",
+	"bulk_create_task_id": "task_id must not be set for create",
+	"bulk_rolled_back":    "not applied: batch rolled back",
+
+	// Dependencies
+	"dependency_self":      "a task cannot block itself",
+	"dependency_cycle":     "dependency would create a cycle",
+	"dependency_not_found": "dependency not found",
+	"blocker_not_found":    "blocker task not found",
+	"blocker_id_invalid":   "invalid blocker ID",
+
+	// Recurrence
+	"recurrence_invalid":            "invalid recurrence rule: %s",
+	"recurrence_empty":              "recurrence rule cannot be empty",
+	"recurrence_empty_stop":         "recurrence rule cannot be empty, stop the series instead",
+	"recurrence_needs_end_date":     "recurring tasks require an end date",
+	"recurrence_freq_required":      "recurrence rule requires FREQ",
+	"recurrence_freq_unsupported":   "unsupported FREQ %s, use DAILY, WEEKLY, MONTHLY or YEARLY",
+	"recurrence_part_invalid":       "invalid recurrence rule part %s",
+	"recurrence_part_duplicate":     "duplicate recurrence rule part %s",
+	"recurrence_part_unsupported":   "unsupported recurrence rule part %s",
+	"recurrence_interval_invalid":   "INTERVAL must be a positive integer",
+	"recurrence_count_invalid":      "COUNT must be a positive integer",
+	"recurrence_count_until":        "COUNT and UNTIL cannot be used together",
+	"recurrence_until_invalid":      "invalid UNTIL value %s",
+	"recurrence_byday_invalid":      "invalid BYDAY value %s",
+	"recurrence_byday_numbered":     "numbered BYDAY values are only allowed with MONTHLY",
+	"recurrence_bymonthday_invalid": "invalid BYMONTHDAY value %s",
+	"recurrence_bymonthday_weekly":  "BYMONTHDAY cannot be used with WEEKLY",
+	"recurrence_yearly_by":          "BYDAY and BYMONTHDAY are not supported with YEARLY",
+	"recurrence_wkst":               "only WKST=MO is supported",
+	"series_id_invalid":             "invalid series ID",
+	"series_not_found":              "series not found",
+
+	// Categories
+	"category_id_invalid":         "invalid category ID",
+	"category_id_invalid_title":   "Invalid category ID",
+	"category_not_found":          "category not found",
+	"category_not_found_title":    "Category not found",
+	"category_not_found_in_trash": "category not found in trash",
+	"category_not_in_trash_title": "Category not found in trash",
+	"category_missing":            "category does not exist",
+	"category_name_empty":         "category name cannot be empty",
+	"category_name_too_long":      "category name cannot exceed 100 characters",
+	"category_description_long":   "category description cannot exceed 500 characters",
+	"category_version_mismatch":   "category was modified by another request",
+	"categories_get_failed":       "Failed to retrieve categories",
+	"category_get_failed":         "Failed to retrieve category",
+	"category_delete_failed":      "Failed to delete category",
+	"category_restore_failed":     "Failed to restore category",
+	"categories_trash_failed":     "failed to retrieve deleted categories",
+
+	// Tags
+	"tag_id_invalid":    "invalid tag ID",
+	"tag_not_found":     "tag not found",
+	"tag_exists":        "tag already exists",
+	"tag_name_empty":    "tag name cannot be empty",
+	"tag_name_too_long": "tag name cannot exceed 50 characters",
+	"tag_not_attached":  "tag is not attached to this task",
+	"tags_get_failed":   "failed to retrieve tags",
+
+	// Comments
+	"comment_id_invalid": "invalid comment ID",
+	"comment_not_found":  "comment not found",
+	"comment_body_empty": "comment body cannot be empty",
+	"comment_body_long":  "comment body cannot exceed 10000 characters",
+	"comment_not_author": "only the author can change a comment",
+
+	// Reminders and notifications
+	"reminder_id_invalid":         "invalid reminder ID",
+	"reminder_not_found":          "reminder not found",
+	"reminder_channel_invalid":    "invalid channel, use in_app, email or webhook",
+	"reminder_remind_at_format":   "invalid remind_at format, use RFC 3339 (e.g. 2024-01-20T09:00:00Z)",
+	"reminder_remind_at_past":     "remind_at must be in the future",
+	"reminder_time_required":      "remind_at or offset_minutes is required",
+	"reminder_time_both":          "use either remind_at or offset_minutes, not both",
+	"reminder_offset_negative":    "offset_minutes cannot be negative",
+	"reminder_offset_no_end_date": "offset reminders require the task to have an end date",
+	"reminder_email_target":       "email reminders require a valid target email address",
+	"reminder_webhook_target":     "webhook reminders require a valid http(s) target URL",
+	"reminder_webhook_private":    "webhook reminders cannot target loopback or private addresses",
+	"reminder_email_disabled":     "email notifier is not configured (SMTP_HOST is empty)",
+	"notification_id_invalid":     "invalid notification ID",
+	"notification_not_found":      "notification not found",
+	"notifications_get_failed":    "failed to retrieve notifications",
+
+	// Time tracking
+	"time_entry_id_invalid":     "invalid time entry ID",
+	"time_entry_not_found":      "time entry not found",
+	"timer_running":             "a timer is already running, stop it first",
+	"timer_not_running":         "no timer is running",
+	"time_format_invalid":       "invalid %s format, use RFC 3339 (e.g. 2024-01-02T15:04:05Z)",
+	"time_started_future":       "started_at cannot be in the future",
+	"time_stopped_future":       "stopped_at cannot be in the future",
+	"time_stopped_before_start": "stopped_at cannot be before started_at",
+	"time_from_invalid":         "invalid from parameter, use YYYY-MM-DD",
+	"time_to_invalid":           "invalid to parameter, use YYYY-MM-DD",
+	"time_range_order":          "from must be before to",
+	"time_range_too_long":       "date range cannot exceed 366 days",
+	"time_group_by_invalid":     "group_by must be task, category or day",
+
+	// Workflow states
+	"state_id_invalid":        "invalid state ID",
+	"state_not_found":         "state not found",
+	"state_exists":            "state already exists",
+	"state_description_empty": "state description cannot be empty",
+	"state_description_long":  "state description cannot exceed 100 characters",
+	"state_built_in":          "built-in states cannot be changed",
+	"state_in_use":            "state is in use, move its tasks to another state first",
+	"states_get_failed":       "failed to retrieve states",
+
+	// Calendar feeds
+	"calendar_feed_not_found":       "calendar feed not found",
+	"calendar_feed_get_failed":      "failed to retrieve calendar feed",
+	"calendar_feed_regen_failed":    "failed to regenerate calendar feed",
+	"calendar_feed_create_failed":   "failed to create calendar feed",
+	"calendar_feed_render_failed":   "failed to render calendar feed",
+	"calendar_category_ids_invalid": "invalid category_ids parameter",
+	"calendar_component_invalid":    "invalid component parameter, use vevent or vtodo",
+	"calendar_only_open_invalid":    "invalid only_open parameter, use true or false",
+
+	// CalDAV
+	"caldav_username_missing":      "username not found in token",
+	"caldav_method_not_allowed":    "method not allowed",
+	"caldav_report_target":         "reports are only available on calendars",
+	"caldav_object_target":         "only to-dos can be read, written or deleted",
+	"caldav_xml_invalid":           "invalid XML request body",
+	"caldav_object_read_failed":    "failed to read calendar object",
+	"caldav_object_too_large":      "calendar object is too large",
+	"caldav_object_invalid":        "invalid calendar object, expected a VCALENDAR holding a VTODO",
+	"caldav_object_name_invalid":   "calendar object names must end in .ics",
+	"caldav_collection_not_found":  "calendar not found",
+	"caldav_object_not_found":      "calendar object not found",
+	"caldav_object_exists":         "calendar object already exists",
+	"caldav_other_collection":      "the task belongs to another calendar",
+	"caldav_component_unsupported": "calendars only hold to-dos (VTODO)",
+	"caldav_sync_token_invalid":    "invalid sync token",
+}
@@ -0,0 +1,265 @@
+package i18n
+
+// spanish translates every code of the English catalog, keeping its placeholders
+var spanish = map[string]string{
+	"bad_request":         "solicitud incorrecta",
+	"unauthorized":        "no autorizado",
+	"forbidden":           "acceso denegado",
+	"not_found":           "no encontrado",
+	"conflict":            "conflicto",
+	"precondition_failed": "la condición previa ha fallado",
+	"internal_error":      "error interno del servidor",
+
+	"state_not_started": "Sin Empezar",
+	"state_in_progress": "En Progreso",
+	"state_completed":   "Completada",
+
+	"auth_header_missing":    "falta la cabecera de autorización o no es válida",
+	"auth_header_required":   "la cabecera de autorización es obligatoria",
+	"auth_header_invalid":    "cabecera de autorización no válida",
+	"token_revoked":          "token revocado",
+	"token_invalid":          "token no válido",
+	"token_claims_invalid":   "claims del token no válidos",
+	"token_create_failed":    "no se pudo crear el token",
+	"user_not_authenticated": "usuario no autenticado",
+	"user_id_missing":        "no se encontró el ID de usuario en el token",
+	"user_id_invalid":        "formato de ID de usuario no válido",
+	"user_not_found":         "usuario no encontrado",
+	"username_taken":         "el nombre de usuario ya existe",
+	"credentials_required":   "el usuario y la contraseña son obligatorios",
+	"credentials_invalid":    "credenciales no válidas",
+	"locale_unsupported":     "idioma no soportado, usa en o es",
+	"request_invalid":        "solicitud no válida",
+	"request_invalid_detail": "solicitud no válida: %s",
+	"request_format_invalid": "Formato de solicitud no válido",
+
+	"task_id_invalid":              "ID de tarea no válido",
+	"task_not_found":               "tarea no encontrada",
+	"task_not_found_in_trash":      "tarea no encontrada en la papelera",
+	"task_text_empty":              "el texto de la tarea no puede estar vacío",
+	"task_text_too_long":           "el texto de la tarea no puede superar los 1000 caracteres",
+	"task_version_mismatch":        "la tarea ha sido modificada por otra solicitud",
+	"task_has_subtasks":            "la tarea tiene subtareas, elimínalas primero",
+	"task_unfinished_subtasks":     "la tarea tiene subtareas sin terminar",
+	"task_blocked":                 "la tarea está bloqueada por tareas sin terminar: %s",
+	"task_own_parent":              "una tarea no puede ser su propia tarea padre",
+	"task_move_below_subtask":      "una tarea no puede moverse debajo de una de sus subtareas",
+	"task_parent_not_found":        "tarea padre no encontrada",
+	"task_parent_deleted":          "la tarea padre está eliminada, restáurala primero",
+	"task_state_not_found":         "estado de la tarea no encontrado",
+	"task_end_date_past":           "la fecha de fin no puede estar en el pasado",
+	"task_end_date_format":         "formato de fecha de fin no válido, usa AAAA-MM-DD",
+	"task_priority_invalid":        "prioridad no válida, usa de 0 (ninguna) a 3 (alta)",
+	"task_sort_invalid":            "parámetro sort no válido, usa created, priority, end_date o position",
+	"task_id_param_invalid":        "parámetro task_id no válido",
+	"task_limit_invalid":           "parámetro limit no válido",
+	"task_offset_invalid":          "parámetro offset no válido",
+	"task_tag_ids_invalid":         "parámetro tag_ids no válido",
+	"task_tag_mode_invalid":        "parámetro tag_mode no válido, usa any o all",
+	"task_category_param_invalid":  "parámetro category_id no válido",
+	"task_state_param_invalid":     "parámetro state_id no válido",
+	"task_assigned_to_invalid":     "parámetro assigned_to no válido, usa me",
+	"task_created_by_invalid":      "parámetro created_by no válido, usa me",
+	"task_assignee_empty":          "el nombre de usuario del responsable no puede estar vacío",
+	"task_if_match_invalid":        "cabecera If-Match no válida, se espera un único ETag",
+	"task_transition_action":       "no se puede aplicar %s a una tarea en estado %s",
+	"task_transition_illegal":      "transición no permitida de %s a %s",
+	"task_transition_illegal_hint": "transición no permitida de %s a %s, usa la transición %s",
+	"task_transition_unknown":      "transición desconocida: %s",
+	"tasks_trash_failed":           "no se pudieron obtener las tareas eliminadas",
+	"task_permission_denied":       "no tienes permiso para hacer esto en la tarea",
+	"tasks_shared_failed":          "no se pudieron obtener las tareas compartidas",
+	"task_board_changed":           "el tablero ha cambiado, vuelve a cargarlo e inténtalo de nuevo",
+	"task_move_self":               "una tarea no se puede mover junto a sí misma",
+	"task_neighbor_not_found":      "tarea vecina no encontrada",
+	"task_neighbor_column":         "la tarea vecina no está en la columna de destino",
+	"task_import_empty":            "el archivo CSV está vacío",
+	"task_import_invalid":          "CSV no válido: %s",
+	"task_import_too_many":         "el CSV no puede tener más de 1000 filas",
+	"task_import_no_rows":          "el CSV no tiene filas que importar",
+	"task_import_unknown_field":    "campo de importación desconocido: %s",
+	"task_import_column_missing":   "columna no encontrada en la cabecera del CSV: %s",
+	"task_import_no_text":          "el CSV no tiene columna task_text, asigna una con map[task_text]",
+	"task_import_important":        "valor de important no válido, usa true o false",
+	"task_import_dry_run_invalid":  "parámetro dry_run no válido, usa true o false",
+	"task_import_file_missing":     "falta el archivo en el campo file",
+	"task_import_failed":           "no se pudieron importar las tareas",
+	"task_import_format_invalid":   "parámetro format no válido, usa csv, todotxt o markdown",
+	"task_import_file_too_many":    "el archivo no puede tener más de 1000 tareas",
+	"task_import_file_no_tasks":    "el archivo no tiene tareas para importar",
+	"task_import_file_read_failed": "no se pudo leer el archivo",
+	"task_import_todotxt_line":     "línea %s de todo.txt no válida: %s",
+	"task_import_markdown_line":    "línea %s de Markdown no válida: %s",
+	"task_import_pri_invalid":      "valor de pri no válido, usa una letra de la A a la Z",
+	"task_import_completed_format": "formato de fecha de finalización no válido, usa AAAA-MM-DD",
+	"task_stats_interval_invalid":  "parámetro interval no válido, usa day o week",
+	"task_stats_failed":            "no se pudieron calcular las estadísticas",
+	"task_quick_category_missing":  "categoría no encontrada: %s",
+
+	"share_id_invalid":        "ID de compartición no válido",
+	"share_not_found":         "compartición no encontrada",
+	"share_exists":            "ya está compartido con este usuario",
+	"share_with_owner":        "una tarea no se puede compartir con su propietario",
+	"share_role_invalid":      "rol no válido, usa viewer o editor",
+	"share_owner_only":        "solo el propietario puede gestionar con quién se comparte una tarea",
+	"share_change_owner_only": "solo el propietario puede modificar una compartición",
+	"shares_get_failed":       "no se pudieron obtener las comparticiones",
+	"share_in_workspace":      "las tareas de un espacio de trabajo se comparten invitando a usuarios al espacio",
+
+	"workspace_id_invalid":          "ID de espacio de trabajo no válido",
+	"workspace_header_invalid":      "cabecera X-Workspace-ID no válida",
+	"workspace_not_found":           "espacio de trabajo no encontrado",
+	"workspace_membership_failed":   "no se pudo comprobar la pertenencia al espacio de trabajo",
+	"workspace_name_empty":          "el nombre del espacio de trabajo no puede estar vacío",
+	"workspace_name_long":           "el nombre del espacio de trabajo no puede superar los 100 caracteres",
+	"workspace_permission_denied":   "no tienes permiso para gestionar el espacio de trabajo",
+	"workspace_read_only":           "los invitados solo tienen acceso de lectura al espacio de trabajo",
+	"workspace_role_invalid":        "rol no válido, usa admin, member o guest",
+	"workspace_admins_owner_only":   "solo el propietario puede gestionar a los administradores",
+	"workspace_owner_role":          "el rol del propietario no se puede cambiar",
+	"workspace_owner_leave":         "el propietario no puede abandonar el espacio de trabajo, elimínalo en su lugar",
+	"workspace_owner_remove":        "el propietario no puede ser expulsado del espacio de trabajo",
+	"workspace_member_exists":       "ya es miembro del espacio de trabajo",
+	"workspace_member_not_found":    "miembro no encontrado",
+	"workspace_assignee_not_member": "el responsable debe ser miembro del espacio de trabajo",
+	"workspaces_get_failed":         "no se pudieron obtener los espacios de trabajo",
+	"member_id_invalid":             "ID de usuario no válido",
+	"invitation_id_invalid":         "ID de invitación no válido",
+	"invitation_not_found":          "invitación no encontrada",
+	"invitation_pending":            "el usuario ya tiene una invitación pendiente",
+	"invitation_create_failed":      "no se pudo crear la invitación",
+	"invitations_get_failed":        "no se pudieron obtener las invitaciones",
+
+	"bulk_empty":          "no se ha indicado ninguna operación",
+	"bulk_too_large":      "una solicitud masiva no puede superar las %s operaciones",
+	"bulk_mode_invalid":   "modo masivo no válido, usa atomic o per_item",
+	"bulk_unknown_op":     "operación desconocida: %s",
+	"bulk_state_required": "state_id es obligatorio para change_state",
+	"bulk_create_task_id": "task_id no debe indicarse en create",
+	"bulk_rolled_back":    "no aplicada: el lote se ha revertido",
+
+	"dependency_self":      "una tarea no puede bloquearse a sí misma",
+	"dependency_cycle":     "la dependencia crearía un ciclo",
+	"dependency_not_found": "dependencia no encontrada",
+	"blocker_not_found":    "tarea bloqueante no encontrada",
+	"blocker_id_invalid":   "ID de tarea bloqueante no válido",
+
+	"recurrence_invalid":            "regla de recurrencia no válida: %s",
+	"recurrence_empty":              "la regla de recurrencia no puede estar vacía",
+	"recurrence_empty_stop":         "la regla de recurrencia no puede estar vacía, detén la serie en su lugar",
+	"recurrence_needs_end_date":     "las tareas recurrentes requieren una fecha de fin",
+	"recurrence_freq_required":      "la regla de recurrencia requiere FREQ",
+	"recurrence_freq_unsupported":   "FREQ %s no soportado, usa DAILY, WEEKLY, MONTHLY o YEARLY",
+	"recurrence_part_invalid":       "parte de la regla de recurrencia no válida %s",
+	"recurrence_part_duplicate":     "parte de la regla de recurrencia duplicada %s",
+	"recurrence_part_unsupported":   "parte de la regla de recurrencia no soportada %s",
+	"recurrence_interval_invalid":   "INTERVAL debe ser un entero positivo",
+	"recurrence_count_invalid":      "COUNT debe ser un entero positivo",
+	"recurrence_count_until":        "COUNT y UNTIL no pueden usarse juntos",
+	"recurrence_until_invalid":      "valor de UNTIL no válido %s",
+	"recurrence_byday_invalid":      "valor de BYDAY no válido %s",
+	"recurrence_byday_numbered":     "los valores numerados de BYDAY solo se permiten con MONTHLY",
+	"recurrence_bymonthday_invalid": "valor de BYMONTHDAY no válido %s",
+	"recurrence_bymonthday_weekly":  "BYMONTHDAY no puede usarse con WEEKLY",
+	"recurrence_yearly_by":          "BYDAY y BYMONTHDAY no están soportados con YEARLY",
+	"recurrence_wkst":               "solo se admite WKST=MO",
+	"series_id_invalid":             "ID de serie no válido",
+	"series_not_found":              "serie no encontrada",
+
+	"category_id_invalid":         "ID de categoría no válido",
+	"category_id_invalid_title":   "ID de categoría no válido",
+	"category_not_found":          "categoría no encontrada",
+	"category_not_found_title":    "Categoría no encontrada",
+	"category_not_found_in_trash": "categoría no encontrada en la papelera",
+	"category_not_in_trash_title": "Categoría no encontrada en la papelera",
+	"category_missing":            "la categoría no existe",
+	"category_name_empty":         "el nombre de la categoría no puede estar vacío",
+	"category_name_too_long":      "el nombre de la categoría no puede superar los 100 caracteres",
+	"category_description_long":   "la descripción de la categoría no puede superar los 500 caracteres",
+	"category_version_mismatch":   "la categoría ha sido modificada por otra solicitud",
+	"categories_get_failed":       "No se pudieron obtener las categorías",
+	"category_get_failed":         "No se pudo obtener la categoría",
+	"category_delete_failed":      "No se pudo eliminar la categoría",
+	"category_restore_failed":     "No se pudo restaurar la categoría",
+	"categories_trash_failed":     "no se pudieron obtener las categorías eliminadas",
+
+	"tag_id_invalid":    "ID de etiqueta no válido",
+	"tag_not_found":     "etiqueta no encontrada",
+	"tag_exists":        "la etiqueta ya existe",
+	"tag_name_empty":    "el nombre de la etiqueta no puede estar vacío",
+	"tag_name_too_long": "el nombre de la etiqueta no puede superar los 50 caracteres",
+	"tag_not_attached":  "la etiqueta no está asignada a esta tarea",
+	"tags_get_failed":   "no se pudieron obtener las etiquetas",
+
+	"comment_id_invalid": "ID de comentario no válido",
+	"comment_not_found":  "comentario no encontrado",
+	"comment_body_empty": "el comentario no puede estar vacío",
+	"comment_body_long":  "el comentario no puede superar los 10000 caracteres",
+	"comment_not_author": "solo el autor puede modificar un comentario",
+
+	"reminder_id_invalid":         "ID de recordatorio no válido",
+	"reminder_not_found":          "recordatorio no encontrado",
+	"reminder_channel_invalid":    "canal no válido, usa in_app, email o webhook",
+	"reminder_remind_at_format":   "formato de remind_at no válido, usa RFC 3339 (p. ej. 2024-01-20T09:00:00Z)",
+	"reminder_remind_at_past":     "remind_at debe estar en el futuro",
+	"reminder_time_required":      "remind_at u offset_minutes es obligatorio",
+	"reminder_time_both":          "usa remind_at u offset_minutes, no ambos",
+	"reminder_offset_negative":    "offset_minutes no puede ser negativo",
+	"reminder_offset_no_end_date": "los recordatorios con desplazamiento requieren que la tarea tenga fecha de fin",
+	"reminder_email_target":       "los recordatorios por email requieren una dirección de email válida",
+	"reminder_webhook_target":     "los recordatorios por webhook requieren una URL http(s) válida",
+	"reminder_webhook_private":    "los recordatorios por webhook no pueden apuntar a direcciones locales o privadas",
+	"reminder_email_disabled":     "el envío de emails no está configurado (SMTP_HOST está vacío)",
+	"notification_id_invalid":     "ID de notificación no válido",
+	"notification_not_found":      "notificación no encontrada",
+	"notifications_get_failed":    "no se pudieron obtener las notificaciones",
+
+	"time_entry_id_invalid":     "ID de registro de tiempo no válido",
+	"time_entry_not_found":      "registro de tiempo no encontrado",
+	"timer_running":             "ya hay un temporizador en marcha, detenlo primero",
+	"timer_not_running":         "no hay ningún temporizador en marcha",
+	"time_format_invalid":       "formato de %s no válido, usa RFC 3339 (p. ej. 2024-01-02T15:04:05Z)",
+	"time_started_future":       "started_at no puede estar en el futuro",
+	"time_stopped_future":       "stopped_at no puede estar en el futuro",
+	"time_stopped_before_start": "stopped_at no puede ser anterior a started_at",
+	"time_from_invalid":         "parámetro from no válido, usa AAAA-MM-DD",
+	"time_to_invalid":           "parámetro to no válido, usa AAAA-MM-DD",
+	"time_range_order":          "from debe ser anterior a to",
+	"time_range_too_long":       "el rango de fechas no puede superar los 366 días",
+	"time_group_by_invalid":     "group_by debe ser task, category o day",
+
+	"state_id_invalid":        "ID de estado no válido",
+	"state_not_found":         "estado no encontrado",
+	"state_exists":            "el estado ya existe",
+	"state_description_empty": "la descripción del estado no puede estar vacía",
+	"state_description_long":  "la descripción del estado no puede superar los 100 caracteres",
+	"state_built_in":          "los estados predefinidos no se pueden modificar",
+	"state_in_use":            "el estado está en uso, mueve sus tareas a otro estado primero",
+	"states_get_failed":       "no se pudieron obtener los estados",
+
+	"calendar_feed_not_found":       "calendario no encontrado",
+	"calendar_feed_get_failed":      "no se pudo obtener el calendario",
+	"calendar_feed_regen_failed":    "no se pudo regenerar el calendario",
+	"calendar_feed_create_failed":   "no se pudo crear el calendario",
+	"calendar_feed_render_failed":   "no se pudo generar el calendario",
+	"calendar_category_ids_invalid": "parámetro category_ids no válido",
+	"calendar_component_invalid":    "parámetro component no válido, usa vevent o vtodo",
+	"calendar_only_open_invalid":    "parámetro only_open no válido, usa true o false",
+
+	// CalDAV
+	"caldav_username_missing":      "no se encontró el nombre de usuario en el token",
+	"caldav_method_not_allowed":    "método no permitido",
+	"caldav_report_target":         "los informes solo están disponibles en los calendarios",
+	"caldav_object_target":         "solo se pueden leer, escribir o eliminar tareas pendientes",
+	"caldav_xml_invalid":           "cuerpo de la solicitud XML no válido",
+	"caldav_object_read_failed":    "error al leer el objeto de calendario",
+	"caldav_object_too_large":      "el objeto de calendario es demasiado grande",
+	"caldav_object_invalid":        "objeto de calendario no válido, se esperaba un VCALENDAR con un VTODO",
+	"caldav_object_name_invalid":   "los nombres de los objetos de calendario deben terminar en .ics",
+	"caldav_collection_not_found":  "calendario no encontrado",
+	"caldav_object_not_found":      "objeto de calendario no encontrado",
+	"caldav_object_exists":         "el objeto de calendario ya existe",
+	"caldav_other_collection":      "la tarea pertenece a otro calendario",
+	"caldav_component_unsupported": "los calendarios solo contienen tareas pendientes (VTODO)",
+	"caldav_sync_token_invalid":    "token de sincronización no válido",
+}
@@ -0,0 +1,72 @@
+package i18n
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Error is an error whose message is a catalog entry. Error returns the English text, so
+// it reads like any other error in logs, and LocalizeErrors renders it in the request
+// locale with its code.
+type Error struct {
+	Code string
+	Args []interface{} // Fill the %s placeholders of the message, in order
+}
+
+// NewError returns an error with the message of the code, filled in with args
+func NewError(code string, args ...interface{}) error {
+	return &Error{Code: code, Args: args}
+}
+
+func (e *Error) Error() string {
+	return e.Localize(English)
+}
+
+// Localize returns the message in the locale. Arguments that are coded errors themselves are
+// localized too, e.g. the cause in "invalid recurrence rule: COUNT must be a positive integer".
+func (e *Error) Localize(locale string) string {
+	args := make([]interface{}, len(e.Args))
+	for i, arg := range e.Args {
+		var coded *Error
+		if err, ok := arg.(error); ok && errors.As(err, &coded) {
+			args[i] = coded.Localize(locale)
+			continue
+		}
+		args[i] = fmt.Sprint(arg)
+	}
+	return T(locale, e.Code, args...)
+}
+
+// Unwrap returns the arguments that are errors, so errors.Is and errors.As see the causes
+// of a coded error as they would through fmt.Errorf and %w
+func (e *Error) Unwrap() []error {
+	var causes []error
+	for _, arg := range e.Args {
+		if err, ok := arg.(error); ok {
+			causes = append(causes, err)
+		}
+	}
+	return causes
+}
+
+// Describe returns the code of err and its message in the request locale, for errors reported
+// inside a successful response such as the rows of an import. Errors without a code are internal
+// and get the generic "internal_error" text, as LocalizeErrors does for server errors.
+func Describe(ctx context.Context, err error) (code string, message string) {
+	locale := FromContext(ctx)
+	var coded *Error
+	if errors.As(err, &coded) {
+		return coded.Code, coded.Localize(locale)
+	}
+	return "internal_error", T(locale, "internal_error")
+}
+
+// RespondError writes err as the JSON error response of the request and records it on the
+// context, where LocalizeErrors finds its code
+func RespondError(c *gin.Context, status int, err error) {
+	c.Error(err)
+	c.AbortWithStatusJSON(status, gin.H{"error": err.Error()})
+}
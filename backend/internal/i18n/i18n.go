@@ -0,0 +1,123 @@
+// Package i18n provides the message catalogs used to localize API responses,
+// locale negotiation and the request context plumbing for the selected locale.
+package i18n
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Supported locales
+const (
+	English = "en"
+	Spanish = "es"
+)
+
+// DefaultLocale is used when nothing else selects a supported locale
+const DefaultLocale = English
+
+// catalogs holds the messages of every supported locale, keyed by message code.
+// Messages may contain %s placeholders, filled in the same order in every locale.
+var catalogs = map[string]map[string]string{
+	English: english,
+	Spanish: spanish,
+}
+
+// IsSupported reports whether there is a catalog for the locale
+func IsSupported(locale string) bool {
+	_, ok := catalogs[locale]
+	return ok
+}
+
+// Locales returns the supported locales in alphabetical order
+func Locales() []string {
+	locales := make([]string, 0, len(catalogs))
+	for locale := range catalogs {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// T returns the message with the given code in the locale, falling back to English.
+// Unknown codes are returned unchanged.
+func T(locale string, code string, args ...interface{}) string {
+	text, ok := catalogs[locale][code]
+	if !ok {
+		if text, ok = english[code]; !ok {
+			return code
+		}
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(text, args...)
+	}
+	return text
+}
+
+// Negotiate picks the best supported locale from an Accept-Language header,
+// e.g. "es-ES,es;q=0.9,en;q=0.8". It returns "" when no supported locale is acceptable.
+func Negotiate(acceptLanguage string) string {
+	best, bestQ := "", 0.0
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		tag := strings.ToLower(strings.TrimSpace(fields[0]))
+		if tag == "" {
+			continue
+		}
+
+		q := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				value, err := strconv.ParseFloat(param[2:], 64)
+				if err != nil {
+					value = 0
+				}
+				q = value
+			}
+		}
+
+		// Match on the primary language subtag: "es-MX" selects "es"
+		locale := strings.SplitN(tag, "-", 2)[0]
+		if !IsSupported(locale) || q <= bestQ {
+			continue
+		}
+		best, bestQ = locale, q
+	}
+
+	return best
+}
+
+// builtInStates maps the IDs of the built-in workflow states seeded by the schema to their message codes
+var builtInStates = map[int64]string{
+	1: "state_not_started",
+	2: "state_in_progress",
+	3: "state_completed",
+}
+
+// StateName returns the localized name of a built-in workflow state. User-defined
+// states are not translated, their description is returned as is.
+func StateName(locale string, id int64, description string) string {
+	if code, ok := builtInStates[id]; ok {
+		return T(locale, code)
+	}
+	return description
+}
+
+type contextKey struct{}
+
+// WithLocale returns a copy of ctx carrying the locale selected for the request
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, contextKey{}, locale)
+}
+
+// FromContext returns the locale selected for the request, or DefaultLocale
+func FromContext(ctx context.Context) string {
+	if locale, ok := ctx.Value(contextKey{}).(string); ok && locale != "" {
+		return locale
+	}
+	return DefaultLocale
+}
@@ -0,0 +1,404 @@
+package recurrence
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"backend/root/internal/i18n"
+)
+
+// Frequency is the FREQ part of an RFC 5545 recurrence rule
+type Frequency string
+
+// Supported frequencies
+const (
+	Daily   Frequency = "DAILY"
+	Weekly  Frequency = "WEEKLY"
+	Monthly Frequency = "MONTHLY"
+	Yearly  Frequency = "YEARLY"
+)
+
+// maxPeriods bounds how many periods Next scans before giving up, so rules that
+// can never match (e.g. BYMONTHDAY=31 with INTERVAL=2 starting in February) terminate
+const maxPeriods = 1000
+
+// WeekdayNum is a BYDAY entry such as MO, 2TU or -1FR. N is 0 when no ordinal is given.
+type WeekdayNum struct {
+	Day time.Weekday
+	N   int
+}
+
+// Rule is a parsed subset of an RFC 5545 RRULE: FREQ, INTERVAL, BYDAY, BYMONTHDAY, COUNT and UNTIL.
+// Weeks always start on Monday.
+type Rule struct {
+	Freq       Frequency
+	Interval   int
+	ByDay      []WeekdayNum
+	ByMonthDay []int
+	Count      int        // 0 means unlimited
+	Until      *time.Time // inclusive, nil means unlimited
+}
+
+var weekdayCodes = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+// Parse parses an RRULE value such as "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE;COUNT=10".
+// An optional "RRULE:" prefix is accepted.
+func Parse(value string) (*Rule, error) {
+	value = strings.TrimSpace(value)
+	value = strings.TrimPrefix(strings.ToUpper(value), "RRULE:")
+	if value == "" {
+		return nil, i18n.NewError("recurrence_empty")
+	}
+
+	rule := &Rule{Interval: 1}
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(value, ";") {
+		if part == "" {
+			continue
+		}
+		key, val, ok := strings.Cut(part, "=")
+		if !ok || val == "" {
+			return nil, i18n.NewError("recurrence_part_invalid", strconv.Quote(part))
+		}
+		if seen[key] {
+			return nil, i18n.NewError("recurrence_part_duplicate", key)
+		}
+		seen[key] = true
+
+		switch key {
+		case "FREQ":
+			switch Frequency(val) {
+			case Daily, Weekly, Monthly, Yearly:
+				rule.Freq = Frequency(val)
+			default:
+				return nil, i18n.NewError("recurrence_freq_unsupported", val)
+			}
+		case "INTERVAL":
+			n, err := strconv.Atoi(val)
+			if err != nil || n < 1 {
+				return nil, i18n.NewError("recurrence_interval_invalid")
+			}
+			rule.Interval = n
+		case "COUNT":
+			n, err := strconv.Atoi(val)
+			if err != nil || n < 1 {
+				return nil, i18n.NewError("recurrence_count_invalid")
+			}
+			rule.Count = n
+		case "UNTIL":
+			until, err := parseUntil(val)
+			if err != nil {
+				return nil, err
+			}
+			rule.Until = &until
+		case "BYDAY":
+			for _, code := range strings.Split(val, ",") {
+				day, err := parseWeekdayNum(code)
+				if err != nil {
+					return nil, err
+				}
+				rule.ByDay = append(rule.ByDay, day)
+			}
+		case "BYMONTHDAY":
+			for _, code := range strings.Split(val, ",") {
+				n, err := strconv.Atoi(code)
+				if err != nil || n == 0 || n < -31 || n > 31 {
+					return nil, i18n.NewError("recurrence_bymonthday_invalid", strconv.Quote(code))
+				}
+				rule.ByMonthDay = append(rule.ByMonthDay, n)
+			}
+		case "WKST":
+			if val != "MO" {
+				return nil, i18n.NewError("recurrence_wkst")
+			}
+		default:
+			return nil, i18n.NewError("recurrence_part_unsupported", key)
+		}
+	}
+
+	if rule.Freq == "" {
+		return nil, i18n.NewError("recurrence_freq_required")
+	}
+	if rule.Count > 0 && rule.Until != nil {
+		return nil, i18n.NewError("recurrence_count_until")
+	}
+	if rule.Freq == Yearly && (len(rule.ByDay) > 0 || len(rule.ByMonthDay) > 0) {
+		return nil, i18n.NewError("recurrence_yearly_by")
+	}
+	for _, day := range rule.ByDay {
+		if day.N != 0 && rule.Freq != Monthly {
+			return nil, i18n.NewError("recurrence_byday_numbered")
+		}
+	}
+	if len(rule.ByMonthDay) > 0 && rule.Freq == Weekly {
+		return nil, i18n.NewError("recurrence_bymonthday_weekly")
+	}
+
+	return rule, nil
+}
+
+// parseUntil accepts the DATE (20060102) and DATE-TIME (20060102T150405Z) forms of UNTIL
+func parseUntil(val string) (time.Time, error) {
+	for _, layout := range []string{"20060102", "20060102T150405Z", "20060102T150405"} {
+		if t, err := time.Parse(layout, val); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, i18n.NewError("recurrence_until_invalid", strconv.Quote(val))
+}
+
+// parseWeekdayNum parses a BYDAY entry such as MO, +2TU or -1FR
+func parseWeekdayNum(code string) (WeekdayNum, error) {
+	if len(code) < 2 {
+		return WeekdayNum{}, i18n.NewError("recurrence_byday_invalid", strconv.Quote(code))
+	}
+
+	day, ok := weekdayCodes[code[len(code)-2:]]
+	if !ok {
+		return WeekdayNum{}, i18n.NewError("recurrence_byday_invalid", strconv.Quote(code))
+	}
+
+	n := 0
+	if prefix := code[:len(code)-2]; prefix != "" {
+		var err error
+		n, err = strconv.Atoi(prefix)
+		if err != nil || n == 0 || n < -5 || n > 5 {
+			return WeekdayNum{}, i18n.NewError("recurrence_byday_invalid", strconv.Quote(code))
+		}
+	}
+
+	return WeekdayNum{Day: day, N: n}, nil
+}
+
+// String returns the canonical RRULE representation of the rule (without the "RRULE:" prefix)
+func (r *Rule) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		codes := make([]string, len(r.ByDay))
+		for i, day := range r.ByDay {
+			code := strings.ToUpper(day.Day.String()[:2])
+			if day.N != 0 {
+				code = strconv.Itoa(day.N) + code
+			}
+			codes[i] = code
+		}
+		parts = append(parts, "BYDAY="+strings.Join(codes, ","))
+	}
+	if len(r.ByMonthDay) > 0 {
+		days := make([]string, len(r.ByMonthDay))
+		for i, day := range r.ByMonthDay {
+			days[i] = strconv.Itoa(day)
+		}
+		parts = append(parts, "BYMONTHDAY="+strings.Join(days, ","))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if r.Until != nil {
+		parts = append(parts, "UNTIL="+r.Until.Format("20060102"))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Next returns the first occurrence strictly after prev, treating prev as an occurrence of the
+// series (the INTERVAL is counted from prev's period). Only the date part is significant.
+// The boolean is false when the rule has no further occurrences because of UNTIL.
+// COUNT is not evaluated here since it depends on how many occurrences already exist.
+func (r *Rule) Next(prev time.Time) (time.Time, bool) {
+	prev = truncateDay(prev)
+
+	var next time.Time
+	var found bool
+	switch r.Freq {
+	case Daily:
+		next, found = r.nextDaily(prev)
+	case Weekly:
+		next, found = r.nextWeekly(prev)
+	case Monthly:
+		next, found = r.nextMonthly(prev)
+	case Yearly:
+		next, found = r.nextYearly(prev)
+	}
+
+	if !found {
+		return time.Time{}, false
+	}
+	if r.Until != nil && next.After(truncateDay(*r.Until)) {
+		return time.Time{}, false
+	}
+	return next, true
+}
+
+func (r *Rule) nextDaily(prev time.Time) (time.Time, bool) {
+	for i := 1; i <= maxPeriods; i++ {
+		candidate := prev.AddDate(0, 0, i*r.Interval)
+		if r.matchesDay(candidate) {
+			return candidate, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func (r *Rule) nextWeekly(prev time.Time) (time.Time, bool) {
+	if len(r.ByDay) == 0 {
+		return prev.AddDate(0, 0, 7*r.Interval), true
+	}
+
+	// Weeks start on Monday (WKST=MO)
+	offset := (int(prev.Weekday()) + 6) % 7
+	weekStart := prev.AddDate(0, 0, -offset)
+
+	for period := 0; period <= maxPeriods; period++ {
+		start := weekStart.AddDate(0, 0, 7*period*r.Interval)
+		for i := 0; i < 7; i++ {
+			candidate := start.AddDate(0, 0, i)
+			if candidate.After(prev) && r.matchesDay(candidate) {
+				return candidate, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+func (r *Rule) nextMonthly(prev time.Time) (time.Time, bool) {
+	for period := 0; period <= maxPeriods; period++ {
+		monthStart := time.Date(prev.Year(), prev.Month()+time.Month(period*r.Interval), 1, 0, 0, 0, 0, prev.Location())
+		for _, candidate := range r.monthCandidates(monthStart, prev.Day()) {
+			if candidate.After(prev) {
+				return candidate, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+func (r *Rule) nextYearly(prev time.Time) (time.Time, bool) {
+	for period := 1; period <= maxPeriods; period++ {
+		year := prev.Year() + period*r.Interval
+		candidate := time.Date(year, prev.Month(), prev.Day(), 0, 0, 0, 0, prev.Location())
+		// Invalid dates such as February 29 in a non-leap year are skipped
+		if candidate.Day() == prev.Day() {
+			return candidate, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// monthCandidates returns the sorted occurrence dates inside the month starting at monthStart.
+// With both BYMONTHDAY and BYDAY only the days matching both are kept, e.g. every Friday the
+// 13th. Without either the series repeats on the same day of month as its anchor.
+func (r *Rule) monthCandidates(monthStart time.Time, anchorDay int) []time.Time {
+	daysInMonth := monthStart.AddDate(0, 1, -1).Day()
+	var days []int
+
+	switch {
+	case len(r.ByMonthDay) > 0 || len(r.ByDay) > 0:
+		var byDay map[int]bool
+		if len(r.ByDay) > 0 {
+			byDay = make(map[int]bool)
+			for _, weekday := range r.ByDay {
+				for _, day := range weekdaysInMonth(monthStart, daysInMonth, weekday) {
+					byDay[day] = true
+				}
+			}
+		}
+
+		if len(r.ByMonthDay) == 0 {
+			for day := range byDay {
+				days = append(days, day)
+			}
+			break
+		}
+		for _, day := range r.ByMonthDay {
+			if day < 0 {
+				day = daysInMonth + day + 1
+			}
+			if day >= 1 && day <= daysInMonth && (byDay == nil || byDay[day]) {
+				days = append(days, day)
+			}
+		}
+	default:
+		if anchorDay <= daysInMonth {
+			days = append(days, anchorDay)
+		}
+	}
+
+	sort.Ints(days)
+	candidates := make([]time.Time, 0, len(days))
+	for i, day := range days {
+		if i > 0 && days[i-1] == day {
+			continue
+		}
+		candidates = append(candidates, monthStart.AddDate(0, 0, day-1))
+	}
+	return candidates
+}
+
+// weekdaysInMonth returns the days of the month matching a BYDAY entry (every match when N is 0)
+func weekdaysInMonth(monthStart time.Time, daysInMonth int, weekday WeekdayNum) []int {
+	first := 1 + (int(weekday.Day)-int(monthStart.Weekday())+7)%7
+	var matches []int
+	for day := first; day <= daysInMonth; day += 7 {
+		matches = append(matches, day)
+	}
+
+	switch {
+	case weekday.N == 0:
+		return matches
+	case weekday.N > 0 && weekday.N <= len(matches):
+		return []int{matches[weekday.N-1]}
+	case weekday.N < 0 && -weekday.N <= len(matches):
+		return []int{matches[len(matches)+weekday.N]}
+	default:
+		return nil
+	}
+}
+
+// matchesDay applies the BYDAY and BYMONTHDAY filters used by DAILY and WEEKLY rules
+func (r *Rule) matchesDay(t time.Time) bool {
+	if len(r.ByDay) > 0 {
+		found := false
+		for _, day := range r.ByDay {
+			if day.Day == t.Weekday() {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if len(r.ByMonthDay) > 0 {
+		daysInMonth := time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, t.Location()).Day()
+		found := false
+		for _, day := range r.ByMonthDay {
+			if day == t.Day() || daysInMonth+day+1 == t.Day() {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
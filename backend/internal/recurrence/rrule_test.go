@@ -0,0 +1,177 @@
+package recurrence
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"backend/root/internal/i18n"
+)
+
+// day returns midnight UTC of a date
+func day(year int, month time.Month, d int) time.Time {
+	return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestNext(t *testing.T) {
+	tests := []struct {
+		name  string
+		rule  string
+		start time.Time
+		want  []time.Time
+		ends  bool // Next reports no further occurrence after want
+	}{
+		{
+			name:  "monthly BYDAY and BYMONTHDAY keep the days matching both",
+			rule:  "FREQ=MONTHLY;BYDAY=FR;BYMONTHDAY=13",
+			start: day(2024, 1, 1),
+			want:  []time.Time{day(2024, 9, 13), day(2024, 12, 13), day(2025, 6, 13)},
+		},
+		{
+			name:  "monthly negative BYMONTHDAY counts from the end of the month",
+			rule:  "FREQ=MONTHLY;BYMONTHDAY=-1",
+			start: day(2024, 1, 31),
+			want:  []time.Time{day(2024, 2, 29), day(2024, 3, 31), day(2024, 4, 30)},
+		},
+		{
+			name:  "monthly negative BYDAY ordinal picks the last weekday",
+			rule:  "FREQ=MONTHLY;BYDAY=-1FR",
+			start: day(2024, 1, 1),
+			want:  []time.Time{day(2024, 1, 26), day(2024, 2, 23), day(2024, 3, 29)},
+		},
+		{
+			name:  "monthly BYDAY with negative BYMONTHDAY days",
+			rule:  "FREQ=MONTHLY;BYDAY=MO;BYMONTHDAY=-7,-6,-5,-4,-3,-2,-1",
+			start: day(2024, 1, 1),
+			want:  []time.Time{day(2024, 1, 29), day(2024, 2, 26), day(2024, 3, 25)},
+		},
+		{
+			name:  "monthly on the 31st skips shorter months",
+			rule:  "FREQ=MONTHLY",
+			start: day(2024, 1, 31),
+			want:  []time.Time{day(2024, 3, 31), day(2024, 5, 31), day(2024, 7, 31), day(2024, 8, 31)},
+		},
+		{
+			name:  "monthly BYMONTHDAY=31 skips shorter months",
+			rule:  "FREQ=MONTHLY;BYMONTHDAY=31",
+			start: day(2024, 8, 31),
+			want:  []time.Time{day(2024, 10, 31), day(2024, 12, 31), day(2025, 1, 31)},
+		},
+		{
+			name:  "monthly on the 29th skips February outside leap years",
+			rule:  "FREQ=MONTHLY;BYMONTHDAY=29",
+			start: day(2023, 1, 29),
+			want:  []time.Time{day(2023, 3, 29), day(2023, 4, 29)},
+		},
+		{
+			name:  "monthly on the 29th keeps February 29 in leap years",
+			rule:  "FREQ=MONTHLY;BYMONTHDAY=29",
+			start: day(2024, 1, 29),
+			want:  []time.Time{day(2024, 2, 29), day(2024, 3, 29)},
+		},
+		{
+			name:  "yearly on February 29 only repeats in leap years",
+			rule:  "FREQ=YEARLY",
+			start: day(2024, 2, 29),
+			want:  []time.Time{day(2028, 2, 29), day(2032, 2, 29)},
+		},
+		{
+			name:  "weekly INTERVAL with BYDAY fills the week before skipping",
+			rule:  "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH",
+			start: day(2024, 1, 1),
+			want:  []time.Time{day(2024, 1, 4), day(2024, 1, 15), day(2024, 1, 18), day(2024, 1, 29)},
+		},
+		{
+			name:  "weekly INTERVAL counts from the week of a midweek start",
+			rule:  "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR",
+			start: day(2024, 1, 3),
+			want:  []time.Time{day(2024, 1, 5), day(2024, 1, 15), day(2024, 1, 19)},
+		},
+		{
+			name:  "UNTIL date is inclusive",
+			rule:  "FREQ=DAILY;INTERVAL=3;UNTIL=20240110",
+			start: day(2024, 1, 1),
+			want:  []time.Time{day(2024, 1, 4), day(2024, 1, 7), day(2024, 1, 10)},
+			ends:  true,
+		},
+		{
+			name:  "UNTIL date-time only compares the date",
+			rule:  "FREQ=WEEKLY;UNTIL=20240115T000000Z",
+			start: day(2024, 1, 1),
+			want:  []time.Time{day(2024, 1, 8), day(2024, 1, 15)},
+			ends:  true,
+		},
+		{
+			name:  "COUNT does not limit Next",
+			rule:  "FREQ=DAILY;COUNT=2",
+			start: day(2024, 1, 1),
+			want:  []time.Time{day(2024, 1, 2), day(2024, 1, 3), day(2024, 1, 4)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := Parse(tt.rule)
+			if err != nil {
+				t.Fatalf("Parse(%q) returned %v", tt.rule, err)
+			}
+
+			prev := tt.start
+			for _, want := range tt.want {
+				next, ok := rule.Next(prev)
+				if !ok || !next.Equal(want) {
+					t.Fatalf("Next(%s) = %s, %v, want %s", prev.Format("2006-01-02"), next.Format("2006-01-02"), ok, want.Format("2006-01-02"))
+				}
+				prev = next
+			}
+
+			if next, ok := rule.Next(prev); ok == tt.ends {
+				t.Errorf("Next(%s) = %s, %v, want %v", prev.Format("2006-01-02"), next.Format("2006-01-02"), ok, !tt.ends)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string // canonical form
+		code  string // error code, "" when the rule is valid
+	}{
+		{value: "RRULE:freq=weekly;byday=MO,th;count=10", want: "FREQ=WEEKLY;BYDAY=MO,TH;COUNT=10"},
+		{value: "FREQ=MONTHLY;INTERVAL=1;BYDAY=-1FR", want: "FREQ=MONTHLY;BYDAY=-1FR"},
+		{value: "FREQ=MONTHLY;BYMONTHDAY=1,-1;UNTIL=20241231T235959Z", want: "FREQ=MONTHLY;BYMONTHDAY=1,-1;UNTIL=20241231"},
+		{value: "", code: "recurrence_empty"},
+		{value: "INTERVAL=2", code: "recurrence_freq_required"},
+		{value: "FREQ=HOURLY", code: "recurrence_freq_unsupported"},
+		{value: "FREQ=DAILY;COUNT=0", code: "recurrence_count_invalid"},
+		{value: "FREQ=DAILY;COUNT=-3", code: "recurrence_count_invalid"},
+		{value: "FREQ=DAILY;COUNT=3;UNTIL=20240110", code: "recurrence_count_until"},
+		{value: "FREQ=DAILY;COUNT=3;COUNT=4", code: "recurrence_part_duplicate"},
+		{value: "FREQ=DAILY;UNTIL=2024-01-10", code: "recurrence_until_invalid"},
+		{value: "FREQ=MONTHLY;BYMONTHDAY=0", code: "recurrence_bymonthday_invalid"},
+		{value: "FREQ=MONTHLY;BYMONTHDAY=-32", code: "recurrence_bymonthday_invalid"},
+		{value: "FREQ=MONTHLY;BYDAY=6MO", code: "recurrence_byday_invalid"},
+		{value: "FREQ=WEEKLY;BYDAY=2MO", code: "recurrence_byday_numbered"},
+		{value: "FREQ=WEEKLY;BYMONTHDAY=1", code: "recurrence_bymonthday_weekly"},
+		{value: "FREQ=YEARLY;BYDAY=MO", code: "recurrence_yearly_by"},
+	}
+
+	for _, tt := range tests {
+		rule, err := Parse(tt.value)
+		if tt.code != "" {
+			var coded *i18n.Error
+			if !errors.As(err, &coded) || coded.Code != tt.code {
+				t.Errorf("Parse(%q) returned %v, want error %s", tt.value, err, tt.code)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Parse(%q) returned %v", tt.value, err)
+			continue
+		}
+		if got := rule.String(); got != tt.want {
+			t.Errorf("Parse(%q).String() = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,16 @@
+package reminders
+
+import "time"
+
+// Clock abstracts the current time so scheduling can be tested deterministically
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock is the Clock backed by time.Now
+type SystemClock struct{}
+
+// Now returns the current time
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
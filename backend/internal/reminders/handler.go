@@ -0,0 +1,168 @@
+package reminders
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+
+	"backend/root/internal/i18n"
+)
+
+// Handler handles HTTP requests for reminders and notifications
+type Handler struct {
+	service Service
+}
+
+// NewHandler creates a new reminders handler
+func NewHandler(service Service) *Handler {
+	return &Handler{
+		service: service,
+	}
+}
+
+// Create handles POST /tasks/:id/reminders - schedules a reminder for a task
+func (h *Handler) Create(c *gin.Context) {
+	taskID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, i18n.NewError("task_id_invalid"))
+		return
+	}
+
+	var req CreateReminderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, i18n.NewError("request_invalid_detail", err))
+		return
+	}
+
+	userID, err := getUserIDFromClaims(c)
+	if err != nil {
+		i18n.RespondError(c, http.StatusUnauthorized, err)
+		return
+	}
+
+	reminder, err := h.service.Create(c.Request.Context(), taskID, userID, req)
+	if err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, reminder)
+}
+
+// GetByTask handles GET /tasks/:id/reminders - lists the reminders of a task
+func (h *Handler) GetByTask(c *gin.Context) {
+	taskID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, i18n.NewError("task_id_invalid"))
+		return
+	}
+
+	userID, err := getUserIDFromClaims(c)
+	if err != nil {
+		i18n.RespondError(c, http.StatusUnauthorized, err)
+		return
+	}
+
+	reminders, err := h.service.GetByTask(c.Request.Context(), taskID, userID)
+	if err != nil {
+		i18n.RespondError(c, http.StatusNotFound, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"reminders": reminders,
+		"count":     len(reminders),
+	})
+}
+
+// Delete handles DELETE /reminders/:id - cancels a reminder
+func (h *Handler) Delete(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, i18n.NewError("reminder_id_invalid"))
+		return
+	}
+
+	userID, err := getUserIDFromClaims(c)
+	if err != nil {
+		i18n.RespondError(c, http.StatusUnauthorized, err)
+		return
+	}
+
+	if err := h.service.Delete(c.Request.Context(), id, userID); err != nil {
+		i18n.RespondError(c, http.StatusNotFound, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "reminder deleted successfully"})
+}
+
+// GetNotifications handles GET /notifications - lists in-app notifications (?unread=true for unread only)
+func (h *Handler) GetNotifications(c *gin.Context) {
+	userID, err := getUserIDFromClaims(c)
+	if err != nil {
+		i18n.RespondError(c, http.StatusUnauthorized, err)
+		return
+	}
+
+	unreadOnly, _ := strconv.ParseBool(c.Query("unread"))
+	notifications, err := h.service.GetNotifications(c.Request.Context(), userID, unreadOnly)
+	if err != nil {
+		i18n.RespondError(c, http.StatusInternalServerError, i18n.NewError("notifications_get_failed"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"notifications": notifications,
+		"count":         len(notifications),
+	})
+}
+
+// MarkNotificationRead handles POST /notifications/:id/read
+func (h *Handler) MarkNotificationRead(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, i18n.NewError("notification_id_invalid"))
+		return
+	}
+
+	userID, err := getUserIDFromClaims(c)
+	if err != nil {
+		i18n.RespondError(c, http.StatusUnauthorized, err)
+		return
+	}
+
+	if err := h.service.MarkNotificationRead(c.Request.Context(), id, userID); err != nil {
+		i18n.RespondError(c, http.StatusNotFound, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "notification marked as read"})
+}
+
+// getUserIDFromClaims extracts the user ID from the JWT claims set by the auth middleware
+func getUserIDFromClaims(c *gin.Context) (int64, error) {
+	claims, exists := c.Get("claims")
+	if !exists {
+		return 0, i18n.NewError("user_not_authenticated")
+	}
+
+	claimsMap, ok := claims.(jwt.MapClaims)
+	if !ok {
+		return 0, errors.New("invalid claims")
+	}
+
+	switch v := claimsMap["uid"].(type) {
+	case float64:
+		return int64(v), nil
+	case int64:
+		return v, nil
+	case int:
+		return int64(v), nil
+	default:
+		return 0, i18n.NewError("user_id_missing")
+	}
+}
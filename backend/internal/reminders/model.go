@@ -0,0 +1,76 @@
+package reminders
+
+import (
+	"time"
+)
+
+// Reminder represents a scheduled notification about a task
+type Reminder struct {
+	ID            int64      `json:"id" db:"id"`
+	TaskID        int64      `json:"task_id" db:"task_id"`
+	UserID        int64      `json:"user_id" db:"user_id"`
+	RemindAt      *time.Time `json:"remind_at" db:"remind_at"`           // Absolute reminder time
+	OffsetMinutes *int       `json:"offset_minutes" db:"offset_minutes"` // Minutes before the task's end_date
+	Channel       string     `json:"channel" db:"channel"`
+	Target        string     `json:"target,omitempty" db:"target"`
+	Status        string     `json:"status" db:"status"`
+	Attempts      int        `json:"attempts" db:"attempts"`
+	LastError     string     `json:"last_error,omitempty" db:"last_error"`
+	SentAt        *time.Time `json:"sent_at" db:"sent_at"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+}
+
+// CreateReminderRequest represents the request payload for creating a reminder.
+// Exactly one of RemindAt or OffsetMinutes must be set.
+type CreateReminderRequest struct {
+	RemindAt      string `json:"remind_at,omitempty"`      // RFC 3339, e.g. "2024-01-20T09:00:00Z"
+	OffsetMinutes *int   `json:"offset_minutes,omitempty"` // Minutes before end_date (00:00 UTC)
+	Channel       string `json:"channel" binding:"required,oneof=in_app email webhook"`
+	Target        string `json:"target,omitempty"` // Email address or webhook URL
+}
+
+// Notification represents an in-app notification shown to a user
+type Notification struct {
+	ID         int64      `json:"id" db:"id"`
+	UserID     int64      `json:"user_id" db:"user_id"`
+	TaskID     *int64     `json:"task_id" db:"task_id"`
+	ReminderID *int64     `json:"reminder_id" db:"reminder_id"`
+	Message    string     `json:"message" db:"message"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+	ReadAt     *time.Time `json:"read_at" db:"read_at"`
+}
+
+// Delivery is a claimed reminder handed to a Notifier
+type Delivery struct {
+	ReminderID int64      `json:"reminder_id"`
+	UserID     int64      `json:"user_id"`
+	TaskID     int64      `json:"task_id"`
+	TaskText   string     `json:"task_text"`
+	EndDate    *time.Time `json:"end_date"`
+	Channel    string     `json:"channel"`
+	Target     string     `json:"-"`
+	Attempt    int        `json:"attempt"`
+}
+
+// Reminder delivery channels
+const (
+	ChannelInApp   = "in_app"
+	ChannelEmail   = "email"
+	ChannelWebhook = "webhook"
+)
+
+// Reminder statuses
+const (
+	StatusPending    = "pending"    // waiting to be due or to be retried
+	StatusProcessing = "processing" // claimed by a scheduler, lease held until locked_until
+	StatusSent       = "sent"
+	StatusFailed     = "failed" // gave up after the maximum number of attempts
+)
+
+// Message returns the human readable text of a reminder
+func (d *Delivery) Message() string {
+	if d.EndDate != nil {
+		return "Reminder: \"" + d.TaskText + "\" is due on " + d.EndDate.Format("2006-01-02")
+	}
+	return "Reminder: \"" + d.TaskText + "\""
+}
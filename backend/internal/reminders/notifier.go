@@ -0,0 +1,233 @@
+package reminders
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net"
+	"net/http"
+	"net/mail"
+	"net/smtp"
+	"strings"
+	"syscall"
+	"time"
+
+	"backend/root/internal/i18n"
+	"backend/root/internal/tasks"
+)
+
+// Notifier delivers a claimed reminder through one channel
+type Notifier interface {
+	Notify(ctx context.Context, delivery *Delivery) error
+}
+
+// InAppNotifier stores reminders as notifications listed under /api/protected/notifications
+type InAppNotifier struct {
+	repo Repository
+}
+
+// NewInAppNotifier creates a new in-app notifier
+func NewInAppNotifier(repo Repository) *InAppNotifier {
+	return &InAppNotifier{repo: repo}
+}
+
+// Notify stores the reminder as an unread notification
+func (n *InAppNotifier) Notify(ctx context.Context, delivery *Delivery) error {
+	_, err := n.repo.CreateNotification(ctx, &Notification{
+		UserID:     delivery.UserID,
+		TaskID:     &delivery.TaskID,
+		ReminderID: &delivery.ReminderID,
+		Message:    delivery.Message(),
+	})
+	return err
+}
+
+// AssignmentNotifier stores an in-app notification for the users a task is assigned to or
+// unassigned from, except for the user who made the change
+type AssignmentNotifier struct {
+	repo Repository
+}
+
+// NewAssignmentNotifier creates a new assignment notifier
+func NewAssignmentNotifier(repo Repository) *AssignmentNotifier {
+	return &AssignmentNotifier{repo: repo}
+}
+
+// TaskAssigned implements tasks.AssignmentHook
+func (n *AssignmentNotifier) TaskAssigned(ctx context.Context, event tasks.AssignmentEvent) error {
+	if event.AssigneeID != nil && *event.AssigneeID != event.ActorID {
+		if err := n.notify(ctx, *event.AssigneeID, event.TaskID, "You were assigned \""+event.TaskText+"\""); err != nil {
+			return err
+		}
+	}
+	if event.PreviousAssigneeID != nil && *event.PreviousAssigneeID != event.ActorID {
+		if err := n.notify(ctx, *event.PreviousAssigneeID, event.TaskID, "You were unassigned from \""+event.TaskText+"\""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// notify stores one unread notification about a task
+func (n *AssignmentNotifier) notify(ctx context.Context, userID int64, taskID int64, message string) error {
+	_, err := n.repo.CreateNotification(ctx, &Notification{
+		UserID:  userID,
+		TaskID:  &taskID,
+		Message: message,
+	})
+	return err
+}
+
+// SMTPConfig holds the settings used by EmailNotifier
+type SMTPConfig struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+// EmailNotifier sends reminders by email through an SMTP server
+type EmailNotifier struct {
+	cfg SMTPConfig
+}
+
+// NewEmailNotifier creates a new email notifier
+func NewEmailNotifier(cfg SMTPConfig) *EmailNotifier {
+	return &EmailNotifier{cfg: cfg}
+}
+
+// Notify sends the reminder to the delivery target address
+func (n *EmailNotifier) Notify(ctx context.Context, delivery *Delivery) error {
+	if n.cfg.Host == "" {
+		return i18n.NewError("reminder_email_disabled")
+	}
+
+	from, err := mail.ParseAddress(n.cfg.From)
+	if err != nil {
+		return fmt.Errorf("invalid SMTP_FROM address: %w", err)
+	}
+	to, err := mail.ParseAddress(delivery.Target)
+	if err != nil {
+		return fmt.Errorf("invalid target email address: %w", err)
+	}
+
+	var auth smtp.Auth
+	if n.cfg.Username != "" {
+		auth = smtp.PlainAuth("", n.cfg.Username, n.cfg.Password, n.cfg.Host)
+	}
+
+	msg := emailMessage(from.Address, to.Address, delivery.Message())
+	addr := net.JoinHostPort(n.cfg.Host, n.cfg.Port)
+	return smtp.SendMail(addr, auth, from.Address, []string{to.Address}, msg)
+}
+
+// emailMessage builds a plain text email. The subject repeats the text on a single line, since
+// task texts may contain line breaks that would otherwise start new headers, and is encoded as
+// an RFC 2047 word when it is not plain ASCII.
+func emailMessage(from string, to string, text string) []byte {
+	subject := strings.Join(strings.FieldsFunc(text, func(r rune) bool { return r == '\r' || r == '\n' }), " ")
+
+	return []byte(strings.Join([]string{
+		"From: " + from,
+		"To: " + to,
+		"Subject: " + mime.QEncoding.Encode("UTF-8", subject),
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=UTF-8",
+		"",
+		text,
+	}, "\r\n"))
+}
+
+// WebhookNotifier posts reminders as JSON to the delivery target URL
+type WebhookNotifier struct {
+	client *http.Client
+}
+
+// NewWebhookNotifier creates a new webhook notifier with the given request timeout. Its
+// connections are refused when the target resolves to a loopback, private or link-local
+// address, so reminders cannot be used to reach services inside the network.
+func NewWebhookNotifier(timeout time.Duration) *WebhookNotifier {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network string, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !isPublicIP(ip) {
+				return fmt.Errorf("webhook target %s is not a public address", host)
+			}
+			return nil
+		},
+	}
+
+	return &WebhookNotifier{client: &http.Client{
+		Timeout:   timeout,
+		Transport: &http.Transport{DialContext: dialer.DialContext},
+	}}
+}
+
+// Notify posts the reminder and treats any non-2xx response as a failure
+func (n *WebhookNotifier) Notify(ctx context.Context, delivery *Delivery) error {
+	body, err := json.Marshal(webhookPayload(delivery))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.Target, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// sharedAddressSpace is the carrier-grade NAT range (RFC 6598), private although net.IP.IsPrivate
+// does not report it
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// isPublicIP reports whether a webhook may connect to ip
+func isPublicIP(ip net.IP) bool {
+	return !ip.IsLoopback() && !ip.IsPrivate() && !ip.IsUnspecified() && !ip.IsLinkLocalUnicast() &&
+		!ip.IsLinkLocalMulticast() && !ip.IsInterfaceLocalMulticast() && !ip.IsMulticast() &&
+		!sharedAddressSpace.Contains(ip)
+}
+
+// isPublicHost reports whether a webhook URL host may be a public address: names other than
+// localhost are only checked once resolved, when the notifier connects
+func isPublicHost(host string) bool {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return false
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		return isPublicIP(ip)
+	}
+	return true
+}
+
+// webhookPayload builds the JSON body sent to webhooks
+func webhookPayload(delivery *Delivery) map[string]interface{} {
+	return map[string]interface{}{
+		"reminder_id": delivery.ReminderID,
+		"task_id":     delivery.TaskID,
+		"task_text":   delivery.TaskText,
+		"end_date":    delivery.EndDate,
+		"attempt":     delivery.Attempt,
+		"message":     delivery.Message(),
+	}
+}
@@ -0,0 +1,156 @@
+package reminders
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEmailMessage(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		subject string
+	}{
+		{
+			name:    "plain ASCII subjects are sent as is",
+			text:    `Reminder: "Pay rent"`,
+			subject: `Subject: Reminder: "Pay rent"`,
+		},
+		{
+			name:    "line breaks cannot start new headers",
+			text:    "Reminder: \"Pay rent\r\nBcc: victim@example.com\"",
+			subject: `Subject: Reminder: "Pay rent Bcc: victim@example.com"`,
+		},
+		{
+			name:    "bare line feeds are removed too",
+			text:    "Reminder: \"Pay\nrent\"",
+			subject: `Subject: Reminder: "Pay rent"`,
+		},
+		{
+			name:    "non-ASCII subjects are encoded",
+			text:    `Reminder: "Pagar la cuota del año"`,
+			subject: `Subject: =?UTF-8?q?Reminder:_"Pagar_la_cuota_del_a=C3=B1o"?=`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := string(emailMessage("from@example.com", "to@example.com", tt.text))
+			headers, body, ok := strings.Cut(msg, "\r\n\r\n")
+			if !ok {
+				t.Fatalf("message has no blank line between headers and body: %q", msg)
+			}
+
+			lines := strings.Split(headers, "\r\n")
+			want := []string{
+				"From: from@example.com",
+				"To: to@example.com",
+				tt.subject,
+				"MIME-Version: 1.0",
+				"Content-Type: text/plain; charset=UTF-8",
+			}
+			if strings.Join(lines, "\n") != strings.Join(want, "\n") {
+				t.Errorf("headers = %q, want %q", lines, want)
+			}
+			if body != tt.text {
+				t.Errorf("body = %q, want %q", body, tt.text)
+			}
+		})
+	}
+}
+
+func TestEmailNotifierRejectsInvalidTargets(t *testing.T) {
+	notifier := NewEmailNotifier(SMTPConfig{Host: "smtp.example.com", Port: "587", From: "no-reply@example.com"})
+
+	err := notifier.Notify(context.Background(), &Delivery{Target: "victim@example.com\r\nBcc: other@example.com"})
+	if err == nil || !strings.Contains(err.Error(), "invalid target email address") {
+		t.Errorf("Notify returned %v, want an invalid target error", err)
+	}
+}
+
+func TestIsPublicHost(t *testing.T) {
+	tests := []struct {
+		host string
+		want bool
+	}{
+		{"example.com", true},
+		{"93.184.216.34", true},
+		{"2606:2800:220:1:248:1893:25c8:1946", true},
+		{"localhost", false},
+		{"LOCALHOST.", false},
+		{"api.localhost", false},
+		{"127.0.0.1", false},
+		{"::1", false},
+		{"0.0.0.0", false},
+		{"10.0.0.8", false},
+		{"172.16.3.4", false},
+		{"192.168.1.10", false},
+		{"169.254.169.254", false},
+		{"100.64.0.1", false},
+		{"fd00::1", false},
+		{"fe80::1", false},
+		{"::ffff:127.0.0.1", false},
+	}
+
+	for _, tt := range tests {
+		if got := isPublicHost(tt.host); got != tt.want {
+			t.Errorf("isPublicHost(%q) = %v, want %v", tt.host, got, tt.want)
+		}
+	}
+}
+
+func TestWebhookNotifierPostsReminders(t *testing.T) {
+	var payload map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("invalid webhook body: %v", err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	// The test server listens on loopback, which NewWebhookNotifier refuses
+	notifier := &WebhookNotifier{client: server.Client()}
+	delivery := &Delivery{ReminderID: 7, TaskID: 3, TaskText: "Pay rent", Target: server.URL, Attempt: 1}
+	if err := notifier.Notify(context.Background(), delivery); err != nil {
+		t.Fatalf("Notify returned %v", err)
+	}
+
+	if payload["reminder_id"] != float64(7) || payload["task_id"] != float64(3) || payload["message"] != `Reminder: "Pay rent"` {
+		t.Errorf("payload = %v", payload)
+	}
+}
+
+func TestWebhookNotifierFailsOnErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	notifier := &WebhookNotifier{client: server.Client()}
+	err := notifier.Notify(context.Background(), &Delivery{Target: server.URL})
+	if err == nil || err.Error() != "webhook responded with status 502" {
+		t.Errorf("Notify returned %v, want a status error", err)
+	}
+}
+
+func TestWebhookNotifierRefusesPrivateAddresses(t *testing.T) {
+	called := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer server.Close()
+
+	err := NewWebhookNotifier(time.Second).Notify(context.Background(), &Delivery{Target: server.URL})
+	if err == nil || !strings.Contains(err.Error(), "is not a public address") {
+		t.Errorf("Notify returned %v, want the connection to be refused", err)
+	}
+	if called {
+		t.Error("the webhook reached a loopback address")
+	}
+}
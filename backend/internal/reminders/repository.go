@@ -0,0 +1,294 @@
+package reminders
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"backend/root/internal/i18n"
+)
+
+// Repository defines the interface for reminder and notification persistence
+type Repository interface {
+	Create(ctx context.Context, reminder *Reminder) (*Reminder, error)
+	GetByID(ctx context.Context, id int64) (*Reminder, error)
+	GetByTask(ctx context.Context, taskID int64) ([]*Reminder, error)
+	Delete(ctx context.Context, id int64) error
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*Delivery, error)
+	MarkSent(ctx context.Context, id int64, sentAt time.Time) error
+	MarkRetry(ctx context.Context, id int64, nextAttemptAt time.Time, lastError string) error
+	MarkFailed(ctx context.Context, id int64, lastError string) error
+	CreateNotification(ctx context.Context, notification *Notification) (*Notification, error)
+	GetNotifications(ctx context.Context, userID int64, unreadOnly bool) ([]*Notification, error)
+	MarkNotificationRead(ctx context.Context, id int64, userID int64, readAt time.Time) error
+}
+
+// reminderColumns lists the reminders table columns in the order expected by scanReminder
+const reminderColumns = `id, task_id, user_id, remind_at, offset_minutes, channel, target, status, attempts,
+	COALESCE(last_error, ''), sent_at, created_at`
+
+// PostgresRepository implements Repository using PostgreSQL
+type PostgresRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresRepository creates a new PostgreSQL reminders repository
+func NewPostgresRepository(db *sql.DB) *PostgresRepository {
+	return &PostgresRepository{db: db}
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanReminder(row rowScanner) (*Reminder, error) {
+	var reminder Reminder
+	err := row.Scan(
+		&reminder.ID, &reminder.TaskID, &reminder.UserID, &reminder.RemindAt, &reminder.OffsetMinutes,
+		&reminder.Channel, &reminder.Target, &reminder.Status, &reminder.Attempts,
+		&reminder.LastError, &reminder.SentAt, &reminder.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &reminder, nil
+}
+
+// Create stores a new pending reminder
+func (r *PostgresRepository) Create(ctx context.Context, reminder *Reminder) (*Reminder, error) {
+	query := `
+		INSERT INTO reminders (task_id, user_id, remind_at, offset_minutes, channel, target)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING ` + reminderColumns
+
+	created, err := scanReminder(r.db.QueryRowContext(ctx, query,
+		reminder.TaskID, reminder.UserID, reminder.RemindAt, reminder.OffsetMinutes, reminder.Channel, reminder.Target,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create reminder: %w", err)
+	}
+
+	return created, nil
+}
+
+// GetByID retrieves a reminder by its ID
+func (r *PostgresRepository) GetByID(ctx context.Context, id int64) (*Reminder, error) {
+	query := `SELECT ` + reminderColumns + ` FROM reminders WHERE id = $1`
+
+	reminder, err := scanReminder(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, i18n.NewError("reminder_not_found")
+		}
+		return nil, fmt.Errorf("failed to get reminder: %w", err)
+	}
+
+	return reminder, nil
+}
+
+// GetByTask retrieves every reminder of a task
+func (r *PostgresRepository) GetByTask(ctx context.Context, taskID int64) ([]*Reminder, error) {
+	query := `SELECT ` + reminderColumns + ` FROM reminders WHERE task_id = $1 ORDER BY id`
+
+	rows, err := r.db.QueryContext(ctx, query, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reminders: %w", err)
+	}
+	defer rows.Close()
+
+	var reminders []*Reminder
+	for rows.Next() {
+		reminder, err := scanReminder(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan reminder: %w", err)
+		}
+		reminders = append(reminders, reminder)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating reminders: %w", err)
+	}
+
+	return reminders, nil
+}
+
+// Delete removes a reminder by ID
+func (r *PostgresRepository) Delete(ctx context.Context, id int64) error {
+	query := `DELETE FROM reminders WHERE id = $1`
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete reminder: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return i18n.NewError("reminder_not_found")
+	}
+
+	return nil
+}
+
+// ClaimDue atomically claims up to limit due reminders for this scheduler instance.
+// Rows locked by another replica are skipped (FOR UPDATE SKIP LOCKED), and a claim is a
+// lease: if the claiming instance dies before reporting the outcome, the reminder becomes
+// claimable again once locked_until has passed. Reminders of finished (done state) or deleted tasks are never claimed.
+func (r *PostgresRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*Delivery, error) {
+	query := `
+		WITH due AS (
+			SELECT r.id
+			FROM reminders r
+			JOIN tasks t ON t.id = r.task_id
+			WHERE NOT EXISTS (SELECT 1 FROM states s WHERE s.id = t.id_state AND s.is_done)
+			  AND t.deleted_at IS NULL
+			  AND (
+				(r.status = 'pending' AND COALESCE(
+					r.next_attempt_at,
+					r.remind_at,
+					(t.end_date::timestamp AT TIME ZONE 'UTC') - make_interval(mins => r.offset_minutes)
+				) <= $1)
+				OR (r.status = 'processing' AND r.locked_until <= $1)
+			  )
+			ORDER BY r.id
+			LIMIT $3
+			FOR UPDATE OF r SKIP LOCKED
+		)
+		UPDATE reminders r
+		SET status = 'processing', locked_until = $2, attempts = r.attempts + 1
+		FROM due, tasks t
+		WHERE r.id = due.id AND t.id = r.task_id
+		RETURNING r.id, r.user_id, r.task_id, t.task_text, t.end_date, r.channel, r.target, r.attempts`
+
+	rows, err := r.db.QueryContext(ctx, query, now, now.Add(lease), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim reminders: %w", err)
+	}
+	defer rows.Close()
+
+	var deliveries []*Delivery
+	for rows.Next() {
+		var d Delivery
+		err := rows.Scan(&d.ReminderID, &d.UserID, &d.TaskID, &d.TaskText, &d.EndDate, &d.Channel, &d.Target, &d.Attempt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan reminder: %w", err)
+		}
+		deliveries = append(deliveries, &d)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating reminders: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+// MarkSent records a successful delivery
+func (r *PostgresRepository) MarkSent(ctx context.Context, id int64, sentAt time.Time) error {
+	query := `
+		UPDATE reminders
+		SET status = 'sent', sent_at = $2, locked_until = NULL, last_error = NULL
+		WHERE id = $1`
+
+	_, err := r.db.ExecContext(ctx, query, id, sentAt)
+	return err
+}
+
+// MarkRetry returns a reminder to the pending queue to be retried at nextAttemptAt
+func (r *PostgresRepository) MarkRetry(ctx context.Context, id int64, nextAttemptAt time.Time, lastError string) error {
+	query := `
+		UPDATE reminders
+		SET status = 'pending', next_attempt_at = $2, locked_until = NULL, last_error = $3
+		WHERE id = $1`
+
+	_, err := r.db.ExecContext(ctx, query, id, nextAttemptAt, lastError)
+	return err
+}
+
+// MarkFailed gives up on a reminder
+func (r *PostgresRepository) MarkFailed(ctx context.Context, id int64, lastError string) error {
+	query := `
+		UPDATE reminders
+		SET status = 'failed', locked_until = NULL, last_error = $2
+		WHERE id = $1`
+
+	_, err := r.db.ExecContext(ctx, query, id, lastError)
+	return err
+}
+
+// CreateNotification stores an in-app notification
+func (r *PostgresRepository) CreateNotification(ctx context.Context, notification *Notification) (*Notification, error) {
+	query := `
+		INSERT INTO notifications (user_id, task_id, reminder_id, message)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, user_id, task_id, reminder_id, message, created_at, read_at`
+
+	var n Notification
+	err := r.db.QueryRowContext(ctx, query,
+		notification.UserID, notification.TaskID, notification.ReminderID, notification.Message,
+	).Scan(&n.ID, &n.UserID, &n.TaskID, &n.ReminderID, &n.Message, &n.CreatedAt, &n.ReadAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create notification: %w", err)
+	}
+
+	return &n, nil
+}
+
+// GetNotifications retrieves a user's notifications, newest first
+func (r *PostgresRepository) GetNotifications(ctx context.Context, userID int64, unreadOnly bool) ([]*Notification, error) {
+	query := `
+		SELECT id, user_id, task_id, reminder_id, message, created_at, read_at
+		FROM notifications
+		WHERE user_id = $1`
+	if unreadOnly {
+		query += ` AND read_at IS NULL`
+	}
+	query += ` ORDER BY created_at DESC, id DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notifications: %w", err)
+	}
+	defer rows.Close()
+
+	var notifications []*Notification
+	for rows.Next() {
+		var n Notification
+		if err := rows.Scan(&n.ID, &n.UserID, &n.TaskID, &n.ReminderID, &n.Message, &n.CreatedAt, &n.ReadAt); err != nil {
+			return nil, fmt.Errorf("failed to scan notification: %w", err)
+		}
+		notifications = append(notifications, &n)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating notifications: %w", err)
+	}
+
+	return notifications, nil
+}
+
+// MarkNotificationRead marks one of the user's notifications as read
+func (r *PostgresRepository) MarkNotificationRead(ctx context.Context, id int64, userID int64, readAt time.Time) error {
+	query := `UPDATE notifications SET read_at = COALESCE(read_at, $3) WHERE id = $1 AND user_id = $2`
+
+	result, err := r.db.ExecContext(ctx, query, id, userID, readAt)
+	if err != nil {
+		return fmt.Errorf("failed to update notification: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return i18n.NewError("notification_not_found")
+	}
+
+	return nil
+}
@@ -0,0 +1,121 @@
+package reminders
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// SchedulerConfig tunes how often reminders are polled and how failed deliveries are retried
+type SchedulerConfig struct {
+	PollInterval time.Duration // how often due reminders are claimed
+	BatchSize    int           // maximum reminders claimed per tick
+	Lease        time.Duration // how long a claim is held before another replica may take over
+	MaxAttempts  int           // deliveries attempted before a reminder is marked failed
+	RetryBackoff time.Duration // delay before the first retry, doubled on every further attempt
+}
+
+// Scheduler periodically claims due reminders and hands them to the notifier of their channel.
+// Several replicas can run a Scheduler against the same database; claims never overlap.
+type Scheduler struct {
+	repo      Repository
+	notifiers map[string]Notifier
+	clock     Clock
+	cfg       SchedulerConfig
+}
+
+// NewScheduler creates a new reminder scheduler
+func NewScheduler(repo Repository, notifiers map[string]Notifier, clock Clock, cfg SchedulerConfig) *Scheduler {
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = 30 * time.Second
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 50
+	}
+	if cfg.Lease <= 0 {
+		cfg.Lease = 5 * time.Minute
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 5
+	}
+	if cfg.RetryBackoff <= 0 {
+		cfg.RetryBackoff = time.Minute
+	}
+
+	return &Scheduler{
+		repo:      repo,
+		notifiers: notifiers,
+		clock:     clock,
+		cfg:       cfg,
+	}
+}
+
+// Run processes due reminders every PollInterval until ctx is cancelled
+func (s *Scheduler) Run(ctx context.Context) {
+	log.Printf("Reminder scheduler started (poll every %s)", s.cfg.PollInterval)
+	ticker := time.NewTicker(s.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.Tick(ctx); err != nil {
+			log.Printf("Reminder scheduler: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			log.Printf("Reminder scheduler stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Tick claims one batch of due reminders and delivers them, returning how many were claimed
+func (s *Scheduler) Tick(ctx context.Context) (int, error) {
+	deliveries, err := s.repo.ClaimDue(ctx, s.clock.Now(), s.cfg.Lease, s.cfg.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, delivery := range deliveries {
+		s.deliver(ctx, delivery)
+	}
+
+	return len(deliveries), nil
+}
+
+// deliver sends a single reminder and records the outcome, scheduling a retry on failure
+func (s *Scheduler) deliver(ctx context.Context, delivery *Delivery) {
+	var err error
+	notifier, ok := s.notifiers[delivery.Channel]
+	if ok {
+		err = notifier.Notify(ctx, delivery)
+	} else {
+		err = errUnknownChannel(delivery.Channel)
+	}
+
+	if err == nil {
+		if err := s.repo.MarkSent(ctx, delivery.ReminderID, s.clock.Now()); err != nil {
+			log.Printf("Reminder scheduler: failed to mark reminder %d as sent: %v", delivery.ReminderID, err)
+		}
+		return
+	}
+
+	if !ok || delivery.Attempt >= s.cfg.MaxAttempts {
+		if err := s.repo.MarkFailed(ctx, delivery.ReminderID, err.Error()); err != nil {
+			log.Printf("Reminder scheduler: failed to mark reminder %d as failed: %v", delivery.ReminderID, err)
+		}
+		return
+	}
+
+	backoff := s.cfg.RetryBackoff << (delivery.Attempt - 1)
+	if err := s.repo.MarkRetry(ctx, delivery.ReminderID, s.clock.Now().Add(backoff), err.Error()); err != nil {
+		log.Printf("Reminder scheduler: failed to reschedule reminder %d: %v", delivery.ReminderID, err)
+	}
+}
+
+type errUnknownChannel string
+
+func (e errUnknownChannel) Error() string {
+	return "no notifier registered for channel " + string(e)
+}
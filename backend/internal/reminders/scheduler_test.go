@@ -0,0 +1,199 @@
+package reminders
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// fakeClock is a Clock stopped at a fixed time
+type fakeClock struct {
+	now time.Time
+}
+
+func (c fakeClock) Now() time.Time {
+	return c.now
+}
+
+// claim records the arguments of a ClaimDue call
+type claim struct {
+	now   time.Time
+	lease time.Duration
+	limit int
+}
+
+// outcome records how the scheduler settled a reminder
+type outcome struct {
+	status string
+	at     time.Time // sent_at or next_attempt_at
+	err    string
+}
+
+// fakeRepo hands out fixed deliveries and records what the scheduler does with them
+type fakeRepo struct {
+	Repository
+	due      []*Delivery
+	claims   []claim
+	outcomes map[int64]outcome
+}
+
+func newFakeRepo(due ...*Delivery) *fakeRepo {
+	return &fakeRepo{due: due, outcomes: make(map[int64]outcome)}
+}
+
+func (r *fakeRepo) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*Delivery, error) {
+	r.claims = append(r.claims, claim{now: now, lease: lease, limit: limit})
+	due := r.due
+	r.due = nil
+	return due, nil
+}
+
+func (r *fakeRepo) MarkSent(ctx context.Context, id int64, sentAt time.Time) error {
+	r.outcomes[id] = outcome{status: StatusSent, at: sentAt}
+	return nil
+}
+
+func (r *fakeRepo) MarkRetry(ctx context.Context, id int64, nextAttemptAt time.Time, lastError string) error {
+	r.outcomes[id] = outcome{status: StatusPending, at: nextAttemptAt, err: lastError}
+	return nil
+}
+
+func (r *fakeRepo) MarkFailed(ctx context.Context, id int64, lastError string) error {
+	r.outcomes[id] = outcome{status: StatusFailed, err: lastError}
+	return nil
+}
+
+// fakeNotifier fails with err, or succeeds when it is nil, and records its deliveries
+type fakeNotifier struct {
+	err        error
+	deliveries []*Delivery
+}
+
+func (n *fakeNotifier) Notify(ctx context.Context, delivery *Delivery) error {
+	n.deliveries = append(n.deliveries, delivery)
+	return n.err
+}
+
+func TestSchedulerTick(t *testing.T) {
+	now := time.Date(2024, 1, 20, 9, 0, 0, 0, time.UTC)
+	cfg := SchedulerConfig{MaxAttempts: 3, RetryBackoff: time.Minute}
+
+	tests := []struct {
+		name     string
+		delivery *Delivery
+		err      error
+		want     outcome
+	}{
+		{
+			name:     "delivered reminders are marked sent at the clock time",
+			delivery: &Delivery{ReminderID: 1, Channel: ChannelInApp, Attempt: 1},
+			want:     outcome{status: StatusSent, at: now},
+		},
+		{
+			name:     "a first failure is retried after the backoff",
+			delivery: &Delivery{ReminderID: 2, Channel: ChannelInApp, Attempt: 1},
+			err:      errors.New("connection refused"),
+			want:     outcome{status: StatusPending, at: now.Add(time.Minute), err: "connection refused"},
+		},
+		{
+			name:     "the backoff doubles on every further attempt",
+			delivery: &Delivery{ReminderID: 3, Channel: ChannelInApp, Attempt: 2},
+			err:      errors.New("connection refused"),
+			want:     outcome{status: StatusPending, at: now.Add(2 * time.Minute), err: "connection refused"},
+		},
+		{
+			name:     "the last attempt marks the reminder failed",
+			delivery: &Delivery{ReminderID: 4, Channel: ChannelInApp, Attempt: 3},
+			err:      errors.New("connection refused"),
+			want:     outcome{status: StatusFailed, err: "connection refused"},
+		},
+		{
+			name:     "channels without a notifier fail without retries",
+			delivery: &Delivery{ReminderID: 5, Channel: "sms", Attempt: 1},
+			want:     outcome{status: StatusFailed, err: "no notifier registered for channel sms"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeRepo(tt.delivery)
+			notifier := &fakeNotifier{err: tt.err}
+			scheduler := NewScheduler(repo, map[string]Notifier{ChannelInApp: notifier}, fakeClock{now: now}, cfg)
+
+			claimed, err := scheduler.Tick(context.Background())
+			if err != nil {
+				t.Fatalf("Tick returned %v", err)
+			}
+			if claimed != 1 {
+				t.Fatalf("Tick claimed %d reminders, want 1", claimed)
+			}
+
+			if got := repo.outcomes[tt.delivery.ReminderID]; got != tt.want {
+				t.Errorf("outcome = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSchedulerTickClaimsWithClockAndConfig(t *testing.T) {
+	now := time.Date(2024, 1, 20, 9, 0, 0, 0, time.UTC)
+	repo := newFakeRepo()
+	scheduler := NewScheduler(repo, nil, fakeClock{now: now}, SchedulerConfig{Lease: time.Minute, BatchSize: 10})
+
+	claimed, err := scheduler.Tick(context.Background())
+	if err != nil {
+		t.Fatalf("Tick returned %v", err)
+	}
+	if claimed != 0 {
+		t.Errorf("Tick claimed %d reminders, want 0", claimed)
+	}
+
+	want := []claim{{now: now, lease: time.Minute, limit: 10}}
+	if len(repo.claims) != 1 || repo.claims[0] != want[0] {
+		t.Errorf("claims = %+v, want %+v", repo.claims, want)
+	}
+}
+
+func TestNewSchedulerDefaults(t *testing.T) {
+	scheduler := NewScheduler(newFakeRepo(), nil, fakeClock{}, SchedulerConfig{})
+
+	want := SchedulerConfig{
+		PollInterval: 30 * time.Second,
+		BatchSize:    50,
+		Lease:        5 * time.Minute,
+		MaxAttempts:  5,
+		RetryBackoff: time.Minute,
+	}
+	if scheduler.cfg != want {
+		t.Errorf("cfg = %+v, want %+v", scheduler.cfg, want)
+	}
+}
+
+func TestSchedulerDeliversEveryClaimedReminder(t *testing.T) {
+	now := time.Date(2024, 1, 20, 9, 0, 0, 0, time.UTC)
+	repo := newFakeRepo(
+		&Delivery{ReminderID: 1, Channel: ChannelInApp, Attempt: 1},
+		&Delivery{ReminderID: 2, Channel: ChannelWebhook, Attempt: 1},
+	)
+	inApp := &fakeNotifier{}
+	webhook := &fakeNotifier{}
+	notifiers := map[string]Notifier{ChannelInApp: inApp, ChannelWebhook: webhook}
+	scheduler := NewScheduler(repo, notifiers, fakeClock{now: now}, SchedulerConfig{})
+
+	if _, err := scheduler.Tick(context.Background()); err != nil {
+		t.Fatalf("Tick returned %v", err)
+	}
+
+	if len(inApp.deliveries) != 1 || inApp.deliveries[0].ReminderID != 1 {
+		t.Errorf("in-app notifier got %+v, want reminder 1", inApp.deliveries)
+	}
+	if len(webhook.deliveries) != 1 || webhook.deliveries[0].ReminderID != 2 {
+		t.Errorf("webhook notifier got %+v, want reminder 2", webhook.deliveries)
+	}
+	for _, id := range []int64{1, 2} {
+		if got := repo.outcomes[id].status; got != StatusSent {
+			t.Errorf("reminder %d status = %q, want %q", id, got, StatusSent)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package reminders
+
+import (
+	"context"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+
+	"backend/root/internal/i18n"
+	"backend/root/internal/tasks"
+)
+
+// Service defines the interface for reminder business logic
+type Service interface {
+	Create(ctx context.Context, taskID int64, userID int64, req CreateReminderRequest) (*Reminder, error)
+	GetByTask(ctx context.Context, taskID int64, userID int64) ([]*Reminder, error)
+	Delete(ctx context.Context, id int64, userID int64) error
+	GetNotifications(ctx context.Context, userID int64, unreadOnly bool) ([]*Notification, error)
+	MarkNotificationRead(ctx context.Context, id int64, userID int64) error
+}
+
+// TaskRepository defines the minimal interface needed to validate task ownership
+type TaskRepository interface {
+	GetByID(ctx context.Context, id int64) (*tasks.Task, error)
+}
+
+// service implements the Service interface
+type service struct {
+	repo     Repository
+	taskRepo TaskRepository
+	clock    Clock
+}
+
+// NewService creates a new reminder service
+func NewService(repo Repository, taskRepo TaskRepository, clock Clock) Service {
+	return &service{
+		repo:     repo,
+		taskRepo: taskRepo,
+		clock:    clock,
+	}
+}
+
+// Create schedules a reminder for a task owned by the user
+func (s *service) Create(ctx context.Context, taskID int64, userID int64, req CreateReminderRequest) (*Reminder, error) {
+	task, err := s.getOwnedTask(ctx, taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	reminder := &Reminder{
+		TaskID:  taskID,
+		UserID:  userID,
+		Channel: req.Channel,
+		Target:  strings.TrimSpace(req.Target),
+	}
+
+	// Exactly one of an absolute time or an offset before the end date
+	switch {
+	case req.RemindAt != "" && req.OffsetMinutes != nil:
+		return nil, i18n.NewError("reminder_time_both")
+	case req.RemindAt != "":
+		remindAt, err := time.Parse(time.RFC3339, req.RemindAt)
+		if err != nil {
+			return nil, i18n.NewError("reminder_remind_at_format")
+		}
+		if !remindAt.After(s.clock.Now()) {
+			return nil, i18n.NewError("reminder_remind_at_past")
+		}
+		reminder.RemindAt = &remindAt
+	case req.OffsetMinutes != nil:
+		if *req.OffsetMinutes < 0 {
+			return nil, i18n.NewError("reminder_offset_negative")
+		}
+		if task.EndDate == nil {
+			return nil, i18n.NewError("reminder_offset_no_end_date")
+		}
+		reminder.OffsetMinutes = req.OffsetMinutes
+	default:
+		return nil, i18n.NewError("reminder_time_required")
+	}
+
+	// Validate the channel target
+	switch req.Channel {
+	case ChannelInApp:
+		reminder.Target = ""
+	case ChannelEmail:
+		if _, err := mail.ParseAddress(reminder.Target); err != nil {
+			return nil, i18n.NewError("reminder_email_target")
+		}
+	case ChannelWebhook:
+		u, err := url.Parse(reminder.Target)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, i18n.NewError("reminder_webhook_target")
+		}
+		if !isPublicHost(u.Hostname()) {
+			return nil, i18n.NewError("reminder_webhook_private")
+		}
+	default:
+		return nil, i18n.NewError("reminder_channel_invalid")
+	}
+
+	return s.repo.Create(ctx, reminder)
+}
+
+// GetByTask retrieves the reminders of a task owned by the user
+func (s *service) GetByTask(ctx context.Context, taskID int64, userID int64) ([]*Reminder, error) {
+	if _, err := s.getOwnedTask(ctx, taskID, userID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetByTask(ctx, taskID)
+}
+
+// Delete removes one of the user's reminders
+func (s *service) Delete(ctx context.Context, id int64, userID int64) error {
+	if id <= 0 {
+		return i18n.NewError("reminder_id_invalid")
+	}
+
+	reminder, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if reminder.UserID != userID {
+		return i18n.NewError("reminder_not_found")
+	}
+
+	return s.repo.Delete(ctx, id)
+}
+
+// GetNotifications retrieves the user's in-app notifications
+func (s *service) GetNotifications(ctx context.Context, userID int64, unreadOnly bool) ([]*Notification, error) {
+	return s.repo.GetNotifications(ctx, userID, unreadOnly)
+}
+
+// MarkNotificationRead marks one of the user's notifications as read
+func (s *service) MarkNotificationRead(ctx context.Context, id int64, userID int64) error {
+	if id <= 0 {
+		return i18n.NewError("notification_id_invalid")
+	}
+
+	return s.repo.MarkNotificationRead(ctx, id, userID, s.clock.Now())
+}
+
+// getOwnedTask loads a task and hides it from anyone but its owner
+func (s *service) getOwnedTask(ctx context.Context, taskID int64, userID int64) (*tasks.Task, error) {
+	if taskID <= 0 {
+		return nil, i18n.NewError("task_id_invalid")
+	}
+
+	task, err := s.taskRepo.GetByID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if task == nil || task.UserID != userID {
+		return nil, tasks.ErrTaskNotFound
+	}
+
+	return task, nil
+}
@@ -0,0 +1,244 @@
+package shares
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+
+	"backend/root/internal/categories"
+	"backend/root/internal/i18n"
+	"backend/root/internal/tasks"
+	"backend/root/internal/users"
+)
+
+// Handler handles HTTP requests for sharing tasks and categories
+type Handler struct {
+	service Service
+}
+
+// NewHandler creates a new sharing handler
+func NewHandler(service Service) *Handler {
+	return &Handler{
+		service: service,
+	}
+}
+
+// ShareTask handles POST /tasks/:id/shares - shares a task with another user
+func (h *Handler) ShareTask(c *gin.Context) {
+	taskID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, i18n.NewError("task_id_invalid"))
+		return
+	}
+
+	var req CreateShareRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, i18n.NewError("request_invalid_detail", err))
+		return
+	}
+
+	userID, err := getUserIDFromClaims(c)
+	if err != nil {
+		i18n.RespondError(c, http.StatusUnauthorized, err)
+		return
+	}
+
+	share, err := h.service.ShareTask(c.Request.Context(), taskID, userID, req)
+	if err != nil {
+		i18n.RespondError(c, statusFor(err), err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, share)
+}
+
+// GetByTask handles GET /tasks/:id/shares - lists who a task is shared with
+func (h *Handler) GetByTask(c *gin.Context) {
+	taskID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, i18n.NewError("task_id_invalid"))
+		return
+	}
+
+	userID, err := getUserIDFromClaims(c)
+	if err != nil {
+		i18n.RespondError(c, http.StatusUnauthorized, err)
+		return
+	}
+
+	shares, err := h.service.GetByTask(c.Request.Context(), taskID, userID)
+	if err != nil {
+		i18n.RespondError(c, statusFor(err), err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"shares": shares,
+		"count":  len(shares),
+	})
+}
+
+// ShareCategory handles POST /categories/:id/shares - shares the caller's tasks in a category
+func (h *Handler) ShareCategory(c *gin.Context) {
+	categoryID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, i18n.NewError("category_id_invalid"))
+		return
+	}
+
+	var req CreateShareRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, i18n.NewError("request_invalid_detail", err))
+		return
+	}
+
+	userID, err := getUserIDFromClaims(c)
+	if err != nil {
+		i18n.RespondError(c, http.StatusUnauthorized, err)
+		return
+	}
+
+	share, err := h.service.ShareCategory(c.Request.Context(), categoryID, userID, req)
+	if err != nil {
+		i18n.RespondError(c, statusFor(err), err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, share)
+}
+
+// GetByCategory handles GET /categories/:id/shares - lists who the caller's tasks in a category are shared with
+func (h *Handler) GetByCategory(c *gin.Context) {
+	categoryID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, i18n.NewError("category_id_invalid"))
+		return
+	}
+
+	userID, err := getUserIDFromClaims(c)
+	if err != nil {
+		i18n.RespondError(c, http.StatusUnauthorized, err)
+		return
+	}
+
+	shares, err := h.service.GetByCategory(c.Request.Context(), categoryID, userID)
+	if err != nil {
+		i18n.RespondError(c, statusFor(err), err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"shares": shares,
+		"count":  len(shares),
+	})
+}
+
+// GetAll handles GET /shares - lists the shares the caller created and received
+func (h *Handler) GetAll(c *gin.Context) {
+	userID, err := getUserIDFromClaims(c)
+	if err != nil {
+		i18n.RespondError(c, http.StatusUnauthorized, err)
+		return
+	}
+
+	list, err := h.service.GetAll(c.Request.Context(), userID)
+	if err != nil {
+		i18n.RespondError(c, http.StatusInternalServerError, i18n.NewError("shares_get_failed"))
+		return
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+// Update handles PATCH /shares/:id - changes the role granted by a share
+func (h *Handler) Update(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, i18n.NewError("share_id_invalid"))
+		return
+	}
+
+	var req UpdateShareRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, i18n.NewError("request_invalid_detail", err))
+		return
+	}
+
+	userID, err := getUserIDFromClaims(c)
+	if err != nil {
+		i18n.RespondError(c, http.StatusUnauthorized, err)
+		return
+	}
+
+	share, err := h.service.UpdateRole(c.Request.Context(), id, userID, req)
+	if err != nil {
+		i18n.RespondError(c, statusFor(err), err)
+		return
+	}
+
+	c.JSON(http.StatusOK, share)
+}
+
+// Revoke handles DELETE /shares/:id - revokes a share, or leaves one shared with the caller
+func (h *Handler) Revoke(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, i18n.NewError("share_id_invalid"))
+		return
+	}
+
+	userID, err := getUserIDFromClaims(c)
+	if err != nil {
+		i18n.RespondError(c, http.StatusUnauthorized, err)
+		return
+	}
+
+	if err := h.service.Revoke(c.Request.Context(), id, userID); err != nil {
+		i18n.RespondError(c, statusFor(err), err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "share revoked successfully"})
+}
+
+// statusFor maps service errors to HTTP status codes
+func statusFor(err error) int {
+	switch {
+	case errors.Is(err, tasks.ErrTaskNotFound), errors.Is(err, categories.ErrCategoryNotFound),
+		errors.Is(err, users.ErrUserNotFound), errors.Is(err, ErrShareNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrOwnerOnly), errors.Is(err, ErrChangeOwnerOnly):
+		return http.StatusForbidden
+	case errors.Is(err, ErrShareExists):
+		return http.StatusConflict
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+// getUserIDFromClaims extracts the user ID from the JWT claims set by the auth middleware
+func getUserIDFromClaims(c *gin.Context) (int64, error) {
+	claims, exists := c.Get("claims")
+	if !exists {
+		return 0, i18n.NewError("user_not_authenticated")
+	}
+
+	claimsMap, ok := claims.(jwt.MapClaims)
+	if !ok {
+		return 0, errors.New("invalid claims")
+	}
+
+	switch v := claimsMap["uid"].(type) {
+	case float64:
+		return int64(v), nil
+	case int64:
+		return v, nil
+	case int:
+		return int64(v), nil
+	default:
+		return 0, i18n.NewError("user_id_missing")
+	}
+}
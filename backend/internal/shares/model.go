@@ -0,0 +1,45 @@
+package shares
+
+import (
+	"time"
+
+	"backend/root/internal/i18n"
+)
+
+// Share grants another user access to a task, or to all of the owner's tasks in a category
+type Share struct {
+	ID            int64     `json:"id" db:"id"`
+	OwnerID       int64     `json:"owner_id" db:"owner_id"`
+	OwnerUsername string    `json:"owner_username"`
+	UserID        int64     `json:"user_id" db:"user_id"` // The user the tasks are shared with
+	Username      string    `json:"username"`
+	TaskID        *int64    `json:"task_id" db:"task_id"`         // Set for a task share
+	CategoryID    *int64    `json:"category_id" db:"category_id"` // Set for a category share
+	Role          string    `json:"role" db:"role"`               // viewer or editor
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+}
+
+// ShareList groups the shares a user created and the ones they received
+type ShareList struct {
+	Granted  []*Share `json:"granted"`
+	Received []*Share `json:"received"`
+}
+
+// CreateShareRequest represents the request payload for sharing a task or a category
+type CreateShareRequest struct {
+	Username string `json:"username" binding:"required"`
+	Role     string `json:"role" binding:"required"` // viewer or editor
+}
+
+// UpdateShareRequest represents the request payload for changing the role of a share
+type UpdateShareRequest struct {
+	Role string `json:"role" binding:"required"` // viewer or editor
+}
+
+// Errors returned when a share cannot be found, created or changed
+var (
+	ErrShareNotFound   = i18n.NewError("share_not_found")
+	ErrShareExists     = i18n.NewError("share_exists")
+	ErrOwnerOnly       = i18n.NewError("share_owner_only")
+	ErrChangeOwnerOnly = i18n.NewError("share_change_owner_only")
+)
@@ -0,0 +1,185 @@
+package shares
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+// Repository defines the interface for share data operations
+type Repository interface {
+	Create(ctx context.Context, share *Share) (*Share, error)
+	GetByID(ctx context.Context, id int64) (*Share, error)
+	GetForTask(ctx context.Context, taskID int64, ownerID int64, categoryID *int64) ([]*Share, error)
+	GetByCategory(ctx context.Context, ownerID int64, categoryID int64) ([]*Share, error)
+	GetGranted(ctx context.Context, ownerID int64) ([]*Share, error)
+	GetReceived(ctx context.Context, userID int64) ([]*Share, error)
+	UpdateRole(ctx context.Context, id int64, role string) (*Share, error)
+	Delete(ctx context.Context, id int64) error
+}
+
+// uniqueViolation is the PostgreSQL error code for a unique constraint violation
+const uniqueViolation = "23505"
+
+// shareSelect selects a share with the names of both users, in the order expected by scanShare
+const shareSelect = `
+	SELECT sh.id, sh.owner_id, o.username, sh.user_id, u.username, sh.task_id, sh.category_id, sh.role, sh.created_at
+	FROM task_shares sh
+	JOIN users o ON o.id = sh.owner_id
+	JOIN users u ON u.id = sh.user_id`
+
+// PostgresRepository implements Repository using PostgreSQL
+type PostgresRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresRepository creates a new PostgreSQL shares repository
+func NewPostgresRepository(db *sql.DB) *PostgresRepository {
+	return &PostgresRepository{db: db}
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanShare(row rowScanner) (*Share, error) {
+	var share Share
+	err := row.Scan(
+		&share.ID, &share.OwnerID, &share.OwnerUsername, &share.UserID, &share.Username,
+		&share.TaskID, &share.CategoryID, &share.Role, &share.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &share, nil
+}
+
+// isUniqueViolation reports whether err was caused by a unique constraint
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == uniqueViolation
+}
+
+// Create stores a new share. The user can hold only one share per task and per owner's category.
+func (r *PostgresRepository) Create(ctx context.Context, share *Share) (*Share, error) {
+	query := `
+		INSERT INTO task_shares (owner_id, user_id, task_id, category_id, role)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id`
+
+	var id int64
+	err := r.db.QueryRowContext(ctx, query, share.OwnerID, share.UserID, share.TaskID, share.CategoryID, share.Role).Scan(&id)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrShareExists
+		}
+		return nil, fmt.Errorf("failed to create share: %w", err)
+	}
+
+	return r.GetByID(ctx, id)
+}
+
+// GetByID retrieves a share by its ID
+func (r *PostgresRepository) GetByID(ctx context.Context, id int64) (*Share, error) {
+	share, err := scanShare(r.db.QueryRowContext(ctx, shareSelect+` WHERE sh.id = $1`, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrShareNotFound
+		}
+		return nil, fmt.Errorf("failed to get share: %w", err)
+	}
+
+	return share, nil
+}
+
+// GetForTask retrieves every share giving access to a task: shares of the task itself and of
+// its owner's category
+func (r *PostgresRepository) GetForTask(ctx context.Context, taskID int64, ownerID int64, categoryID *int64) ([]*Share, error) {
+	query := shareSelect + `
+		WHERE sh.task_id = $1 OR (sh.owner_id = $2 AND sh.category_id = $3)
+		ORDER BY u.username, sh.id`
+
+	return r.query(ctx, query, taskID, ownerID, categoryID)
+}
+
+// GetByCategory retrieves the shares of one of the owner's categories
+func (r *PostgresRepository) GetByCategory(ctx context.Context, ownerID int64, categoryID int64) ([]*Share, error) {
+	query := shareSelect + `
+		WHERE sh.owner_id = $1 AND sh.category_id = $2
+		ORDER BY u.username, sh.id`
+
+	return r.query(ctx, query, ownerID, categoryID)
+}
+
+// GetGranted retrieves the shares created by the owner, newest first
+func (r *PostgresRepository) GetGranted(ctx context.Context, ownerID int64) ([]*Share, error) {
+	return r.query(ctx, shareSelect+` WHERE sh.owner_id = $1 ORDER BY sh.created_at DESC, sh.id DESC`, ownerID)
+}
+
+// GetReceived retrieves the shares other users created for the user, newest first
+func (r *PostgresRepository) GetReceived(ctx context.Context, userID int64) ([]*Share, error) {
+	return r.query(ctx, shareSelect+` WHERE sh.user_id = $1 ORDER BY sh.created_at DESC, sh.id DESC`, userID)
+}
+
+// UpdateRole changes the role granted by a share
+func (r *PostgresRepository) UpdateRole(ctx context.Context, id int64, role string) (*Share, error) {
+	result, err := r.db.ExecContext(ctx, `UPDATE task_shares SET role = $2 WHERE id = $1`, id, role)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update share: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return nil, ErrShareNotFound
+	}
+
+	return r.GetByID(ctx, id)
+}
+
+// Delete removes a share, revoking the access it granted
+func (r *PostgresRepository) Delete(ctx context.Context, id int64) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM task_shares WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete share: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrShareNotFound
+	}
+
+	return nil
+}
+
+// query runs a shareSelect based query and scans every share it returns
+func (r *PostgresRepository) query(ctx context.Context, query string, args ...interface{}) ([]*Share, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shares: %w", err)
+	}
+	defer rows.Close()
+
+	shares := []*Share{}
+	for rows.Next() {
+		share, err := scanShare(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan share: %w", err)
+		}
+		shares = append(shares, share)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating shares: %w", err)
+	}
+
+	return shares, nil
+}
@@ -0,0 +1,244 @@
+package shares
+
+import (
+	"context"
+	"strings"
+
+	"backend/root/internal/categories"
+	"backend/root/internal/i18n"
+	"backend/root/internal/tasks"
+	"backend/root/internal/users"
+	"backend/root/internal/workspaces"
+)
+
+// Service defines the interface for sharing business logic
+type Service interface {
+	ShareTask(ctx context.Context, taskID int64, userID int64, req CreateShareRequest) (*Share, error)
+	ShareCategory(ctx context.Context, categoryID int64, userID int64, req CreateShareRequest) (*Share, error)
+	GetByTask(ctx context.Context, taskID int64, userID int64) ([]*Share, error)
+	GetByCategory(ctx context.Context, categoryID int64, userID int64) ([]*Share, error)
+	GetAll(ctx context.Context, userID int64) (*ShareList, error)
+	UpdateRole(ctx context.Context, id int64, userID int64, req UpdateShareRequest) (*Share, error)
+	Revoke(ctx context.Context, id int64, userID int64) error
+}
+
+// TaskRepository defines the minimal interface needed to validate task ownership
+type TaskRepository interface {
+	GetByID(ctx context.Context, id int64) (*tasks.Task, error)
+	GetRole(ctx context.Context, id int64, userID int64) (string, error)
+}
+
+// CategoryRepository defines the minimal interface needed to validate categories
+type CategoryRepository interface {
+	Exists(ctx context.Context, id int64) (bool, error)
+}
+
+// UserRepository defines the minimal interface needed to find the user to share with
+type UserRepository interface {
+	FindByUsername(ctx context.Context, username string) (*users.User, error)
+}
+
+// service implements the Service interface
+type service struct {
+	repo     Repository
+	taskRepo TaskRepository
+	catRepo  CategoryRepository
+	userRepo UserRepository
+}
+
+// NewService creates a new sharing service
+func NewService(repo Repository, taskRepo TaskRepository, catRepo CategoryRepository, userRepo UserRepository) Service {
+	return &service{
+		repo:     repo,
+		taskRepo: taskRepo,
+		catRepo:  catRepo,
+		userRepo: userRepo,
+	}
+}
+
+// ShareTask shares one of the user's tasks with another user
+func (s *service) ShareTask(ctx context.Context, taskID int64, userID int64, req CreateShareRequest) (*Share, error) {
+	task, err := s.getOwnedTask(ctx, taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	share, err := s.newShare(ctx, userID, req)
+	if err != nil {
+		return nil, err
+	}
+	share.TaskID = &task.ID
+
+	return s.repo.Create(ctx, share)
+}
+
+// ShareCategory shares all of the user's tasks in a category, present and future, with another user
+func (s *service) ShareCategory(ctx context.Context, categoryID int64, userID int64, req CreateShareRequest) (*Share, error) {
+	if err := s.checkCategory(ctx, categoryID); err != nil {
+		return nil, err
+	}
+
+	share, err := s.newShare(ctx, userID, req)
+	if err != nil {
+		return nil, err
+	}
+	share.CategoryID = &categoryID
+
+	return s.repo.Create(ctx, share)
+}
+
+// GetByTask lists who has access to one of the user's tasks, through the task or its category
+func (s *service) GetByTask(ctx context.Context, taskID int64, userID int64) ([]*Share, error) {
+	task, err := s.getOwnedTask(ctx, taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetForTask(ctx, task.ID, task.UserID, task.CategoryID)
+}
+
+// GetByCategory lists the users the user's tasks in a category are shared with
+func (s *service) GetByCategory(ctx context.Context, categoryID int64, userID int64) ([]*Share, error) {
+	if err := s.checkCategory(ctx, categoryID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetByCategory(ctx, userID, categoryID)
+}
+
+// GetAll lists the shares the user created and the ones they received
+func (s *service) GetAll(ctx context.Context, userID int64) (*ShareList, error) {
+	granted, err := s.repo.GetGranted(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	received, err := s.repo.GetReceived(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ShareList{Granted: granted, Received: received}, nil
+}
+
+// UpdateRole changes the role granted by one of the user's shares
+func (s *service) UpdateRole(ctx context.Context, id int64, userID int64, req UpdateShareRequest) (*Share, error) {
+	role, err := normalizeRole(req.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	share, err := s.getShare(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if share.OwnerID != userID {
+		return nil, ErrChangeOwnerOnly
+	}
+
+	return s.repo.UpdateRole(ctx, id, role)
+}
+
+// Revoke removes a share. The owner revokes access; the user it was shared with can leave it.
+func (s *service) Revoke(ctx context.Context, id int64, userID int64) error {
+	if _, err := s.getShare(ctx, id, userID); err != nil {
+		return err
+	}
+
+	return s.repo.Delete(ctx, id)
+}
+
+// newShare validates a share request of the owner and resolves the user to share with.
+// Tasks and categories of a workspace are shared by inviting users to the workspace instead.
+func (s *service) newShare(ctx context.Context, ownerID int64, req CreateShareRequest) (*Share, error) {
+	if workspaces.IDFromContext(ctx) != nil {
+		return nil, i18n.NewError("share_in_workspace")
+	}
+
+	role, err := normalizeRole(req.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.FindByUsername(ctx, strings.TrimSpace(req.Username))
+	if err != nil {
+		return nil, users.ErrUserNotFound
+	}
+	if user.ID == ownerID {
+		return nil, i18n.NewError("share_with_owner")
+	}
+
+	return &Share{OwnerID: ownerID, UserID: user.ID, Role: role}, nil
+}
+
+// getOwnedTask loads a task the user owns. Users it is only shared with learn that they cannot
+// manage its shares; everyone else does not learn that it exists.
+func (s *service) getOwnedTask(ctx context.Context, taskID int64, userID int64) (*tasks.Task, error) {
+	if taskID <= 0 {
+		return nil, i18n.NewError("task_id_invalid")
+	}
+
+	task, err := s.taskRepo.GetByID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if task == nil {
+		return nil, tasks.ErrTaskNotFound
+	}
+
+	if task.UserID != userID {
+		role, err := s.taskRepo.GetRole(ctx, taskID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if role == "" {
+			return nil, tasks.ErrTaskNotFound
+		}
+		return nil, ErrOwnerOnly
+	}
+
+	return task, nil
+}
+
+// getShare loads a share that the user created or received
+func (s *service) getShare(ctx context.Context, id int64, userID int64) (*Share, error) {
+	if id <= 0 {
+		return nil, i18n.NewError("share_id_invalid")
+	}
+
+	share, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if share.OwnerID != userID && share.UserID != userID {
+		return nil, ErrShareNotFound
+	}
+
+	return share, nil
+}
+
+// checkCategory checks that a category exists
+func (s *service) checkCategory(ctx context.Context, categoryID int64) error {
+	if categoryID <= 0 {
+		return i18n.NewError("category_id_invalid")
+	}
+
+	exists, err := s.catRepo.Exists(ctx, categoryID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return categories.ErrCategoryNotFound
+	}
+
+	return nil
+}
+
+// normalizeRole validates a role that can be granted by sharing
+func normalizeRole(role string) (string, error) {
+	role = strings.ToLower(strings.TrimSpace(role))
+	if !tasks.IsValidShareRole(role) {
+		return "", i18n.NewError("share_role_invalid")
+	}
+	return role, nil
+}
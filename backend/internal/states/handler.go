@@ -0,0 +1,156 @@
+package states
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+
+	"backend/root/internal/i18n"
+)
+
+// Handler handles HTTP requests for workflow states
+type Handler struct {
+	service Service
+}
+
+// NewHandler creates a new workflow states handler
+func NewHandler(service Service) *Handler {
+	return &Handler{
+		service: service,
+	}
+}
+
+// Create handles POST /states - creates a workflow state for the user
+func (h *Handler) Create(c *gin.Context) {
+	var req CreateStateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, i18n.NewError("request_invalid_detail", err))
+		return
+	}
+
+	userID, err := getUserIDFromClaims(c)
+	if err != nil {
+		i18n.RespondError(c, http.StatusUnauthorized, err)
+		return
+	}
+
+	state, err := h.service.Create(c.Request.Context(), userID, req)
+	if err != nil {
+		i18n.RespondError(c, statusFor(err), err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, state)
+}
+
+// GetAll handles GET /states - lists the built-in and the user's states in workflow order
+func (h *Handler) GetAll(c *gin.Context) {
+	userID, err := getUserIDFromClaims(c)
+	if err != nil {
+		i18n.RespondError(c, http.StatusUnauthorized, err)
+		return
+	}
+
+	states, err := h.service.GetAllByUser(c.Request.Context(), userID)
+	if err != nil {
+		i18n.RespondError(c, http.StatusInternalServerError, i18n.NewError("states_get_failed"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"states": states,
+		"count":  len(states),
+	})
+}
+
+// Update handles PUT /states/:id - renames, reorders or changes the done flag of a state
+func (h *Handler) Update(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, i18n.NewError("state_id_invalid"))
+		return
+	}
+
+	var req UpdateStateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, i18n.NewError("request_invalid_detail", err))
+		return
+	}
+
+	userID, err := getUserIDFromClaims(c)
+	if err != nil {
+		i18n.RespondError(c, http.StatusUnauthorized, err)
+		return
+	}
+
+	state, err := h.service.Update(c.Request.Context(), id, userID, req)
+	if err != nil {
+		i18n.RespondError(c, statusFor(err), err)
+		return
+	}
+
+	c.JSON(http.StatusOK, state)
+}
+
+// Delete handles DELETE /states/:id - deletes a state no task is in
+func (h *Handler) Delete(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, i18n.NewError("state_id_invalid"))
+		return
+	}
+
+	userID, err := getUserIDFromClaims(c)
+	if err != nil {
+		i18n.RespondError(c, http.StatusUnauthorized, err)
+		return
+	}
+
+	if err := h.service.Delete(c.Request.Context(), id, userID); err != nil {
+		i18n.RespondError(c, statusFor(err), err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "state deleted successfully"})
+}
+
+// statusFor maps service errors to HTTP status codes
+func statusFor(err error) int {
+	switch {
+	case errors.Is(err, ErrStateNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrBuiltIn):
+		return http.StatusForbidden
+	case errors.Is(err, ErrStateExists), errors.Is(err, ErrStateInUse):
+		return http.StatusConflict
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+// getUserIDFromClaims extracts the user ID from the JWT claims set by the auth middleware
+func getUserIDFromClaims(c *gin.Context) (int64, error) {
+	claims, exists := c.Get("claims")
+	if !exists {
+		return 0, i18n.NewError("user_not_authenticated")
+	}
+
+	claimsMap, ok := claims.(jwt.MapClaims)
+	if !ok {
+		return 0, errors.New("invalid claims")
+	}
+
+	switch v := claimsMap["uid"].(type) {
+	case float64:
+		return int64(v), nil
+	case int64:
+		return v, nil
+	case int:
+		return int64(v), nil
+	default:
+		return 0, i18n.NewError("user_id_missing")
+	}
+}
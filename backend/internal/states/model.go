@@ -0,0 +1,37 @@
+package states
+
+import "backend/root/internal/i18n"
+
+// State is a workflow state a task can be in. Built-in states are shared by every user
+// and have no owner; users add their own states next to them.
+type State struct {
+	ID          int64  `json:"id" db:"id"`
+	UserID      *int64 `json:"user_id" db:"user_id"` // nil for built-in states
+	Description string `json:"description" db:"description"`
+	Position    int    `json:"position" db:"position"` // States are listed by ascending position
+	IsDone      bool   `json:"is_done" db:"is_done"`   // Tasks in a done state count as finished
+	BuiltIn     bool   `json:"built_in"`
+	TaskCount   int    `json:"task_count"` // Number of the user's tasks in the state
+}
+
+// CreateStateRequest represents the request payload for creating a workflow state
+type CreateStateRequest struct {
+	Description string `json:"description" binding:"required,min=1,max=100"`
+	Position    *int   `json:"position,omitempty"` // Defaults to after the last state
+	IsDone      bool   `json:"is_done"`
+}
+
+// UpdateStateRequest represents the request payload for updating a workflow state; omitted fields are kept
+type UpdateStateRequest struct {
+	Description *string `json:"description,omitempty" binding:"omitempty,min=1,max=100"`
+	Position    *int    `json:"position,omitempty"`
+	IsDone      *bool   `json:"is_done,omitempty"`
+}
+
+// Errors returned when a state cannot be found, created, changed or deleted
+var (
+	ErrStateNotFound = i18n.NewError("state_not_found")
+	ErrStateExists   = i18n.NewError("state_exists")
+	ErrBuiltIn       = i18n.NewError("state_built_in")
+	ErrStateInUse    = i18n.NewError("state_in_use")
+)
@@ -0,0 +1,160 @@
+package states
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+// Repository defines the interface for workflow state persistence
+type Repository interface {
+	Create(ctx context.Context, userID int64, description string, position *int, isDone bool) (*State, error)
+	GetByID(ctx context.Context, id int64, userID int64) (*State, error)
+	GetAllByUser(ctx context.Context, userID int64) ([]*State, error)
+	Update(ctx context.Context, state *State) (*State, error)
+	Delete(ctx context.Context, id int64) error
+}
+
+// stateSelect selects a state with the number of the user's live tasks in it, in the order expected by scanState.
+// The user ID is always the first query argument.
+const stateSelect = `
+		SELECT s.id, s.user_id, s.description, s.position, s.is_done,
+			(SELECT COUNT(*) FROM tasks t WHERE t.id_state = s.id AND t.id_user = $1 AND t.deleted_at IS NULL)
+		FROM states s`
+
+// uniqueViolation is the PostgreSQL error code raised by a duplicate key
+const uniqueViolation = "23505"
+
+// PostgresRepository implements Repository using PostgreSQL
+type PostgresRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresRepository creates a new PostgreSQL workflow states repository
+func NewPostgresRepository(db *sql.DB) *PostgresRepository {
+	return &PostgresRepository{db: db}
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanState(row rowScanner) (*State, error) {
+	var state State
+	err := row.Scan(&state.ID, &state.UserID, &state.Description, &state.Position, &state.IsDone, &state.TaskCount)
+	if err != nil {
+		return nil, err
+	}
+
+	state.BuiltIn = state.UserID == nil
+	return &state, nil
+}
+
+// isUniqueViolation reports whether err was caused by a unique constraint
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == uniqueViolation
+}
+
+// Create stores a new state for the user. Without a position the state goes after every state the user sees.
+func (r *PostgresRepository) Create(ctx context.Context, userID int64, description string, position *int, isDone bool) (*State, error) {
+	query := `
+		INSERT INTO states (user_id, description, position, is_done)
+		VALUES ($1, $2, COALESCE($3, (SELECT COALESCE(MAX(position), 0) + 10 FROM states WHERE user_id IS NULL OR user_id = $1)), $4)
+		RETURNING id, user_id, description, position, is_done, 0`
+
+	created, err := scanState(r.db.QueryRowContext(ctx, query, userID, description, position, isDone))
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrStateExists
+		}
+		return nil, fmt.Errorf("failed to create state: %w", err)
+	}
+
+	return created, nil
+}
+
+// GetByID retrieves a state visible to the user: a built-in state or one of their own
+func (r *PostgresRepository) GetByID(ctx context.Context, id int64, userID int64) (*State, error) {
+	query := stateSelect + ` WHERE s.id = $2 AND (s.user_id IS NULL OR s.user_id = $1)`
+
+	state, err := scanState(r.db.QueryRowContext(ctx, query, userID, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrStateNotFound
+		}
+		return nil, fmt.Errorf("failed to get state: %w", err)
+	}
+
+	return state, nil
+}
+
+// GetAllByUser retrieves the built-in states and the user's own states, in workflow order
+func (r *PostgresRepository) GetAllByUser(ctx context.Context, userID int64) ([]*State, error) {
+	query := stateSelect + ` WHERE s.user_id IS NULL OR s.user_id = $1 ORDER BY s.position, s.id`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get states: %w", err)
+	}
+	defer rows.Close()
+
+	states := []*State{}
+	for rows.Next() {
+		state, err := scanState(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan state: %w", err)
+		}
+		states = append(states, state)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating states: %w", err)
+	}
+
+	return states, nil
+}
+
+// Update stores the description, position and done flag of a user-owned state
+func (r *PostgresRepository) Update(ctx context.Context, state *State) (*State, error) {
+	query := `
+		UPDATE states SET description = $2, position = $3, is_done = $4
+		WHERE id = $1 AND user_id IS NOT NULL
+		RETURNING id, user_id, description, position, is_done, 0`
+
+	updated, err := scanState(r.db.QueryRowContext(ctx, query, state.ID, state.Description, state.Position, state.IsDone))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrStateNotFound
+		}
+		if isUniqueViolation(err) {
+			return nil, ErrStateExists
+		}
+		return nil, fmt.Errorf("failed to update state: %w", err)
+	}
+
+	updated.TaskCount = state.TaskCount
+	return updated, nil
+}
+
+// Delete removes a user-owned state; tasks in the trash that still use it fall back to Not Started
+func (r *PostgresRepository) Delete(ctx context.Context, id int64) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM states WHERE id = $1 AND user_id IS NOT NULL`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete state: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrStateNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,120 @@
+package states
+
+import (
+	"context"
+	"strings"
+
+	"backend/root/internal/i18n"
+)
+
+// Service defines the interface for workflow state business logic
+type Service interface {
+	Create(ctx context.Context, userID int64, req CreateStateRequest) (*State, error)
+	GetAllByUser(ctx context.Context, userID int64) ([]*State, error)
+	Update(ctx context.Context, id int64, userID int64, req UpdateStateRequest) (*State, error)
+	Delete(ctx context.Context, id int64, userID int64) error
+}
+
+// service implements the Service interface
+type service struct {
+	repo Repository
+}
+
+// NewService creates a new workflow state service
+func NewService(repo Repository) Service {
+	return &service{
+		repo: repo,
+	}
+}
+
+// Create adds a workflow state for the user
+func (s *service) Create(ctx context.Context, userID int64, req CreateStateRequest) (*State, error) {
+	description, err := normalizeDescription(req.Description)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.Create(ctx, userID, description, req.Position, req.IsDone)
+}
+
+// GetAllByUser retrieves the states the user can put tasks in, in workflow order
+func (s *service) GetAllByUser(ctx context.Context, userID int64) ([]*State, error) {
+	states, err := s.repo.GetAllByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Built-in states are shown in the language of the request
+	locale := i18n.FromContext(ctx)
+	for _, state := range states {
+		if state.BuiltIn {
+			state.Description = i18n.StateName(locale, state.ID, state.Description)
+		}
+	}
+	return states, nil
+}
+
+// Update renames, moves or changes the done flag of one of the user's states
+func (s *service) Update(ctx context.Context, id int64, userID int64, req UpdateStateRequest) (*State, error) {
+	state, err := s.getOwnedState(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Description != nil {
+		if state.Description, err = normalizeDescription(*req.Description); err != nil {
+			return nil, err
+		}
+	}
+	if req.Position != nil {
+		state.Position = *req.Position
+	}
+	if req.IsDone != nil {
+		state.IsDone = *req.IsDone
+	}
+
+	return s.repo.Update(ctx, state)
+}
+
+// Delete removes one of the user's states once no task is in it
+func (s *service) Delete(ctx context.Context, id int64, userID int64) error {
+	state, err := s.getOwnedState(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+	if state.TaskCount > 0 {
+		return ErrStateInUse
+	}
+
+	return s.repo.Delete(ctx, id)
+}
+
+// getOwnedState loads a state the user may change: built-in states are read-only
+func (s *service) getOwnedState(ctx context.Context, id int64, userID int64) (*State, error) {
+	if id <= 0 {
+		return nil, i18n.NewError("state_id_invalid")
+	}
+
+	state, err := s.repo.GetByID(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if state.BuiltIn {
+		return nil, ErrBuiltIn
+	}
+
+	return state, nil
+}
+
+// normalizeDescription trims a state description and validates its length
+func normalizeDescription(description string) (string, error) {
+	description = strings.TrimSpace(description)
+	if description == "" {
+		return "", i18n.NewError("state_description_empty")
+	}
+	if len(description) > 100 {
+		return "", i18n.NewError("state_description_long")
+	}
+
+	return description, nil
+}
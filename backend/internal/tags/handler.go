@@ -0,0 +1,213 @@
+package tags
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+
+	"backend/root/internal/i18n"
+	"backend/root/internal/tasks"
+)
+
+// Handler handles HTTP requests for tags
+type Handler struct {
+	service Service
+}
+
+// NewHandler creates a new tags handler
+func NewHandler(service Service) *Handler {
+	return &Handler{
+		service: service,
+	}
+}
+
+// Create handles POST /tags - creates a tag for the user
+func (h *Handler) Create(c *gin.Context) {
+	var req CreateTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, i18n.NewError("request_invalid_detail", err))
+		return
+	}
+
+	userID, err := getUserIDFromClaims(c)
+	if err != nil {
+		i18n.RespondError(c, http.StatusUnauthorized, err)
+		return
+	}
+
+	tag, err := h.service.Create(c.Request.Context(), userID, req)
+	if err != nil {
+		i18n.RespondError(c, statusFor(err), err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, tag)
+}
+
+// GetAll handles GET /tags - lists the user's tags
+func (h *Handler) GetAll(c *gin.Context) {
+	userID, err := getUserIDFromClaims(c)
+	if err != nil {
+		i18n.RespondError(c, http.StatusUnauthorized, err)
+		return
+	}
+
+	tags, err := h.service.GetAllByUser(c.Request.Context(), userID)
+	if err != nil {
+		i18n.RespondError(c, http.StatusInternalServerError, i18n.NewError("tags_get_failed"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"tags":  tags,
+		"count": len(tags),
+	})
+}
+
+// Update handles PUT /tags/:id - renames a tag
+func (h *Handler) Update(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, i18n.NewError("tag_id_invalid"))
+		return
+	}
+
+	var req UpdateTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, i18n.NewError("request_invalid_detail", err))
+		return
+	}
+
+	userID, err := getUserIDFromClaims(c)
+	if err != nil {
+		i18n.RespondError(c, http.StatusUnauthorized, err)
+		return
+	}
+
+	tag, err := h.service.Rename(c.Request.Context(), id, userID, req)
+	if err != nil {
+		i18n.RespondError(c, statusFor(err), err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tag)
+}
+
+// Delete handles DELETE /tags/:id - deletes a tag and detaches it from every task
+func (h *Handler) Delete(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, i18n.NewError("tag_id_invalid"))
+		return
+	}
+
+	userID, err := getUserIDFromClaims(c)
+	if err != nil {
+		i18n.RespondError(c, http.StatusUnauthorized, err)
+		return
+	}
+
+	if err := h.service.Delete(c.Request.Context(), id, userID); err != nil {
+		i18n.RespondError(c, statusFor(err), err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "tag deleted successfully"})
+}
+
+// Attach handles POST /tasks/:id/tags - attaches a tag to a task
+func (h *Handler) Attach(c *gin.Context) {
+	taskID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, i18n.NewError("task_id_invalid"))
+		return
+	}
+
+	var req AttachTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, i18n.NewError("request_invalid_detail", err))
+		return
+	}
+
+	userID, err := getUserIDFromClaims(c)
+	if err != nil {
+		i18n.RespondError(c, http.StatusUnauthorized, err)
+		return
+	}
+
+	task, err := h.service.Attach(c.Request.Context(), taskID, userID, req.TagID)
+	if err != nil {
+		i18n.RespondError(c, statusFor(err), err)
+		return
+	}
+
+	c.JSON(http.StatusOK, task)
+}
+
+// Detach handles DELETE /tasks/:id/tags/:tag_id - removes a tag from a task
+func (h *Handler) Detach(c *gin.Context) {
+	taskID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, i18n.NewError("task_id_invalid"))
+		return
+	}
+
+	tagID, err := strconv.ParseInt(c.Param("tag_id"), 10, 64)
+	if err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, i18n.NewError("tag_id_invalid"))
+		return
+	}
+
+	userID, err := getUserIDFromClaims(c)
+	if err != nil {
+		i18n.RespondError(c, http.StatusUnauthorized, err)
+		return
+	}
+
+	task, err := h.service.Detach(c.Request.Context(), taskID, userID, tagID)
+	if err != nil {
+		i18n.RespondError(c, statusFor(err), err)
+		return
+	}
+
+	c.JSON(http.StatusOK, task)
+}
+
+// statusFor maps service errors to HTTP status codes
+func statusFor(err error) int {
+	switch {
+	case errors.Is(err, ErrTagNotFound), errors.Is(err, tasks.ErrTaskNotFound), errors.Is(err, ErrNotAttached):
+		return http.StatusNotFound
+	case errors.Is(err, ErrTagExists):
+		return http.StatusConflict
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+// getUserIDFromClaims extracts the user ID from the JWT claims set by the auth middleware
+func getUserIDFromClaims(c *gin.Context) (int64, error) {
+	claims, exists := c.Get("claims")
+	if !exists {
+		return 0, i18n.NewError("user_not_authenticated")
+	}
+
+	claimsMap, ok := claims.(jwt.MapClaims)
+	if !ok {
+		return 0, errors.New("invalid claims")
+	}
+
+	switch v := claimsMap["uid"].(type) {
+	case float64:
+		return int64(v), nil
+	case int64:
+		return v, nil
+	case int:
+		return int64(v), nil
+	default:
+		return 0, i18n.NewError("user_id_missing")
+	}
+}
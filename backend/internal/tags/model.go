@@ -0,0 +1,33 @@
+package tags
+
+import "backend/root/internal/i18n"
+
+// Tag represents a user-owned label that can be attached to any number of tasks
+type Tag struct {
+	ID        int64  `json:"id" db:"id"`
+	UserID    int64  `json:"user_id" db:"user_id"`
+	Name      string `json:"name" db:"name"`
+	TaskCount int    `json:"task_count" db:"task_count"` // Number of tasks carrying the tag
+}
+
+// CreateTagRequest represents the request payload for creating a tag
+type CreateTagRequest struct {
+	Name string `json:"name" binding:"required,min=1,max=50"`
+}
+
+// UpdateTagRequest represents the request payload for renaming a tag
+type UpdateTagRequest struct {
+	Name string `json:"name" binding:"required,min=1,max=50"`
+}
+
+// AttachTagRequest represents the request payload for attaching a tag to a task
+type AttachTagRequest struct {
+	TagID int64 `json:"tag_id" binding:"required,min=1"`
+}
+
+// Errors returned when a tag cannot be found, created or detached
+var (
+	ErrTagNotFound = i18n.NewError("tag_not_found")
+	ErrTagExists   = i18n.NewError("tag_exists")
+	ErrNotAttached = i18n.NewError("tag_not_attached")
+)
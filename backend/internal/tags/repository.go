@@ -0,0 +1,173 @@
+package tags
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+// Repository defines the interface for tag persistence
+type Repository interface {
+	Create(ctx context.Context, userID int64, name string) (*Tag, error)
+	GetByID(ctx context.Context, id int64) (*Tag, error)
+	GetAllByUser(ctx context.Context, userID int64) ([]*Tag, error)
+	Rename(ctx context.Context, id int64, name string) (*Tag, error)
+	Delete(ctx context.Context, id int64) error
+	Attach(ctx context.Context, taskID int64, tagID int64) error
+	Detach(ctx context.Context, taskID int64, tagID int64) error
+}
+
+// tagSelect selects a tag with the number of tasks carrying it, in the order expected by scanTag
+const tagSelect = `
+		SELECT g.id, g.user_id, g.name,
+			(SELECT COUNT(*) FROM task_tags tt JOIN tasks t ON t.id = tt.task_id
+			 WHERE tt.tag_id = g.id AND t.deleted_at IS NULL)
+		FROM tags g`
+
+// uniqueViolation is the PostgreSQL error code raised by a duplicate key
+const uniqueViolation = "23505"
+
+// PostgresRepository implements Repository using PostgreSQL
+type PostgresRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresRepository creates a new PostgreSQL tags repository
+func NewPostgresRepository(db *sql.DB) *PostgresRepository {
+	return &PostgresRepository{db: db}
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanTag(row rowScanner) (*Tag, error) {
+	var tag Tag
+	if err := row.Scan(&tag.ID, &tag.UserID, &tag.Name, &tag.TaskCount); err != nil {
+		return nil, err
+	}
+
+	return &tag, nil
+}
+
+// isUniqueViolation reports whether err was caused by a unique constraint
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == uniqueViolation
+}
+
+// Create stores a new tag for the user
+func (r *PostgresRepository) Create(ctx context.Context, userID int64, name string) (*Tag, error) {
+	query := `INSERT INTO tags (user_id, name) VALUES ($1, $2) RETURNING id, user_id, name, 0`
+
+	tag, err := scanTag(r.db.QueryRowContext(ctx, query, userID, name))
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrTagExists
+		}
+		return nil, fmt.Errorf("failed to create tag: %w", err)
+	}
+
+	return tag, nil
+}
+
+// GetByID retrieves a tag by its ID
+func (r *PostgresRepository) GetByID(ctx context.Context, id int64) (*Tag, error) {
+	tag, err := scanTag(r.db.QueryRowContext(ctx, tagSelect+` WHERE g.id = $1`, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrTagNotFound
+		}
+		return nil, fmt.Errorf("failed to get tag: %w", err)
+	}
+
+	return tag, nil
+}
+
+// GetAllByUser retrieves every tag of a user ordered by name
+func (r *PostgresRepository) GetAllByUser(ctx context.Context, userID int64) ([]*Tag, error) {
+	rows, err := r.db.QueryContext(ctx, tagSelect+` WHERE g.user_id = $1 ORDER BY LOWER(g.name), g.id`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tags: %w", err)
+	}
+	defer rows.Close()
+
+	tags := []*Tag{}
+	for rows.Next() {
+		tag, err := scanTag(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan tag: %w", err)
+		}
+		tags = append(tags, tag)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating tags: %w", err)
+	}
+
+	return tags, nil
+}
+
+// Rename changes the name of a tag
+func (r *PostgresRepository) Rename(ctx context.Context, id int64, name string) (*Tag, error) {
+	if _, err := r.db.ExecContext(ctx, `UPDATE tags SET name = $2 WHERE id = $1`, id, name); err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrTagExists
+		}
+		return nil, fmt.Errorf("failed to rename tag: %w", err)
+	}
+
+	return r.GetByID(ctx, id)
+}
+
+// Delete removes a tag; it is detached from every task by the foreign key cascade
+func (r *PostgresRepository) Delete(ctx context.Context, id int64) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM tags WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete tag: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrTagNotFound
+	}
+
+	return nil
+}
+
+// Attach labels a task with a tag; attaching an already attached tag is a no-op
+func (r *PostgresRepository) Attach(ctx context.Context, taskID int64, tagID int64) error {
+	query := `INSERT INTO task_tags (task_id, tag_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`
+
+	if _, err := r.db.ExecContext(ctx, query, taskID, tagID); err != nil {
+		return fmt.Errorf("failed to attach tag: %w", err)
+	}
+
+	return nil
+}
+
+// Detach removes a tag from a task
+func (r *PostgresRepository) Detach(ctx context.Context, taskID int64, tagID int64) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM task_tags WHERE task_id = $1 AND tag_id = $2`, taskID, tagID)
+	if err != nil {
+		return fmt.Errorf("failed to detach tag: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrNotAttached
+	}
+
+	return nil
+}
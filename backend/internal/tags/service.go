@@ -0,0 +1,153 @@
+package tags
+
+import (
+	"context"
+	"strings"
+
+	"backend/root/internal/i18n"
+	"backend/root/internal/tasks"
+)
+
+// Service defines the interface for tag business logic
+type Service interface {
+	Create(ctx context.Context, userID int64, req CreateTagRequest) (*Tag, error)
+	GetAllByUser(ctx context.Context, userID int64) ([]*Tag, error)
+	Rename(ctx context.Context, id int64, userID int64, req UpdateTagRequest) (*Tag, error)
+	Delete(ctx context.Context, id int64, userID int64) error
+	Attach(ctx context.Context, taskID int64, userID int64, tagID int64) (*tasks.TaskResponse, error)
+	Detach(ctx context.Context, taskID int64, userID int64, tagID int64) (*tasks.TaskResponse, error)
+}
+
+// TaskRepository defines the minimal interface needed to validate task ownership
+type TaskRepository interface {
+	GetByID(ctx context.Context, id int64) (*tasks.Task, error)
+	GetByIDWithDetails(ctx context.Context, id int64) (*tasks.TaskResponse, error)
+}
+
+// service implements the Service interface
+type service struct {
+	repo     Repository
+	taskRepo TaskRepository
+}
+
+// NewService creates a new tag service
+func NewService(repo Repository, taskRepo TaskRepository) Service {
+	return &service{
+		repo:     repo,
+		taskRepo: taskRepo,
+	}
+}
+
+// Create creates a new tag for the user
+func (s *service) Create(ctx context.Context, userID int64, req CreateTagRequest) (*Tag, error) {
+	name, err := normalizeName(req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.Create(ctx, userID, name)
+}
+
+// GetAllByUser retrieves the user's tags
+func (s *service) GetAllByUser(ctx context.Context, userID int64) ([]*Tag, error) {
+	return s.repo.GetAllByUser(ctx, userID)
+}
+
+// Rename changes the name of one of the user's tags
+func (s *service) Rename(ctx context.Context, id int64, userID int64, req UpdateTagRequest) (*Tag, error) {
+	if _, err := s.getOwnedTag(ctx, id, userID); err != nil {
+		return nil, err
+	}
+
+	name, err := normalizeName(req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.Rename(ctx, id, name)
+}
+
+// Delete removes one of the user's tags from every task and deletes it
+func (s *service) Delete(ctx context.Context, id int64, userID int64) error {
+	if _, err := s.getOwnedTag(ctx, id, userID); err != nil {
+		return err
+	}
+
+	return s.repo.Delete(ctx, id)
+}
+
+// Attach labels one of the user's tasks with one of their tags
+func (s *service) Attach(ctx context.Context, taskID int64, userID int64, tagID int64) (*tasks.TaskResponse, error) {
+	if err := s.getOwnedTask(ctx, taskID, userID); err != nil {
+		return nil, err
+	}
+	if _, err := s.getOwnedTag(ctx, tagID, userID); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Attach(ctx, taskID, tagID); err != nil {
+		return nil, err
+	}
+
+	return s.taskRepo.GetByIDWithDetails(ctx, taskID)
+}
+
+// Detach removes a tag from one of the user's tasks
+func (s *service) Detach(ctx context.Context, taskID int64, userID int64, tagID int64) (*tasks.TaskResponse, error) {
+	if err := s.getOwnedTask(ctx, taskID, userID); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Detach(ctx, taskID, tagID); err != nil {
+		return nil, err
+	}
+
+	return s.taskRepo.GetByIDWithDetails(ctx, taskID)
+}
+
+// getOwnedTag loads a tag and hides it from anyone but its owner
+func (s *service) getOwnedTag(ctx context.Context, id int64, userID int64) (*Tag, error) {
+	if id <= 0 {
+		return nil, i18n.NewError("tag_id_invalid")
+	}
+
+	tag, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if tag.UserID != userID {
+		return nil, ErrTagNotFound
+	}
+
+	return tag, nil
+}
+
+// getOwnedTask checks that a task exists and belongs to the user
+func (s *service) getOwnedTask(ctx context.Context, taskID int64, userID int64) error {
+	if taskID <= 0 {
+		return i18n.NewError("task_id_invalid")
+	}
+
+	task, err := s.taskRepo.GetByID(ctx, taskID)
+	if err != nil {
+		return err
+	}
+	if task == nil || task.UserID != userID {
+		return tasks.ErrTaskNotFound
+	}
+
+	return nil
+}
+
+// normalizeName trims a tag name and validates its length
+func normalizeName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", i18n.NewError("tag_name_empty")
+	}
+	if len(name) > 50 {
+		return "", i18n.NewError("tag_name_too_long")
+	}
+
+	return name, nil
+}
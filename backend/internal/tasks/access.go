@@ -0,0 +1,100 @@
+package tasks
+
+import (
+	"context"
+
+	"backend/root/internal/i18n"
+)
+
+// Roles a user can hold on a task: its owner, or someone the task (or its category) was shared with.
+// The assignee of a task holds at least the editor role.
+const (
+	RoleViewer = "viewer" // Read the task, its subtasks, history and comments
+	RoleEditor = "editor" // Also change its fields, state and blockers
+	RoleOwner  = "owner"  // Also delete, restore and share it
+)
+
+// roleRank orders the roles so that a stronger role satisfies a weaker requirement
+var roleRank = map[string]int{
+	RoleViewer: 1,
+	RoleEditor: 2,
+	RoleOwner:  3,
+}
+
+// IsValidShareRole checks if a role can be granted by sharing
+func IsValidShareRole(role string) bool {
+	return role == RoleViewer || role == RoleEditor
+}
+
+// HasRole reports whether granted satisfies the required role
+func HasRole(granted string, required string) bool {
+	return roleRank[granted] >= roleRank[required]
+}
+
+// ErrInvalidTaskID is returned for task IDs that cannot exist
+var ErrInvalidTaskID = i18n.NewError("task_id_invalid")
+
+// ErrTaskNotFound is returned when a task does not exist, is deleted or is not visible to the user
+var ErrTaskNotFound = i18n.NewError("task_not_found")
+
+// ErrPermissionDenied is returned when a user can see a task but their role does not allow the operation
+var ErrPermissionDenied = i18n.NewError("task_permission_denied")
+
+// authorize loads a live task and checks that the user holds at least the required role on it,
+// returning the role actually granted. Tasks the user has no access to are reported as not found.
+func (s *service) authorize(ctx context.Context, taskID int64, userID int64, required string) (*Task, string, error) {
+	if taskID <= 0 {
+		return nil, "", ErrInvalidTaskID
+	}
+
+	task, err := s.repo.GetByID(ctx, taskID)
+	if err != nil {
+		return nil, "", err
+	}
+	if task == nil {
+		return nil, "", ErrTaskNotFound
+	}
+
+	granted := RoleOwner
+	if task.UserID != userID {
+		granted, err = s.repo.GetRole(ctx, taskID, userID)
+		if err != nil {
+			return nil, "", err
+		}
+		if granted == "" {
+			return nil, "", ErrTaskNotFound
+		}
+	}
+
+	if !HasRole(granted, required) {
+		return nil, "", ErrPermissionDenied
+	}
+
+	return task, granted, nil
+}
+
+// GetShared retrieves the live tasks other users shared with the user, directly or through
+// a category, each with the role the user holds on it
+func (s *service) GetShared(ctx context.Context, userID int64) ([]*TaskResponse, error) {
+	tasks, err := s.repo.GetShared(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(tasks) == 0 {
+		return []*TaskResponse{}, nil
+	}
+
+	ids := make([]int64, len(tasks))
+	for i, task := range tasks {
+		ids[i] = task.ID
+	}
+	roles, err := s.repo.GetRoles(ctx, ids, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, task := range tasks {
+		task.Role = roles[task.ID]
+	}
+
+	return tasks, nil
+}
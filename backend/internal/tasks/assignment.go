@@ -0,0 +1,148 @@
+package tasks
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	"backend/root/internal/i18n"
+	"backend/root/internal/users"
+	"backend/root/internal/workspaces"
+)
+
+// UserRepository defines the minimal interface needed to resolve assignees by username
+type UserRepository interface {
+	FindByUsername(ctx context.Context, username string) (*users.User, error)
+}
+
+// Errors returned when the assignee of a task cannot be resolved
+var (
+	ErrAssigneeEmpty     = i18n.NewError("task_assignee_empty")
+	ErrAssigneeNotFound  = i18n.NewError("user_not_found")
+	ErrAssigneeNotMember = i18n.NewError("workspace_assignee_not_member")
+)
+
+// AssignmentEvent describes a change of the user responsible for a task
+type AssignmentEvent struct {
+	TaskID             int64
+	TaskText           string
+	OwnerID            int64
+	AssigneeID         *int64 // nil when the task was unassigned
+	PreviousAssigneeID *int64 // nil when the task was unassigned before
+	ActorID            int64  // The user who made the change
+}
+
+// AssignmentHook is notified after a task assignment has been committed
+type AssignmentHook interface {
+	TaskAssigned(ctx context.Context, event AssignmentEvent) error
+}
+
+// Assign makes a user responsible for a task. The assignee can edit the task even if it was
+// not shared with them; assigning the current assignee again changes nothing.
+func (s *service) Assign(ctx context.Context, taskID int64, userID int64, req AssignRequest) (*TaskResponse, error) {
+	task, _, err := s.authorize(ctx, taskID, userID, RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	assignee, err := s.resolveAssignee(ctx, req.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.setAssignee(ctx, userID, task, &assignee)
+}
+
+// Unassign removes the user responsible for a task
+func (s *service) Unassign(ctx context.Context, taskID int64, userID int64) (*TaskResponse, error) {
+	task, _, err := s.authorize(ctx, taskID, userID, RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.setAssignee(ctx, userID, task, nil)
+}
+
+// resolveAssignee looks up the ID of the user a task is being assigned to. In a workspace
+// only its members can be assigned.
+func (s *service) resolveAssignee(ctx context.Context, username string) (int64, error) {
+	username = strings.TrimSpace(username)
+	if username == "" {
+		return 0, ErrAssigneeEmpty
+	}
+
+	user, err := s.userRepo.FindByUsername(ctx, username)
+	if err != nil {
+		return 0, ErrAssigneeNotFound
+	}
+
+	if workspaceID := workspaces.IDFromContext(ctx); workspaceID != nil {
+		member, err := s.repo.IsWorkspaceMember(ctx, *workspaceID, user.ID)
+		if err != nil {
+			return 0, err
+		}
+		if !member {
+			return 0, ErrAssigneeNotMember
+		}
+	}
+
+	return user.ID, nil
+}
+
+// setAssignee saves a new assignee, recording it in the history, and runs the assignment hooks
+func (s *service) setAssignee(ctx context.Context, actorID int64, task *Task, assigneeID *int64) (*TaskResponse, error) {
+	if sameID(task.AssigneeID, assigneeID) {
+		return s.repo.GetByIDWithDetails(ctx, task.ID)
+	}
+
+	updated := *task
+	updated.AssigneeID = assigneeID
+	resp, err := s.save(ctx, actorID, task, &updated)
+	if err != nil {
+		return nil, err
+	}
+
+	s.notifyAssignment(ctx, AssignmentEvent{
+		TaskID:             task.ID,
+		TaskText:           task.TaskText,
+		OwnerID:            task.UserID,
+		AssigneeID:         assigneeID,
+		PreviousAssigneeID: task.AssigneeID,
+		ActorID:            actorID,
+	})
+
+	return resp, nil
+}
+
+// notifyNewAssignee runs the assignment hooks for a task created with an assignee
+func (s *service) notifyNewAssignee(ctx context.Context, actorID int64, task *Task) {
+	if task.AssigneeID == nil {
+		return
+	}
+
+	s.notifyAssignment(ctx, AssignmentEvent{
+		TaskID:     task.ID,
+		TaskText:   task.TaskText,
+		OwnerID:    task.UserID,
+		AssigneeID: task.AssigneeID,
+		ActorID:    actorID,
+	})
+}
+
+// notifyAssignment runs every assignment hook. The assignment is already committed,
+// so a failing hook is logged rather than reported to the client.
+func (s *service) notifyAssignment(ctx context.Context, event AssignmentEvent) {
+	for _, hook := range s.opts.AssignmentHooks {
+		if err := hook.TaskAssigned(ctx, event); err != nil {
+			log.Printf("assignment hook failed for task %d: %v", event.TaskID, err)
+		}
+	}
+}
+
+// sameID reports whether two optional IDs are equal
+func sameID(a *int64, b *int64) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
@@ -0,0 +1,206 @@
+package tasks
+
+import (
+	"context"
+	"errors"
+
+	"backend/root/internal/i18n"
+)
+
+// GetBoard groups the tasks of the current space into one column per workflow state, each in
+// board order. Every built-in and own state gets a column, even when empty.
+func (s *service) GetBoard(ctx context.Context, userID int64, filter TaskFilter) (*Board, error) {
+	filter.Sort = SortPosition
+	tasks, err := s.GetAllByUser(ctx, userID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// Workspace tasks may be in states defined by other members
+	stateIDs := make([]int64, 0, len(tasks))
+	for _, task := range tasks {
+		if task.State != nil {
+			stateIDs = append(stateIDs, task.State.ID)
+		}
+	}
+	states, err := s.repo.GetBoardStates(ctx, userID, stateIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	board := &Board{Columns: []*BoardColumn{}}
+	columns := make(map[int64]*BoardColumn, len(states))
+	for _, state := range states {
+		if filter.StateID != nil && state.ID != *filter.StateID {
+			continue
+		}
+		column := &BoardColumn{State: state, Tasks: []*TaskResponse{}}
+		columns[state.ID] = column
+		board.Columns = append(board.Columns, column)
+	}
+
+	// Tasks whose state was deleted sit in Not Started
+	for _, task := range tasks {
+		stateID := StateNotStarted
+		if task.State != nil {
+			stateID = task.State.ID
+		}
+		if column, ok := columns[stateID]; ok {
+			column.Tasks = append(column.Tasks, task)
+			column.Count++
+		}
+	}
+
+	return board, nil
+}
+
+// Move places a card between two neighbors of a board column, optionally changing its state
+// under the same rules as an update. Only the moved task is written. When IfMatch is set the
+// task must still be at that version.
+func (s *service) Move(ctx context.Context, taskID int64, userID int64, req MoveTaskRequest) (*TaskResponse, error) {
+	existingTask, _, err := s.authorize(ctx, taskID, userID, RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.IfMatch != nil && *req.IfMatch != existingTask.Version {
+		return nil, ErrVersionMismatch
+	}
+
+	stateID := currentState(existingTask.StateID)
+	if req.StateID != nil {
+		if err := s.checkStateChange(ctx, existingTask, *req.StateID); err != nil {
+			return nil, err
+		}
+		stateID = *req.StateID
+	}
+
+	if !req.IgnoreBlockers {
+		if err := s.checkBlockers(ctx, existingTask, req.StateID); err != nil {
+			return nil, err
+		}
+	}
+
+	var resp *TaskResponse
+	err = s.withTx(ctx, func(tx *service) error {
+		position, err := tx.placeBetween(ctx, existingTask, userID, columnOf(existingTask, stateID), req.AfterID, req.BeforeID)
+		if err != nil {
+			return err
+		}
+
+		updatedTask := *existingTask
+		if req.StateID != nil {
+			updatedTask.StateID = req.StateID
+		}
+		updatedTask.Position = &position
+		resp, err = tx.save(ctx, userID, existingTask, &updatedTask)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// placeBetween computes the position of a task moved between two cards of a column; without
+// neighbors it goes to the bottom. Neighbors that have no position yet or share one are fixed
+// by rebalancing the column once. Any other card between the neighbors means the client's
+// board is stale.
+func (s *service) placeBetween(ctx context.Context, task *Task, userID int64, column Column, afterID *int64, beforeID *int64) (string, error) {
+	if afterID == nil && beforeID == nil {
+		last, err := s.repo.LastPosition(ctx, column)
+		if err != nil {
+			return "", err
+		}
+		return positionBetween(last, "")
+	}
+
+	for attempt := 0; ; attempt++ {
+		lower, lowerOK, err := s.neighborPosition(ctx, task, userID, column, afterID)
+		if err != nil {
+			return "", err
+		}
+		upper, upperOK, err := s.neighborPosition(ctx, task, userID, column, beforeID)
+		if err != nil {
+			return "", err
+		}
+
+		position, err := positionBetween(lower, upper)
+		if err == nil && lowerOK && upperOK {
+			between, err := s.repo.HasTasksBetween(ctx, column, lower, upper, task.ID)
+			if err != nil {
+				return "", err
+			}
+			if between {
+				return "", ErrBoardChanged
+			}
+			return position, nil
+		}
+
+		if attempt > 0 {
+			return "", ErrBoardChanged
+		}
+		if err := s.repo.RebalanceColumn(ctx, column); err != nil {
+			return "", err
+		}
+	}
+}
+
+// neighborPosition returns the position of a neighbor of a moved card, "" for the edge of the column.
+// The second result is false when the neighbor has no position yet.
+func (s *service) neighborPosition(ctx context.Context, task *Task, userID int64, column Column, neighborID *int64) (string, bool, error) {
+	if neighborID == nil {
+		return "", true, nil
+	}
+	if *neighborID == task.ID {
+		return "", false, i18n.NewError("task_move_self")
+	}
+
+	neighbor, _, err := s.authorize(ctx, *neighborID, userID, RoleViewer)
+	if err != nil {
+		if errors.Is(err, ErrTaskNotFound) {
+			return "", false, i18n.NewError("task_neighbor_not_found")
+		}
+		return "", false, err
+	}
+	if !sameColumn(columnOf(neighbor, currentState(neighbor.StateID)), column) {
+		return "", false, i18n.NewError("task_neighbor_column")
+	}
+
+	if neighbor.Position == nil {
+		return "", false, nil
+	}
+	return *neighbor.Position, true, nil
+}
+
+// placeLast moves a task to the bottom of the column of its current state
+func (s *service) placeLast(ctx context.Context, task *Task) error {
+	last, err := s.repo.LastPosition(ctx, columnOf(task, currentState(task.StateID)))
+	if err != nil {
+		return err
+	}
+
+	position, err := positionBetween(last, "")
+	if err != nil {
+		return err
+	}
+	task.Position = &position
+	return nil
+}
+
+// sameColumn reports whether two columns hold the same cards
+func sameColumn(a Column, b Column) bool {
+	if a.StateID != b.StateID || !sameID(a.WorkspaceID, b.WorkspaceID) {
+		return false
+	}
+	return a.WorkspaceID != nil || a.UserID == b.UserID
+}
+
+// samePosition reports whether two optional positions are equal
+func samePosition(a *string, b *string) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
@@ -0,0 +1,213 @@
+package tasks
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+
+	"backend/root/internal/i18n"
+)
+
+// MaxImportRows caps the number of data rows accepted in a single CSV import
+const MaxImportRows = 1000
+
+// MaxImportBytes caps the size of an imported CSV file
+const MaxImportBytes = 2 << 20
+
+// Importable CSV fields. Exported files use the same names, so they can be imported back.
+const (
+	FieldTaskText   = "task_text"
+	FieldEndDate    = "end_date"
+	FieldCategory   = "category"
+	FieldPriority   = "priority"
+	FieldImportant  = "important"
+	FieldAssignee   = "assignee"
+	FieldRecurrence = "recurrence"
+)
+
+// importFields lists the fields a CSV column can be mapped to
+var importFields = []string{
+	FieldTaskText, FieldEndDate, FieldCategory, FieldPriority, FieldImportant, FieldAssignee, FieldRecurrence,
+}
+
+// exportHeader lists the columns of an exported CSV file
+var exportHeader = []string{
+	"id", FieldTaskText, "state", FieldCategory, FieldEndDate, FieldPriority, FieldImportant,
+	FieldAssignee, FieldRecurrence, "tags", "parent_id", "creation_date", "completed_at",
+}
+
+// parseImportCSV reads the rows of a CSV file whose first line is a header. Columns are matched
+// to fields by name, ignoring case; mapping overrides that with field -> header pairs.
+// Columns that are not mapped are ignored.
+func parseImportCSV(r io.Reader, mapping map[string]string) ([]ImportRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, i18n.NewError("task_import_empty")
+	}
+	if err != nil {
+		return nil, i18n.NewError("task_import_invalid", err)
+	}
+	if len(header) > 0 {
+		header[0] = strings.TrimPrefix(header[0], "\ufeff") // Byte order mark written by spreadsheets
+	}
+
+	columns, err := mapColumns(header, mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []ImportRow
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, i18n.NewError("task_import_invalid", err)
+		}
+		if len(rows) == MaxImportRows {
+			return nil, i18n.NewError("task_import_too_many")
+		}
+
+		line, _ := reader.FieldPos(0)
+		cell := func(field string) string {
+			index, ok := columns[field]
+			if !ok || index >= len(record) {
+				return ""
+			}
+			return unescapeCell(strings.TrimSpace(record[index]))
+		}
+		rows = append(rows, ImportRow{
+			Line:       line,
+			TaskText:   cell(FieldTaskText),
+			EndDate:    cell(FieldEndDate),
+			Category:   cell(FieldCategory),
+			Priority:   cell(FieldPriority),
+			Important:  cell(FieldImportant),
+			Assignee:   cell(FieldAssignee),
+			Recurrence: cell(FieldRecurrence),
+		})
+	}
+
+	if len(rows) == 0 {
+		return nil, i18n.NewError("task_import_no_rows")
+	}
+
+	return rows, nil
+}
+
+// mapColumns returns the index of the column holding each mapped field
+func mapColumns(header []string, mapping map[string]string) (map[string]int, error) {
+	indexOf := func(name string) int {
+		for i, column := range header {
+			if strings.EqualFold(strings.TrimSpace(column), strings.TrimSpace(name)) {
+				return i
+			}
+		}
+		return -1
+	}
+
+	columns := make(map[string]int, len(importFields))
+	for _, field := range importFields {
+		if index := indexOf(field); index >= 0 {
+			columns[field] = index
+		}
+	}
+
+	for field, name := range mapping {
+		if !isImportField(field) {
+			return nil, i18n.NewError("task_import_unknown_field", field)
+		}
+		index := indexOf(name)
+		if index < 0 {
+			return nil, i18n.NewError("task_import_column_missing", name)
+		}
+		columns[field] = index
+	}
+
+	if _, ok := columns[FieldTaskText]; !ok {
+		return nil, i18n.NewError("task_import_no_text")
+	}
+
+	return columns, nil
+}
+
+// isImportField reports whether a CSV column can be mapped to field
+func isImportField(field string) bool {
+	for _, f := range importFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// writeTasksCSV writes tasks as a CSV file with a header line
+func writeTasksCSV(w io.Writer, tasks []*TaskResponse) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(exportHeader); err != nil {
+		return err
+	}
+
+	for _, task := range tasks {
+		var state, category, endDate, assignee, recurrence, parentID, completedAt string
+		if task.State != nil {
+			state = task.State.Description
+		}
+		if task.Category != nil {
+			category = task.Category.Name
+		}
+		if task.EndDate != nil {
+			endDate = task.EndDate.Format("2006-01-02")
+		}
+		if task.Assignee != nil {
+			assignee = task.Assignee.Username
+		}
+		if task.Recurrence != nil {
+			recurrence = *task.Recurrence
+		}
+		if task.ParentID != nil {
+			parentID = strconv.FormatInt(*task.ParentID, 10)
+		}
+		if task.CompletedAt != nil {
+			completedAt = task.CompletedAt.UTC().Format("2006-01-02T15:04:05Z")
+		}
+		tags := make([]string, 0, len(task.Tags))
+		for _, tag := range task.Tags {
+			tags = append(tags, tag.Name)
+		}
+
+		record := []string{
+			strconv.FormatInt(task.ID, 10), escapeCell(task.TaskText), escapeCell(state), escapeCell(category), endDate,
+			strconv.Itoa(task.Priority), strconv.FormatBool(task.Important), escapeCell(assignee), recurrence,
+			escapeCell(strings.Join(tags, ", ")), parentID, task.CreationDate.Format("2006-01-02"), completedAt,
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// escapeCell keeps spreadsheets from evaluating text starting like a formula by prefixing it with a quote
+func escapeCell(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+// unescapeCell removes the quote escapeCell adds, so exported files import unchanged
+func unescapeCell(value string) string {
+	if len(value) > 1 && value[0] == '\'' && strings.ContainsRune("=+-@\t\r", rune(value[1])) {
+		return value[1:]
+	}
+	return value
+}
@@ -1,12 +1,22 @@
 package tasks
 
 import (
+	"encoding/json"
 	"errors"
+	"io"
+	"log"
 	"net/http"
+	"path/filepath"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
+
+	"backend/root/internal/etag"
+	"backend/root/internal/i18n"
+	"backend/root/internal/workspaces"
 )
 
 // Handler handles HTTP requests for task operations
@@ -25,62 +35,76 @@ func NewHandler(service Service) *Handler {
 func (h *Handler) Create(c *gin.Context) {
 	var req CreateTaskRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request: " + err.Error()})
+		i18n.RespondError(c, http.StatusBadRequest, i18n.NewError("request_invalid_detail", err))
 		return
 	}
 
 	userID, err := h.getUserIDFromClaims(c)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		i18n.RespondError(c, http.StatusUnauthorized, err)
 		return
 	}
 
 	// Create the task
 	task, err := h.service.Create(c.Request.Context(), userID, req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		if errors.Is(err, workspaces.ErrReadOnly) {
+			i18n.RespondError(c, http.StatusForbidden, err)
+			return
+		}
+		i18n.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 
 	c.JSON(http.StatusCreated, task)
 }
 
-// GetAll handles GET /tasks - retrieves all tasks for the authenticated user with optional filtering
-func (h *Handler) GetAll(c *gin.Context) {
+// QuickAdd handles POST /tasks/quick - creates a task from a line such as "Pay rent tomorrow #Home !high"
+func (h *Handler) QuickAdd(c *gin.Context) {
+	var req QuickAddRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, i18n.NewError("request_invalid_detail", err))
+		return
+	}
+
 	userID, err := h.getUserIDFromClaims(c)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		i18n.RespondError(c, http.StatusUnauthorized, err)
 		return
 	}
 
-	// Parse optional query parameters for filtering
-	var categoryID *int64
-	var stateID *int64
-
-	// Parse category_id query parameter
-	if categoryIDStr := c.Query("category_id"); categoryIDStr != "" {
-		catID, err := strconv.ParseInt(categoryIDStr, 10, 64)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid category_id parameter"})
+	result, err := h.service.QuickAdd(c.Request.Context(), userID, req)
+	if err != nil {
+		if errors.Is(err, workspaces.ErrReadOnly) {
+			i18n.RespondError(c, http.StatusForbidden, err)
 			return
 		}
-		categoryID = &catID
+		i18n.RespondError(c, http.StatusBadRequest, err)
+		return
 	}
 
-	// Parse state_id query parameter
-	if stateIDStr := c.Query("state_id"); stateIDStr != "" {
-		stID, err := strconv.ParseInt(stateIDStr, 10, 64)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid state_id parameter"})
-			return
-		}
-		stateID = &stID
+	c.JSON(http.StatusCreated, result)
+}
+
+// GetAll handles GET /tasks - retrieves all tasks for the authenticated user with optional filtering
+func (h *Handler) GetAll(c *gin.Context) {
+	userID, err := h.getUserIDFromClaims(c)
+	if err != nil {
+		i18n.RespondError(c, http.StatusUnauthorized, err)
+		return
+	}
+
+	// Parse optional query parameters for filtering and sorting
+	filter, err := parseTaskFilter(c)
+	if err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, err)
+		return
 	}
 
 	// Get tasks with optional filtering
-	tasks, err := h.service.GetAllByUser(c.Request.Context(), userID, categoryID, stateID)
+	tasks, err := h.service.GetAllByUser(c.Request.Context(), userID, filter)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		i18n.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -94,82 +118,871 @@ func (h *Handler) GetAll(c *gin.Context) {
 func (h *Handler) GetByID(c *gin.Context) {
 	taskID, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid task ID"})
+		i18n.RespondError(c, http.StatusBadRequest, ErrInvalidTaskID)
 		return
 	}
 
 	userID, err := h.getUserIDFromClaims(c)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		i18n.RespondError(c, http.StatusUnauthorized, err)
 		return
 	}
 
 	task, err := h.service.GetByID(c.Request.Context(), taskID, userID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		i18n.RespondError(c, http.StatusNotFound, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, task)
+	// Let clients revalidate their cached copy with If-None-Match
+	writeTask(c, task, true)
 }
 
 // Update handles PUT /tasks/:id - updates an existing task
 func (h *Handler) Update(c *gin.Context) {
 	taskID, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid task ID"})
+		i18n.RespondError(c, http.StatusBadRequest, ErrInvalidTaskID)
 		return
 	}
 
 	var req UpdateTaskRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request: " + err.Error()})
+		i18n.RespondError(c, http.StatusBadRequest, i18n.NewError("request_invalid_detail", err))
+		return
+	}
+
+	req.IfMatch, err = etag.ParseIfMatch(c.GetHeader("If-Match"))
+	if err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 
 	userID, err := h.getUserIDFromClaims(c)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		i18n.RespondError(c, http.StatusUnauthorized, err)
 		return
 	}
 
 	task, err := h.service.Update(c.Request.Context(), taskID, userID, req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		if errors.Is(err, ErrVersionMismatch) {
+			i18n.RespondError(c, http.StatusPreconditionFailed, err)
+			return
+		}
+		if errors.Is(err, ErrPermissionDenied) {
+			i18n.RespondError(c, http.StatusForbidden, err)
+			return
+		}
+		if IsTransitionError(err) {
+			i18n.RespondError(c, http.StatusConflict, err)
+			return
+		}
+		i18n.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, task)
+	writeTask(c, task, false)
+}
+
+// Transition handles POST /tasks/:id/transition - moves a task along the state graph
+func (h *Handler) Transition(c *gin.Context) {
+	taskID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, ErrInvalidTaskID)
+		return
+	}
+
+	var req TransitionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, i18n.NewError("request_invalid_detail", err))
+		return
+	}
+
+	req.IfMatch, err = etag.ParseIfMatch(c.GetHeader("If-Match"))
+	if err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	userID, err := h.getUserIDFromClaims(c)
+	if err != nil {
+		i18n.RespondError(c, http.StatusUnauthorized, err)
+		return
+	}
+
+	task, err := h.service.Transition(c.Request.Context(), taskID, userID, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrVersionMismatch):
+			i18n.RespondError(c, http.StatusPreconditionFailed, err)
+		case IsTransitionError(err):
+			i18n.RespondError(c, http.StatusConflict, err)
+		case errors.Is(err, ErrPermissionDenied):
+			i18n.RespondError(c, http.StatusForbidden, err)
+		case errors.Is(err, ErrTaskNotFound):
+			i18n.RespondError(c, http.StatusNotFound, err)
+		default:
+			i18n.RespondError(c, http.StatusBadRequest, err)
+		}
+		return
+	}
+
+	writeTask(c, task, false)
+}
+
+// Move handles POST /tasks/:id/move - places a card between two neighbors of a board column
+func (h *Handler) Move(c *gin.Context) {
+	taskID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, ErrInvalidTaskID)
+		return
+	}
+
+	var req MoveTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, i18n.NewError("request_invalid_detail", err))
+		return
+	}
+
+	req.IfMatch, err = etag.ParseIfMatch(c.GetHeader("If-Match"))
+	if err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	userID, err := h.getUserIDFromClaims(c)
+	if err != nil {
+		i18n.RespondError(c, http.StatusUnauthorized, err)
+		return
+	}
+
+	task, err := h.service.Move(c.Request.Context(), taskID, userID, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrVersionMismatch):
+			i18n.RespondError(c, http.StatusPreconditionFailed, err)
+		case errors.Is(err, ErrBoardChanged), IsTransitionError(err):
+			i18n.RespondError(c, http.StatusConflict, err)
+		case errors.Is(err, ErrPermissionDenied):
+			i18n.RespondError(c, http.StatusForbidden, err)
+		case errors.Is(err, ErrTaskNotFound):
+			i18n.RespondError(c, http.StatusNotFound, err)
+		default:
+			i18n.RespondError(c, http.StatusBadRequest, err)
+		}
+		return
+	}
+
+	writeTask(c, task, false)
+}
+
+// GetBoard handles GET /board - groups the tasks of the current space into state columns in board order
+func (h *Handler) GetBoard(c *gin.Context) {
+	userID, err := h.getUserIDFromClaims(c)
+	if err != nil {
+		i18n.RespondError(c, http.StatusUnauthorized, err)
+		return
+	}
+
+	// The list filters apply to the cards; the order is always the board's
+	filter, err := parseTaskFilter(c)
+	if err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	board, err := h.service.GetBoard(c.Request.Context(), userID, filter)
+	if err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, board)
 }
 
 // Delete handles DELETE /tasks/:id - deletes a task
 func (h *Handler) Delete(c *gin.Context) {
 	taskID, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid task ID"})
+		i18n.RespondError(c, http.StatusBadRequest, ErrInvalidTaskID)
+		return
+	}
+
+	ifMatch, err := etag.ParseIfMatch(c.GetHeader("If-Match"))
+	if err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, err)
 		return
 	}
 
 	userID, err := h.getUserIDFromClaims(c)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		i18n.RespondError(c, http.StatusUnauthorized, err)
 		return
 	}
 
-	err = h.service.Delete(c.Request.Context(), taskID, userID)
+	err = h.service.Delete(c.Request.Context(), taskID, userID, ifMatch)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		if errors.Is(err, ErrVersionMismatch) {
+			i18n.RespondError(c, http.StatusPreconditionFailed, err)
+			return
+		}
+		if errors.Is(err, ErrPermissionDenied) {
+			i18n.RespondError(c, http.StatusForbidden, err)
+			return
+		}
+		i18n.RespondError(c, http.StatusNotFound, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "task deleted successfully"})
 }
 
+// GetChildren handles GET /tasks/:id/children - lists the direct subtasks of a task
+func (h *Handler) GetChildren(c *gin.Context) {
+	taskID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, ErrInvalidTaskID)
+		return
+	}
+
+	userID, err := h.getUserIDFromClaims(c)
+	if err != nil {
+		i18n.RespondError(c, http.StatusUnauthorized, err)
+		return
+	}
+
+	children, err := h.service.GetChildren(c.Request.Context(), taskID, userID)
+	if err != nil {
+		i18n.RespondError(c, http.StatusNotFound, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"tasks": children,
+		"count": len(children),
+	})
+}
+
+// GetTree handles GET /tasks/:id/tree - retrieves a task with all of its subtasks nested
+func (h *Handler) GetTree(c *gin.Context) {
+	taskID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, ErrInvalidTaskID)
+		return
+	}
+
+	userID, err := h.getUserIDFromClaims(c)
+	if err != nil {
+		i18n.RespondError(c, http.StatusUnauthorized, err)
+		return
+	}
+
+	tree, err := h.service.GetTree(c.Request.Context(), taskID, userID)
+	if err != nil {
+		i18n.RespondError(c, http.StatusNotFound, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tree)
+}
+
+// AddBlocker handles POST /tasks/:id/blockers - makes the task depend on another task
+func (h *Handler) AddBlocker(c *gin.Context) {
+	taskID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, ErrInvalidTaskID)
+		return
+	}
+
+	var req AddBlockerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, i18n.NewError("request_invalid_detail", err))
+		return
+	}
+
+	userID, err := h.getUserIDFromClaims(c)
+	if err != nil {
+		i18n.RespondError(c, http.StatusUnauthorized, err)
+		return
+	}
+
+	task, err := h.service.AddBlocker(c.Request.Context(), taskID, userID, req.BlockerID)
+	if err != nil {
+		if errors.Is(err, ErrPermissionDenied) {
+			i18n.RespondError(c, http.StatusForbidden, err)
+			return
+		}
+		i18n.RespondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, task)
+}
+
+// RemoveBlocker handles DELETE /tasks/:id/blockers/:blocker_id - removes a dependency
+func (h *Handler) RemoveBlocker(c *gin.Context) {
+	taskID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, ErrInvalidTaskID)
+		return
+	}
+
+	blockerID, err := strconv.ParseInt(c.Param("blocker_id"), 10, 64)
+	if err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, i18n.NewError("blocker_id_invalid"))
+		return
+	}
+
+	userID, err := h.getUserIDFromClaims(c)
+	if err != nil {
+		i18n.RespondError(c, http.StatusUnauthorized, err)
+		return
+	}
+
+	task, err := h.service.RemoveBlocker(c.Request.Context(), taskID, userID, blockerID)
+	if err != nil {
+		if errors.Is(err, ErrPermissionDenied) {
+			i18n.RespondError(c, http.StatusForbidden, err)
+			return
+		}
+		i18n.RespondError(c, http.StatusNotFound, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, task)
+}
+
+// Assign handles POST /tasks/:id/assign - makes a user responsible for the task
+func (h *Handler) Assign(c *gin.Context) {
+	taskID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, ErrInvalidTaskID)
+		return
+	}
+
+	var req AssignRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, i18n.NewError("request_invalid_detail", err))
+		return
+	}
+
+	userID, err := h.getUserIDFromClaims(c)
+	if err != nil {
+		i18n.RespondError(c, http.StatusUnauthorized, err)
+		return
+	}
+
+	task, err := h.service.Assign(c.Request.Context(), taskID, userID, req)
+	if err != nil {
+		i18n.RespondError(c, assignmentStatus(err), err)
+		return
+	}
+
+	c.JSON(http.StatusOK, task)
+}
+
+// Unassign handles POST /tasks/:id/unassign - removes the user responsible for the task
+func (h *Handler) Unassign(c *gin.Context) {
+	taskID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, ErrInvalidTaskID)
+		return
+	}
+
+	userID, err := h.getUserIDFromClaims(c)
+	if err != nil {
+		i18n.RespondError(c, http.StatusUnauthorized, err)
+		return
+	}
+
+	task, err := h.service.Unassign(c.Request.Context(), taskID, userID)
+	if err != nil {
+		i18n.RespondError(c, assignmentStatus(err), err)
+		return
+	}
+
+	c.JSON(http.StatusOK, task)
+}
+
+// writeTask responds with a task and its ETag. Tags, comments, progress, blockers and the
+// localized state name change the body without bumping the version, so the ETag adds a hash of
+// the body to it; revalidate answers 304 when If-None-Match lists that ETag.
+func writeTask(c *gin.Context, task *TaskResponse, revalidate bool) {
+	body, err := json.Marshal(task)
+	if err != nil {
+		i18n.RespondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	tag := etag.FormatContent(task.Version, body)
+	c.Header("ETag", tag)
+	c.Header("Vary", "Accept-Language")
+	if revalidate && etag.Matches(c.GetHeader("If-None-Match"), tag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	c.Data(http.StatusOK, "application/json; charset=utf-8", body)
+}
+
+// assignmentStatus maps assignment errors to HTTP status codes
+func assignmentStatus(err error) int {
+	switch {
+	case errors.Is(err, ErrPermissionDenied):
+		return http.StatusForbidden
+	case errors.Is(err, ErrTaskNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrAssigneeNotFound), errors.Is(err, ErrAssigneeEmpty), errors.Is(err, ErrInvalidTaskID),
+		errors.Is(err, ErrAssigneeNotMember):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// GetSeries handles GET /tasks/series/:series_id - lists every occurrence of a recurring series
+func (h *Handler) GetSeries(c *gin.Context) {
+	seriesID, err := strconv.ParseInt(c.Param("series_id"), 10, 64)
+	if err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, i18n.NewError("series_id_invalid"))
+		return
+	}
+
+	userID, err := h.getUserIDFromClaims(c)
+	if err != nil {
+		i18n.RespondError(c, http.StatusUnauthorized, err)
+		return
+	}
+
+	series, err := h.service.GetSeries(c.Request.Context(), seriesID, userID)
+	if err != nil {
+		i18n.RespondError(c, http.StatusNotFound, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"tasks": series,
+		"count": len(series),
+	})
+}
+
+// UpdateSeries handles PUT /tasks/series/:series_id - edits every unfinished occurrence of a series
+func (h *Handler) UpdateSeries(c *gin.Context) {
+	seriesID, err := strconv.ParseInt(c.Param("series_id"), 10, 64)
+	if err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, i18n.NewError("series_id_invalid"))
+		return
+	}
+
+	var req UpdateSeriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, i18n.NewError("request_invalid_detail", err))
+		return
+	}
+
+	userID, err := h.getUserIDFromClaims(c)
+	if err != nil {
+		i18n.RespondError(c, http.StatusUnauthorized, err)
+		return
+	}
+
+	series, err := h.service.UpdateSeries(c.Request.Context(), seriesID, userID, req)
+	if err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"tasks": series,
+		"count": len(series),
+	})
+}
+
+// StopSeries handles POST /tasks/series/:series_id/stop - stops generating new occurrences
+func (h *Handler) StopSeries(c *gin.Context) {
+	seriesID, err := strconv.ParseInt(c.Param("series_id"), 10, 64)
+	if err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, i18n.NewError("series_id_invalid"))
+		return
+	}
+
+	userID, err := h.getUserIDFromClaims(c)
+	if err != nil {
+		i18n.RespondError(c, http.StatusUnauthorized, err)
+		return
+	}
+
+	series, err := h.service.StopSeries(c.Request.Context(), seriesID, userID)
+	if err != nil {
+		i18n.RespondError(c, http.StatusNotFound, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"tasks": series,
+		"count": len(series),
+	})
+}
+
+// Bulk handles POST /tasks/bulk - executes a batch of task operations
+func (h *Handler) Bulk(c *gin.Context) {
+	var req BulkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, i18n.NewError("request_invalid_detail", err))
+		return
+	}
+
+	userID, err := h.getUserIDFromClaims(c)
+	if err != nil {
+		i18n.RespondError(c, http.StatusUnauthorized, err)
+		return
+	}
+
+	resp, err := h.service.Bulk(c.Request.Context(), userID, req)
+	if err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	// An atomic batch that was rolled back is reported as a client error with per-operation details
+	if !resp.Committed {
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// Export handles GET /tasks/export.csv - downloads the tasks of GET /tasks as a CSV file
+func (h *Handler) Export(c *gin.Context) {
+	h.export(c, "text/csv; charset=utf-8", "tasks.csv", writeTasksCSV)
+}
+
+// ExportTodoTxt handles GET /tasks/export.txt - downloads the tasks of GET /tasks in the todo.txt format
+func (h *Handler) ExportTodoTxt(c *gin.Context) {
+	h.export(c, "text/plain; charset=utf-8", "todo.txt", writeTasksTodoTxt)
+}
+
+// ExportMarkdown handles GET /tasks/export.md - downloads the tasks of GET /tasks as Markdown checklists
+func (h *Handler) ExportMarkdown(c *gin.Context) {
+	h.export(c, "text/markdown; charset=utf-8", "tasks.md", writeTasksMarkdown)
+}
+
+// export writes the tasks of GET /tasks as a file download in the format of write
+func (h *Handler) export(c *gin.Context, contentType string, filename string, write func(io.Writer, []*TaskResponse) error) {
+	userID, err := h.getUserIDFromClaims(c)
+	if err != nil {
+		i18n.RespondError(c, http.StatusUnauthorized, err)
+		return
+	}
+
+	filter, err := parseTaskFilter(c)
+	if err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	tasks, err := h.service.GetAllByUser(c.Request.Context(), userID, filter)
+	if err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.Status(http.StatusOK)
+	if err := write(c.Writer, tasks); err != nil {
+		log.Printf("failed to write %s: %v", filename, err)
+	}
+}
+
+// Import handles POST /tasks/import - creates tasks from a CSV, todo.txt or Markdown file, sent as
+// the request body or as the "file" field of a multipart form
+func (h *Handler) Import(c *gin.Context) {
+	userID, err := h.getUserIDFromClaims(c)
+	if err != nil {
+		i18n.RespondError(c, http.StatusUnauthorized, err)
+		return
+	}
+
+	dryRun, err := strconv.ParseBool(c.DefaultQuery("dry_run", "false"))
+	if err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, i18n.NewError("task_import_dry_run_invalid"))
+		return
+	}
+
+	format := c.Query("format")
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, MaxImportBytes)
+	var body io.Reader = c.Request.Body
+	if c.ContentType() == "multipart/form-data" {
+		header, err := c.FormFile("file")
+		if err != nil {
+			i18n.RespondError(c, http.StatusBadRequest, i18n.NewError("task_import_file_missing"))
+			return
+		}
+		file, err := header.Open()
+		if err != nil {
+			i18n.RespondError(c, http.StatusBadRequest, i18n.NewError("task_import_file_missing"))
+			return
+		}
+		defer file.Close()
+		body = file
+		if format == "" {
+			format = importFormatOf(header.Filename)
+		}
+	}
+	if format == "" && c.ContentType() == "text/markdown" {
+		format = FormatMarkdown
+	}
+
+	var rows []ImportRow
+	switch format {
+	case "", FormatCSV:
+		rows, err = parseImportCSV(body, c.QueryMap("map"))
+	case FormatTodoTxt:
+		rows, err = parseImportTodoTxt(body)
+	case FormatMarkdown:
+		rows, err = parseImportMarkdown(body)
+	default:
+		i18n.RespondError(c, http.StatusBadRequest, i18n.NewError("task_import_format_invalid"))
+		return
+	}
+	if err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	result, err := h.service.Import(c.Request.Context(), userID, ImportRequest{Rows: rows, DryRun: dryRun})
+	if err != nil {
+		if errors.Is(err, workspaces.ErrReadOnly) {
+			i18n.RespondError(c, http.StatusForbidden, err)
+			return
+		}
+		i18n.RespondError(c, http.StatusInternalServerError, i18n.NewError("task_import_failed"))
+		return
+	}
+
+	// An import with invalid rows writes nothing and is reported as a client error with per-row details
+	if !result.DryRun && !result.Committed {
+		c.JSON(http.StatusBadRequest, result)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// importFormatOf picks the import format from the extension of an uploaded file, "" when it tells nothing
+func importFormatOf(filename string) string {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".txt":
+		return FormatTodoTxt
+	case ".md", ".markdown":
+		return FormatMarkdown
+	case ".csv":
+		return FormatCSV
+	default:
+		return ""
+	}
+}
+
+// GetStats handles GET /stats - summarizes the tasks of the current space for dashboards
+func (h *Handler) GetStats(c *gin.Context) {
+	userID, err := h.getUserIDFromClaims(c)
+	if err != nil {
+		i18n.RespondError(c, http.StatusUnauthorized, err)
+		return
+	}
+
+	req := StatsRequest{Interval: c.Query("interval")}
+	if value := c.Query("from"); value != "" {
+		parsed, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			i18n.RespondError(c, http.StatusBadRequest, i18n.NewError("time_from_invalid"))
+			return
+		}
+		req.From = &parsed
+	}
+	if value := c.Query("to"); value != "" {
+		parsed, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			i18n.RespondError(c, http.StatusBadRequest, i18n.NewError("time_to_invalid"))
+			return
+		}
+		req.To = &parsed
+	}
+
+	stats, err := h.service.GetStats(c.Request.Context(), userID, req)
+	if err != nil {
+		var requestErr *StatsRequestError
+		if errors.As(err, &requestErr) {
+			i18n.RespondError(c, http.StatusBadRequest, err)
+			return
+		}
+		i18n.RespondError(c, http.StatusInternalServerError, i18n.NewError("task_stats_failed"))
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// GetMatrix handles GET /tasks/matrix - groups open tasks into Eisenhower quadrants
+func (h *Handler) GetMatrix(c *gin.Context) {
+	userID, err := h.getUserIDFromClaims(c)
+	if err != nil {
+		i18n.RespondError(c, http.StatusUnauthorized, err)
+		return
+	}
+
+	matrix, err := h.service.GetMatrix(c.Request.Context(), userID)
+	if err != nil {
+		i18n.RespondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, matrix)
+}
+
+// GetShared handles GET /tasks/shared - lists the tasks other users shared with the caller
+func (h *Handler) GetShared(c *gin.Context) {
+	userID, err := h.getUserIDFromClaims(c)
+	if err != nil {
+		i18n.RespondError(c, http.StatusUnauthorized, err)
+		return
+	}
+
+	tasks, err := h.service.GetShared(c.Request.Context(), userID)
+	if err != nil {
+		i18n.RespondError(c, http.StatusInternalServerError, i18n.NewError("tasks_shared_failed"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"tasks": tasks,
+		"count": len(tasks),
+	})
+}
+
+// Restore handles POST /tasks/:id/restore - takes a task out of the trash
+func (h *Handler) Restore(c *gin.Context) {
+	taskID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, ErrInvalidTaskID)
+		return
+	}
+
+	userID, err := h.getUserIDFromClaims(c)
+	if err != nil {
+		i18n.RespondError(c, http.StatusUnauthorized, err)
+		return
+	}
+
+	task, err := h.service.Restore(c.Request.Context(), taskID, userID)
+	if err != nil {
+		if errors.Is(err, ErrNotInTrash) {
+			i18n.RespondError(c, http.StatusNotFound, err)
+			return
+		}
+		i18n.RespondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, task)
+}
+
+// GetHistory handles GET /tasks/:id/history - lists the recorded changes of a task
+func (h *Handler) GetHistory(c *gin.Context) {
+	taskID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, ErrInvalidTaskID)
+		return
+	}
+
+	userID, err := h.getUserIDFromClaims(c)
+	if err != nil {
+		i18n.RespondError(c, http.StatusUnauthorized, err)
+		return
+	}
+
+	events, err := h.service.GetHistory(c.Request.Context(), taskID, userID)
+	if err != nil {
+		i18n.RespondError(c, http.StatusNotFound, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"events": events,
+		"count":  len(events),
+	})
+}
+
+// parseTaskFilter reads the task list filters and sort order from the query string
+func parseTaskFilter(c *gin.Context) (TaskFilter, error) {
+	var filter TaskFilter
+
+	// Parse category_id query parameter
+	if categoryIDStr := c.Query("category_id"); categoryIDStr != "" {
+		catID, err := strconv.ParseInt(categoryIDStr, 10, 64)
+		if err != nil {
+			return filter, i18n.NewError("task_category_param_invalid")
+		}
+		filter.CategoryID = &catID
+	}
+
+	// Parse state_id query parameter
+	if stateIDStr := c.Query("state_id"); stateIDStr != "" {
+		stID, err := strconv.ParseInt(stateIDStr, 10, 64)
+		if err != nil {
+			return filter, i18n.NewError("task_state_param_invalid")
+		}
+		filter.StateID = &stID
+	}
+
+	// Parse tag_ids query parameter (comma separated) and tag_mode
+	if tagIDsStr := c.Query("tag_ids"); tagIDsStr != "" {
+		for _, part := range strings.Split(tagIDsStr, ",") {
+			tagID, err := strconv.ParseInt(strings.TrimSpace(part), 10, 64)
+			if err != nil {
+				return filter, i18n.NewError("task_tag_ids_invalid")
+			}
+			filter.TagIDs = append(filter.TagIDs, tagID)
+		}
+	}
+	filter.TagMode = c.DefaultQuery("tag_mode", TagModeAny)
+	if filter.TagMode != TagModeAny && filter.TagMode != TagModeAll {
+		return filter, i18n.NewError("task_tag_mode_invalid")
+	}
+
+	// Parse assigned_to and created_by query parameters; only the current user can be named
+	switch c.Query("assigned_to") {
+	case "":
+	case "me":
+		filter.AssignedToMe = true
+	default:
+		return filter, i18n.NewError("task_assigned_to_invalid")
+	}
+	switch c.Query("created_by") {
+	case "":
+	case "me":
+		filter.CreatedByMe = true
+	default:
+		return filter, i18n.NewError("task_created_by_invalid")
+	}
+
+	// Parse sort query parameter
+	filter.Sort = c.Query("sort")
+	if filter.Sort != "" && filter.Sort != SortCreated && filter.Sort != SortPriority && filter.Sort != SortEndDate && filter.Sort != SortPosition {
+		return filter, i18n.NewError("task_sort_invalid")
+	}
+
+	return filter, nil
+}
+
 // Helper function to extract user ID from JWT claims
 func (h *Handler) getUserIDFromClaims(c *gin.Context) (int64, error) {
 	claims, exists := c.Get("claims")
 	if !exists {
-		return 0, errors.New("user not authenticated")
+		return 0, i18n.NewError("user_not_authenticated")
 	}
 
 	claimsMap, ok := claims.(jwt.MapClaims)
@@ -179,7 +992,7 @@ func (h *Handler) getUserIDFromClaims(c *gin.Context) (int64, error) {
 
 	userIDInterface, exists := claimsMap["uid"]
 	if !exists {
-		return 0, errors.New("user ID not found in token")
+		return 0, i18n.NewError("user_id_missing")
 	}
 
 	// Convert userID to int64
@@ -191,6 +1004,6 @@ func (h *Handler) getUserIDFromClaims(c *gin.Context) (int64, error) {
 	case int:
 		return int64(v), nil
 	default:
-		return 0, errors.New("invalid user ID format")
+		return 0, i18n.NewError("user_id_invalid")
 	}
 }
\ No newline at end of file
@@ -0,0 +1,80 @@
+package tasks
+
+import (
+	"context"
+)
+
+// recordEvent stores the difference between two versions of a task as a history event.
+// before is nil for a created task and after is nil for a deleted one; updates that
+// change nothing are not recorded. It must run in the same transaction as the change.
+func (s *service) recordEvent(ctx context.Context, actorID int64, before *Task, after *Task) error {
+	event := &TaskEvent{ActorID: actorID, Changes: diffTasks(before, after)}
+	switch {
+	case before == nil:
+		event.Action = EventCreated
+		event.TaskID, event.UserID = after.ID, after.UserID
+	case after == nil:
+		event.Action = EventDeleted
+		event.TaskID, event.UserID = before.ID, before.UserID
+	default:
+		event.Action = EventUpdated
+		event.TaskID, event.UserID = after.ID, after.UserID
+	}
+
+	if len(event.Changes) == 0 && event.Action == EventUpdated {
+		return nil
+	}
+
+	return s.repo.CreateEvent(ctx, event)
+}
+
+// diffTasks lists the user-visible fields whose values differ between two versions of a task
+func diffTasks(before *Task, after *Task) []FieldChange {
+	oldValues, newValues := historyValues(before), historyValues(after)
+
+	changes := []FieldChange{}
+	for _, field := range historyFields {
+		if oldValues[field] != newValues[field] {
+			changes = append(changes, FieldChange{Field: field, Old: oldValues[field], New: newValues[field]})
+		}
+	}
+
+	return changes
+}
+
+// historyFields are the task fields tracked in the history, in display order
+var historyFields = []string{
+	"task_text", "end_date", "state_id", "category_id", "parent_id", "recurrence", "priority", "important", "assignee_id",
+}
+
+// historyValues flattens the tracked fields of a task into comparable JSON values (nil when unset)
+func historyValues(t *Task) map[string]interface{} {
+	values := make(map[string]interface{}, len(historyFields))
+	if t == nil {
+		return values
+	}
+
+	values["task_text"] = t.TaskText
+	if t.EndDate != nil {
+		values["end_date"] = t.EndDate.Format("2006-01-02")
+	}
+	if t.StateID != nil {
+		values["state_id"] = *t.StateID
+	}
+	if t.CategoryID != nil {
+		values["category_id"] = *t.CategoryID
+	}
+	if t.ParentID != nil {
+		values["parent_id"] = *t.ParentID
+	}
+	if t.Recurrence != nil {
+		values["recurrence"] = *t.Recurrence
+	}
+	values["priority"] = t.Priority
+	values["important"] = t.Important
+	if t.AssigneeID != nil {
+		values["assignee_id"] = *t.AssigneeID
+	}
+
+	return values
+}
@@ -0,0 +1,220 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"backend/root/internal/i18n"
+	"backend/root/internal/workspaces"
+)
+
+// Import creates a task for every row of an imported file, validating each one with the rules of Create.
+// Categories and tags are matched by name and created when missing. Rows are only written when every one
+// of them is valid, all in one transaction; a dry run stops after validating.
+func (s *service) Import(ctx context.Context, userID int64, req ImportRequest) (*ImportResult, error) {
+	if err := workspaces.CheckWritable(ctx); err != nil {
+		return nil, err
+	}
+	if len(req.Rows) == 0 {
+		return nil, i18n.NewError("task_import_file_no_tasks")
+	}
+	if len(req.Rows) > MaxImportRows {
+		return nil, i18n.NewError("task_import_file_too_many")
+	}
+
+	var names, tagNames []string
+	for _, row := range req.Rows {
+		if row.Category != "" {
+			names = append(names, row.Category)
+		}
+		tagNames = append(tagNames, row.Tags...)
+	}
+	categoryIDs, err := s.repo.GetCategoryIDs(ctx, names)
+	if err != nil {
+		return nil, err
+	}
+	tagIDs, err := s.repo.GetTagIDs(ctx, userID, tagNames)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ImportResult{
+		DryRun:        req.DryRun,
+		NewCategories: []string{},
+		NewTags:       []string{},
+		Rows:          make([]ImportRowResult, len(req.Rows)),
+	}
+	tasks := make([]*Task, len(req.Rows))
+	newCategories := make(map[string]bool)
+	newTags := make(map[string]bool)
+	for i, row := range req.Rows {
+		result.Rows[i].Line = row.Line
+
+		task, err := s.importTask(ctx, userID, row, categoryIDs)
+		if err != nil {
+			result.Rows[i].Code, result.Rows[i].Error = i18n.Describe(ctx, err)
+			result.Failed++
+			continue
+		}
+		tasks[i] = task
+		result.Rows[i].Success = true
+		result.Succeeded++
+
+		key := strings.ToLower(row.Category)
+		if row.Category != "" && task.CategoryID == nil && !newCategories[key] {
+			newCategories[key] = true
+			result.NewCategories = append(result.NewCategories, row.Category)
+		}
+		for _, name := range row.Tags {
+			key := strings.ToLower(name)
+			if _, ok := tagIDs[key]; !ok && !newTags[key] {
+				newTags[key] = true
+				result.NewTags = append(result.NewTags, name)
+			}
+		}
+	}
+
+	if req.DryRun || result.Failed > 0 {
+		return result, nil
+	}
+
+	err = s.withTx(ctx, func(tx *service) error {
+		for _, name := range result.NewCategories {
+			id, err := tx.repo.CreateCategory(ctx, name)
+			if err != nil {
+				return err
+			}
+			categoryIDs[strings.ToLower(name)] = id
+		}
+		for _, name := range result.NewTags {
+			id, err := tx.repo.CreateTag(ctx, userID, name)
+			if err != nil {
+				return err
+			}
+			tagIDs[strings.ToLower(name)] = id
+		}
+
+		for i, task := range tasks {
+			if name := req.Rows[i].Category; name != "" && task.CategoryID == nil {
+				id := categoryIDs[strings.ToLower(name)]
+				task.CategoryID = &id
+			}
+			if err := tx.insertTask(ctx, userID, task); err != nil {
+				return fmt.Errorf("failed to import line %d: %w", req.Rows[i].Line, err)
+			}
+			for _, name := range req.Rows[i].Tags {
+				if err := tx.repo.AttachTag(ctx, task.ID, tagIDs[strings.ToLower(name)]); err != nil {
+					return fmt.Errorf("failed to import line %d: %w", req.Rows[i].Line, err)
+				}
+			}
+			result.Rows[i].TaskID = task.ID
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	result.Committed = true
+
+	for _, task := range tasks {
+		s.notifyNewAssignee(ctx, userID, task)
+	}
+
+	return result, nil
+}
+
+// importTask converts an imported row into a create request and validates it like Create.
+// A category that does not exist yet is left unset, to be created on commit. Completed
+// tasks are imported in the Completed state and may keep an end date in the past.
+func (s *service) importTask(ctx context.Context, userID int64, row ImportRow, categoryIDs map[string]int64) (*Task, error) {
+	req := CreateTaskRequest{
+		TaskText:   row.TaskText,
+		EndDate:    row.EndDate,
+		Assignee:   row.Assignee,
+		Recurrence: row.Recurrence,
+	}
+
+	var endDate, completedAt *time.Time
+	if row.Completed {
+		if row.EndDate != "" {
+			date, err := time.Parse("2006-01-02", row.EndDate)
+			if err != nil {
+				return nil, i18n.NewError("task_end_date_format")
+			}
+			endDate = &date
+			req.EndDate = "" // set below, skipping the check for past dates
+		}
+		req.Recurrence = "" // a completed task has no next occurrence
+		now := time.Now()
+		completedAt = &now
+		if row.CompletedAt != "" {
+			date, err := time.Parse("2006-01-02", row.CompletedAt)
+			if err != nil {
+				return nil, i18n.NewError("task_import_completed_format")
+			}
+			completedAt = &date
+		}
+	}
+
+	for _, name := range row.Tags {
+		if len(name) > 50 {
+			return nil, i18n.NewError("tag_name_too_long")
+		}
+	}
+
+	if row.Priority != "" {
+		priority, err := strconv.Atoi(row.Priority)
+		if err != nil {
+			return nil, i18n.NewError("task_priority_invalid")
+		}
+		req.Priority = &priority
+	}
+
+	if row.Important != "" {
+		important, err := parseImportBool(row.Important)
+		if err != nil {
+			return nil, err
+		}
+		req.Important = &important
+	}
+
+	if row.Category != "" {
+		if len(row.Category) > 100 {
+			return nil, i18n.NewError("category_name_too_long")
+		}
+		if id, ok := categoryIDs[strings.ToLower(row.Category)]; ok {
+			req.CategoryID = &id
+		}
+	}
+
+	task, err := s.newTask(ctx, userID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if row.Completed {
+		completed := StateCompleted
+		task.StateID = &completed
+		task.CompletedAt = completedAt
+		if endDate != nil {
+			task.EndDate = endDate
+		}
+	}
+
+	return task, nil
+}
+
+// parseImportBool reads a yes/no cell as spreadsheets usually write it
+func parseImportBool(value string) (bool, error) {
+	switch strings.ToLower(value) {
+	case "true", "yes", "y", "1", "x":
+		return true, nil
+	case "false", "no", "n", "0":
+		return false, nil
+	default:
+		return false, i18n.NewError("task_import_important")
+	}
+}
@@ -0,0 +1,235 @@
+package tasks
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+
+	"backend/root/internal/i18n"
+)
+
+// Markdown priority markers, written after the task text
+var markdownPriorities = map[int]string{
+	PriorityHigh:   "!high",
+	PriorityMedium: "!medium",
+	PriorityLow:    "!low",
+}
+
+// writeTasksMarkdown writes tasks as Markdown checklists: first the tasks without a category,
+// then a "## Category" section per category, in the order the categories first appear.
+// Each task is a line such as "- [x] Task text !high #tag due:2024-01-20 done:2024-01-16".
+// Words of the text that would read back as markers are escaped with a backslash.
+func writeTasksMarkdown(w io.Writer, tasks []*TaskResponse) error {
+	var names []string
+	groups := make(map[string][]*TaskResponse)
+	for _, task := range tasks {
+		name := ""
+		if task.Category != nil {
+			name = task.Category.Name
+		}
+		if _, ok := groups[name]; !ok && name != "" {
+			names = append(names, name)
+		}
+		groups[name] = append(groups[name], task)
+	}
+
+	writer := bufio.NewWriter(w)
+	writer.WriteString("# Tasks\n")
+	if len(groups[""]) > 0 {
+		writer.WriteString("\n")
+		writeMarkdownItems(writer, groups[""])
+	}
+	for _, name := range names {
+		title := strings.Join(strings.Fields(name), " ")
+		if strings.HasSuffix(title, "#") {
+			title += " #" // Closing sequence, so that the last # stays in the name
+		}
+		writer.WriteString("\n## " + title + "\n\n")
+		writeMarkdownItems(writer, groups[name])
+	}
+
+	return writer.Flush()
+}
+
+// writeMarkdownItems writes the checklist items of a group of tasks
+func writeMarkdownItems(writer *bufio.Writer, tasks []*TaskResponse) {
+	for _, task := range tasks {
+		box := "[ ]"
+		if task.State != nil && task.State.IsDone {
+			box = "[x]"
+		}
+
+		parts := []string{"-", box, escapeTaskText(task.TaskText, isMarkdownMarker)}
+		if marker, ok := markdownPriorities[task.Priority]; ok {
+			parts = append(parts, marker)
+		}
+		for _, tag := range task.Tags {
+			parts = append(parts, "#"+todoTxtName(tag.Name))
+		}
+		if task.EndDate != nil {
+			parts = append(parts, "due:"+task.EndDate.Format("2006-01-02"))
+		}
+		if box == "[x]" && task.CompletedAt != nil {
+			parts = append(parts, "done:"+task.CompletedAt.UTC().Format("2006-01-02"))
+		}
+
+		writer.WriteString(strings.Join(parts, " ") + "\n")
+	}
+}
+
+// parseImportMarkdown reads the checklist items of a Markdown file as tasks. A heading of level
+// two or more sets the category of the items below it and a top-level heading clears it.
+// Nested items are imported as tasks of their own; other lines are ignored.
+func parseImportMarkdown(r io.Reader) ([]ImportRow, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), MaxImportBytes)
+
+	var rows []ImportRow
+	category := ""
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if line == 1 {
+			text = strings.TrimPrefix(text, "\ufeff") // Byte order mark written by some editors
+		}
+
+		if level, title, ok := markdownHeading(text); ok {
+			category = ""
+			if level > 1 {
+				category = title
+			}
+			continue
+		}
+
+		checked, item, ok := markdownItem(text)
+		if !ok {
+			continue
+		}
+		if len(rows) == MaxImportRows {
+			return nil, i18n.NewError("task_import_file_too_many")
+		}
+
+		row, err := parseMarkdownItem(item)
+		if err != nil {
+			return nil, i18n.NewError("task_import_markdown_line", line, err)
+		}
+		row.Line = line
+		row.Category = category
+		row.Completed = checked
+		rows = append(rows, row)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, i18n.NewError("task_import_file_read_failed")
+	}
+
+	if len(rows) == 0 {
+		return nil, i18n.NewError("task_import_file_no_tasks")
+	}
+
+	return rows, nil
+}
+
+// markdownHeading returns the level and text of an ATX heading such as "## Home"
+func markdownHeading(line string) (int, string, bool) {
+	level := 0
+	for level < len(line) && line[level] == '#' {
+		level++
+	}
+	if level == 0 || level > 6 || (level < len(line) && line[level] != ' ' && line[level] != '\t') {
+		return 0, "", false
+	}
+
+	// A closing sequence of # is only one when a space separates it from the title, as in "## C# ##"
+	title := strings.TrimSpace(line[level:])
+	if closing := strings.TrimRight(title, "#"); closing == "" || strings.HasSuffix(closing, " ") || strings.HasSuffix(closing, "\t") {
+		title = strings.TrimSpace(closing)
+	}
+	return level, title, true
+}
+
+// markdownItem returns whether a task list item such as "- [x] text" is checked and its text
+func markdownItem(line string) (bool, string, bool) {
+	if len(line) < 2 || !strings.ContainsRune("-*+", rune(line[0])) || line[1] != ' ' {
+		return false, "", false
+	}
+
+	rest := strings.TrimLeft(line[2:], " ")
+	if len(rest) < 3 || rest[0] != '[' || rest[2] != ']' {
+		return false, "", false
+	}
+	var checked bool
+	switch rest[1] {
+	case ' ':
+	case 'x', 'X':
+		checked = true
+	default:
+		return false, "", false
+	}
+
+	return checked, strings.TrimSpace(rest[3:]), true
+}
+
+// parseMarkdownItem reads the text of a checklist item: the words that are not a priority
+// marker, a #tag, due: or done:
+func parseMarkdownItem(item string) (ImportRow, error) {
+	var row ImportRow
+
+	words := []string{}
+	for _, field := range strings.Fields(item) {
+		if len(field) > 1 && field[0] == '\\' {
+			words = append(words, field[1:]) // Escaped word, taken as written
+			continue
+		}
+		if priority, ok := markdownPriority(field); ok {
+			row.Priority = strconv.Itoa(priority)
+			continue
+		}
+
+		switch {
+		case isMarkdownTag(field):
+			row.Tags = appendName(row.Tags, fromTodoTxtName(field[1:]))
+		case strings.HasPrefix(field, "due:") && len(field) > len("due:"):
+			row.EndDate = strings.TrimPrefix(field, "due:")
+		case strings.HasPrefix(field, "done:") && len(field) > len("done:"):
+			row.CompletedAt = strings.TrimPrefix(field, "done:")
+			if !isTodoTxtDate(row.CompletedAt) {
+				return row, i18n.NewError("task_import_completed_format")
+			}
+		default:
+			words = append(words, field)
+		}
+	}
+	row.TaskText = strings.Join(words, " ")
+
+	return row, nil
+}
+
+// isMarkdownMarker reports whether a word of a task text would be read as a Markdown marker
+func isMarkdownMarker(word string, first bool) bool {
+	_, priority := markdownPriority(word)
+	return priority || isMarkdownTag(word) ||
+		(strings.HasPrefix(word, "due:") && len(word) > len("due:")) ||
+		(strings.HasPrefix(word, "done:") && len(word) > len("done:"))
+}
+
+// isMarkdownTag reports whether a word is a #tag; ## and #123 are kept as text
+func isMarkdownTag(word string) bool {
+	return len(word) > 1 && word[0] == '#' && word[1] != '#' && !isDigit(word[1])
+}
+
+// markdownPriority returns the task priority of a priority marker such as !high
+func markdownPriority(word string) (int, bool) {
+	for priority, marker := range markdownPriorities {
+		if strings.EqualFold(word, marker) {
+			return priority, true
+		}
+	}
+	return 0, false
+}
+
+// isDigit reports whether a byte is an ASCII digit
+func isDigit(b byte) bool {
+	return b >= '0' && b <= '9'
+}
@@ -0,0 +1,106 @@
+package tasks
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestMarkdownRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		task TaskResponse
+		want ImportRow
+	}{
+		{
+			name: "open task with every field",
+			task: TaskResponse{
+				TaskText: "Pay rent", State: openState, Priority: PriorityHigh, EndDate: date(2024, 1, 20),
+				Category: &CategoryInfo{Name: "Home Office"}, Tags: tags("bills", "due soon"),
+			},
+			want: ImportRow{TaskText: "Pay rent", Priority: "3", EndDate: "2024-01-20", Category: "Home Office", Tags: []string{"bills", "due soon"}},
+		},
+		{
+			name: "completed task keeps its completion date",
+			task: TaskResponse{TaskText: "File taxes", State: doneState, Priority: PriorityLow, CompletedAt: date(2024, 1, 16)},
+			want: ImportRow{TaskText: "File taxes", Priority: "1", Completed: true, CompletedAt: "2024-01-16"},
+		},
+		{
+			name: "completed task without completion date",
+			task: TaskResponse{TaskText: "Water plants", State: doneState},
+			want: ImportRow{TaskText: "Water plants", Completed: true},
+		},
+		{
+			name: "underscores and backslashes in tag names",
+			task: TaskResponse{TaskText: "Clean up", State: openState, Tags: tags(`C:\temp`, "a_b c")},
+			want: ImportRow{TaskText: "Clean up", Tags: []string{`C:\temp`, "a_b c"}},
+		},
+		{
+			name: "marker words in the text",
+			task: TaskResponse{TaskText: "Fix #42 !HIGH impact #backend due:friday done:yes", State: openState},
+			want: ImportRow{TaskText: "Fix #42 !HIGH impact #backend due:friday done:yes"},
+		},
+		{
+			name: "words starting with a backslash",
+			task: TaskResponse{TaskText: `Escape \#tags`, State: openState},
+			want: ImportRow{TaskText: `Escape \#tags`},
+		},
+		{
+			name: "category ending with #",
+			task: TaskResponse{TaskText: "Learn generics", State: openState, Category: &CategoryInfo{Name: "C#"}},
+			want: ImportRow{TaskText: "Learn generics", Category: "C#"},
+		},
+		{
+			name: "category ending with a spaced #",
+			task: TaskResponse{TaskText: "Call", State: openState, Category: &CategoryInfo{Name: "Room #"}},
+			want: ImportRow{TaskText: "Call", Category: "Room #"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := tt.task
+
+			var buf bytes.Buffer
+			if err := writeTasksMarkdown(&buf, []*TaskResponse{&task}); err != nil {
+				t.Fatalf("writeTasksMarkdown returned %v", err)
+			}
+			rows, err := parseImportMarkdown(&buf)
+			if err != nil {
+				t.Fatalf("parseImportMarkdown returned %v", err)
+			}
+			if len(rows) != 1 {
+				t.Fatalf("parseImportMarkdown returned %d rows, want 1", len(rows))
+			}
+
+			got := rows[0]
+			got.Line = 0
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("round trip of %q = %+v, want %+v", buf.String(), got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarkdownHeading(t *testing.T) {
+	tests := []struct {
+		line  string
+		level int
+		title string
+		ok    bool
+	}{
+		{"# Tasks", 1, "Tasks", true},
+		{"## Home ##", 2, "Home", true},
+		{"## C#", 2, "C#", true},
+		{"## Room # #", 2, "Room #", true},
+		{"#hashtag", 0, "", false},
+		{"####### Too deep", 0, "", false},
+	}
+
+	for _, tt := range tests {
+		level, title, ok := markdownHeading(tt.line)
+		if level != tt.level || title != tt.title || ok != tt.ok {
+			t.Errorf("markdownHeading(%q) = %d, %q, %v, want %d, %q, %v", tt.line, level, title, ok, tt.level, tt.title, tt.ok)
+		}
+	}
+}
@@ -2,6 +2,8 @@ package tasks
 
 import (
 	"time"
+
+	"backend/root/internal/i18n"
 )
 
 // Task represents a task in the system
@@ -12,14 +14,41 @@ type Task struct {
 	EndDate      *time.Time `json:"end_date" db:"end_date"`
 	StateID      *int64     `json:"state_id" db:"id_state"`
 	CategoryID   *int64     `json:"category_id" db:"id_category"`
-	UserID       int64      `json:"user_id" db:"id_user"`
+	UserID       int64      `json:"user_id" db:"id_user"` // Owner of the task
+	ParentID     *int64     `json:"parent_id" db:"parent_id"`
+	Recurrence   *string    `json:"recurrence" db:"recurrence"`
+	SeriesID     *int64     `json:"series_id" db:"series_id"`
+	Occurrence   int        `json:"occurrence" db:"occurrence"`
+	Priority     int        `json:"priority" db:"priority"`
+	Important    bool       `json:"important" db:"important"`
+	StartedAt    *time.Time `json:"started_at" db:"started_at"`
+	CompletedAt  *time.Time `json:"completed_at" db:"completed_at"`
+	CreatedBy    *int64     `json:"created_by" db:"created_by"`   // nil for tasks created before creators were recorded
+	AssigneeID   *int64     `json:"assignee_id" db:"assignee_id"` // nil when unassigned
+	WorkspaceID  *int64     `json:"workspace_id" db:"workspace_id"` // nil for personal tasks
+	Position     *string    `json:"position" db:"position"`         // Order within its board column, nil until first placed
+	Version      int64      `json:"version" db:"version"`
 }
 
+// ErrVersionMismatch is returned when a write is based on an outdated version of a task
+var ErrVersionMismatch = i18n.NewError("task_version_mismatch")
+
+// ErrDependencyCycle is returned when a dependency would make a task wait, directly or transitively, on itself
+var ErrDependencyCycle = i18n.NewError("dependency_cycle")
+
+// ErrNotInTrash is returned when restoring a task that is not in the trash
+var ErrNotInTrash = i18n.NewError("task_not_found_in_trash")
+
 // CreateTaskRequest represents the request payload for creating a task
 type CreateTaskRequest struct {
 	TaskText   string `json:"task_text" binding:"required,min=1,max=1000"`
 	EndDate    string `json:"end_date,omitempty"` // Optional, format: "2006-01-02"
 	CategoryID *int64 `json:"category_id,omitempty"`
+	ParentID   *int64 `json:"parent_id,omitempty"`  // Optional, makes the task a subtask
+	Assignee   string `json:"assignee,omitempty"`   // Optional username of the user responsible for the task
+	Recurrence string `json:"recurrence,omitempty"` // Optional RRULE, requires end_date
+	Priority   *int   `json:"priority,omitempty"`   // Optional, 0 (none) to 3 (high)
+	Important  *bool  `json:"important,omitempty"`
 }
 
 // UpdateTaskRequest represents the request payload for updating a task
@@ -27,7 +56,17 @@ type UpdateTaskRequest struct {
 	TaskText string `json:"task_text" binding:"required,min=1,max=1000"`
 	CategoryID *int64 `json:"category_id"`
 	EndDate  string `json:"end_date,omitempty"` 
-	StateID  *int64 `json:"state_id,omitempty"`
+	StateID  *int64 `json:"state_id,omitempty"` // nil keeps the current state; changes must follow the transition graph
+	ParentID *int64 `json:"parent_id,omitempty"` // nil keeps the current parent; 0 detaches the task from it
+	// Recurrence replaces the RRULE when set; an empty string stops the recurrence
+	Recurrence *string `json:"recurrence,omitempty"`
+	// Priority and Important keep their current values when omitted
+	Priority  *int  `json:"priority,omitempty"`
+	Important *bool `json:"important,omitempty"`
+	// IgnoreBlockers allows starting or completing a task whose blockers are unfinished
+	IgnoreBlockers bool `json:"ignore_blockers,omitempty"`
+	// IfMatch is the version the client last saw, taken from the If-Match header
+	IfMatch *int64 `json:"-"`
 }
 
 // TaskResponse represents the response format for task data with related information
@@ -39,12 +78,106 @@ type TaskResponse struct {
 	State        *StateInfo        `json:"state"`
 	Category     *CategoryInfo     `json:"category"`
 	UserID       int64             `json:"user_id"`
+	ParentID     *int64            `json:"parent_id"`
+	Recurrence   *string           `json:"recurrence"`
+	SeriesID     *int64            `json:"series_id"`
+	Occurrence   int               `json:"occurrence"`
+	Priority     int               `json:"priority"`
+	Important    bool              `json:"important"`
+	StartedAt    *time.Time        `json:"started_at"`   // First time the task went In Progress
+	CompletedAt  *time.Time        `json:"completed_at"` // When the task was completed, nil while open
+	CreatedBy    *int64            `json:"created_by"`
+	Assignee     *AssigneeInfo     `json:"assignee"` // nil when unassigned
+	WorkspaceID  *int64            `json:"workspace_id"` // nil for personal tasks
+	Position     *string           `json:"position"`     // Order within its board column
+	Progress     *TaskProgress     `json:"progress,omitempty"` // Only present for tasks with subtasks
+	Blocked      bool              `json:"blocked"`              // True while any blocker is unfinished
+	BlockedBy    []BlockerInfo     `json:"blocked_by"`
+	Tags         []TagInfo         `json:"tags"`
+	CommentCount int               `json:"comment_count"`
+	DeletedAt    *time.Time        `json:"deleted_at,omitempty"` // Only set for tasks in the trash
+	Version      int64             `json:"version"`              // Incremented on every write, exposed as the ETag
+	Role         string            `json:"role,omitempty"`       // Caller's access, only set on tasks shared with them
+}
+
+// TransitionRequest represents the request payload for POST /tasks/:id/transition
+type TransitionRequest struct {
+	Action         string `json:"action" binding:"required,oneof=start complete reset reopen"`
+	IgnoreBlockers bool   `json:"ignore_blockers,omitempty"`
+	// IfMatch is the version the client last saw, taken from the If-Match header
+	IfMatch *int64 `json:"-"`
+}
+
+// MoveTaskRequest represents the request payload for POST /tasks/:id/move.
+// AfterID and BeforeID are the cards that end up directly above and below the moved one;
+// leave AfterID out to move it to the top of the column and BeforeID to move it to the bottom.
+type MoveTaskRequest struct {
+	StateID        *int64 `json:"state_id,omitempty"` // Target column, nil keeps the current state
+	AfterID        *int64 `json:"after_id,omitempty"`
+	BeforeID       *int64 `json:"before_id,omitempty"`
+	IgnoreBlockers bool   `json:"ignore_blockers,omitempty"`
+	// IfMatch is the version the client last saw, taken from the If-Match header
+	IfMatch *int64 `json:"-"`
+}
+
+// Board groups tasks into one column per workflow state, each in board order
+type Board struct {
+	Columns []*BoardColumn `json:"columns"`
+}
+
+// BoardColumn is a workflow state with its tasks
+type BoardColumn struct {
+	State *StateInfo      `json:"state"`
+	Tasks []*TaskResponse `json:"tasks"`
+	Count int             `json:"count"`
+}
+
+// Column identifies a board column: the tasks in one state of a personal space or a workspace
+type Column struct {
+	StateID     int64
+	UserID      int64  // Owner of the tasks, only used in personal spaces
+	WorkspaceID *int64 // nil for a personal space
+}
+
+// ErrBoardChanged is returned when a card is moved between neighbors that are no longer adjacent
+var ErrBoardChanged = i18n.NewError("task_board_changed")
+
+// TagInfo represents a tag attached to a task
+type TagInfo struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+// BlockerInfo represents a task that must be completed before another one can progress
+type BlockerInfo struct {
+	ID        int64  `json:"id"`
+	TaskText  string `json:"task_text"`
+	Completed bool   `json:"completed"`
+}
+
+// AddBlockerRequest represents the request payload for adding a dependency to a task
+type AddBlockerRequest struct {
+	BlockerID int64 `json:"blocker_id" binding:"required,min=1"`
+}
+
+// TaskProgress summarizes how many of a task's descendants are completed
+type TaskProgress struct {
+	Total     int `json:"total"`
+	Completed int `json:"completed"`
+	Percent   int `json:"percent"`
+}
+
+// TaskNode is a task with its subtasks nested below it
+type TaskNode struct {
+	TaskResponse
+	Children []*TaskNode `json:"children"`
 }
 
 // StateInfo represents basic state information
 type StateInfo struct {
 	ID          int64  `json:"id"`
 	Description string `json:"description"`
+	IsDone      bool   `json:"is_done"` // Tasks in this state count as finished
 }
 
 // CategoryInfo represents basic category information  
@@ -54,11 +187,288 @@ type CategoryInfo struct {
 	Description string `json:"description"`
 }
 
-// TaskState constants
+// AssigneeInfo represents the user a task is assigned to
+type AssigneeInfo struct {
+	ID       int64  `json:"id"`
+	Username string `json:"username"`
+}
+
+// AssignRequest represents the request payload for POST /tasks/:id/assign
+type AssignRequest struct {
+	Username string `json:"username" binding:"required"`
+}
+
+// TaskFilter holds the optional filters and sort order for listing a user's tasks
+type TaskFilter struct {
+	CategoryID *int64
+	StateID    *int64
+	OnlyOpen   bool    // exclude completed tasks
+	TagIDs     []int64 // only tasks carrying these tags
+	TagMode    string  // TagModeAny (default) or TagModeAll
+	Sort       string  // SortCreated (default), SortPriority, SortEndDate or SortPosition
+	// AssignedToMe lists the tasks assigned to the user, whoever owns them, instead of the user's own tasks
+	AssignedToMe bool
+	CreatedByMe  bool // only tasks the user created
+}
+
+// Tag filter modes
+const (
+	TagModeAny = "any" // tasks carrying at least one of the tags
+	TagModeAll = "all" // tasks carrying every tag
+)
+
+// Sort orders for task lists
 const (
-	StateNotStarted = int64(1) // 'Not Started'
+	SortCreated  = "created"  // newest first
+	SortPriority = "priority" // highest priority first, then earliest end date
+	SortEndDate  = "end_date" // earliest end date first, tasks without one last
+	SortPosition = "position" // board order
+)
+
+// Task priorities
+const (
+	PriorityNone   = 0
+	PriorityLow    = 1
+	PriorityMedium = 2
+	PriorityHigh   = 3
+)
+
+// EisenhowerMatrix groups open tasks by urgency (end date proximity) and importance
+type EisenhowerMatrix struct {
+	DoFirst      []*TaskResponse `json:"do_first"`      // urgent and important
+	Schedule     []*TaskResponse `json:"schedule"`      // important, not urgent
+	Delegate     []*TaskResponse `json:"delegate"`      // urgent, not important
+	Eliminate    []*TaskResponse `json:"eliminate"`     // neither urgent nor important
+	UrgentBefore time.Time       `json:"urgent_before"` // tasks due before this date count as urgent
+}
+
+// QuickAddRequest represents the request payload for creating a task from a single line
+type QuickAddRequest struct {
+	Text string `json:"text" binding:"required,min=1,max=1000"` // e.g. "Pay rent tomorrow #Home !high"
+}
+
+// QuickAddParse reports how a quick-add line was read
+type QuickAddParse struct {
+	TaskText   string `json:"task_text"`             // The words left once the markers are removed
+	DatePhrase string `json:"date_phrase,omitempty"` // The words read as the end date, as typed
+	EndDate    string `json:"end_date,omitempty"`    // YYYY-MM-DD
+	Category   string `json:"category,omitempty"`    // Category name as typed, without the #
+	CategoryID *int64 `json:"category_id,omitempty"`
+	Priority   *int   `json:"priority,omitempty"`
+}
+
+// QuickAddResult represents the response format for a quick-add: the created task and how the line was read
+type QuickAddResult struct {
+	Task   *TaskResponse `json:"task"`
+	Parsed QuickAddParse `json:"parsed"`
+}
+
+// Statistics timeline intervals
+const (
+	IntervalDay  = "day"
+	IntervalWeek = "week" // weeks start on Monday
+)
+
+// MaxStatsDays caps the date range of the statistics timeline
+const MaxStatsDays = 366
+
+// StatsRequest selects the date range and the interval of the statistics timeline
+type StatsRequest struct {
+	From     *time.Time // First day, inclusive; nil for 30 days (12 weeks) before To
+	To       *time.Time // Last day, inclusive; nil for today
+	Interval string     // IntervalDay (default) or IntervalWeek
+}
+
+// Stats summarizes the tasks of the current space, for dashboards
+type Stats struct {
+	Total             int             `json:"total"`
+	Open              int             `json:"open"`
+	Completed         int             `json:"completed"`            // tasks in a done state
+	Overdue           int             `json:"overdue"`              // open tasks whose end date has passed
+	CompletionRate    float64         `json:"completion_rate"`      // completed / total, 0 without tasks
+	AvgDaysToComplete *float64        `json:"avg_days_to_complete"` // from creation to completion date, nil without completed tasks
+	StreakDays        int             `json:"streak_days"`          // consecutive days with a completion, up to today or yesterday
+	ByState           []StateCount    `json:"by_state"`
+	ByCategory        []CategoryCount `json:"by_category"`
+	From              string          `json:"from"` // YYYY-MM-DD
+	To                string          `json:"to"`   // YYYY-MM-DD
+	Interval          string          `json:"interval"`
+	Timeline          []StatsPeriod   `json:"timeline"`
+}
+
+// StateCount is the number of tasks in a workflow state
+type StateCount struct {
+	State StateInfo `json:"state"`
+	Count int       `json:"count"`
+}
+
+// CategoryCount is the number of tasks in a category, nil for the tasks without one
+type CategoryCount struct {
+	Category  *CategoryInfo `json:"category"`
+	Count     int           `json:"count"`
+	Completed int           `json:"completed"`
+}
+
+// StatsPeriod counts the tasks created and completed in a day or week of the timeline
+type StatsPeriod struct {
+	Start     string `json:"start"` // YYYY-MM-DD
+	Created   int    `json:"created"`
+	Completed int    `json:"completed"`
+}
+
+// UpdateSeriesRequest represents the request payload for editing every open occurrence of a series
+type UpdateSeriesRequest struct {
+	TaskText   string `json:"task_text" binding:"required,min=1,max=1000"`
+	CategoryID *int64 `json:"category_id"`
+	Recurrence string `json:"recurrence" binding:"required"`
+}
+
+// BulkOperation represents a single operation inside a bulk request.
+// TaskID is required for every operation except "create".
+
+type BulkOperation struct {
+	Op             string  `json:"op" binding:"required,oneof=create update delete change_state move_category"`
+	TaskID         int64   `json:"task_id,omitempty"`
+	TaskText       string  `json:"task_text,omitempty"`
+	EndDate        string  `json:"end_date,omitempty"`
+	CategoryID     *int64  `json:"category_id,omitempty"`
+	StateID        *int64  `json:"state_id,omitempty"`
+	ParentID       *int64  `json:"parent_id,omitempty"`  // on update, nil keeps the current parent and 0 detaches it
+	Assignee       string  `json:"assignee,omitempty"`   // create only
+	Recurrence     *string `json:"recurrence,omitempty"` // on update, nil keeps the current rule and "" stops it
+	Priority       *int    `json:"priority,omitempty"`
+	Important      *bool   `json:"important,omitempty"`
+	IgnoreBlockers bool    `json:"ignore_blockers,omitempty"`
+}
+
+// BulkRequest represents the request payload for POST /tasks/bulk
+type BulkRequest struct {
+	Mode       string          `json:"mode,omitempty" binding:"omitempty,oneof=atomic per_item"`
+	Operations []BulkOperation `json:"operations" binding:"required,min=1,max=100,dive"`
+}
+
+// BulkResult reports the outcome of a single bulk operation
+type BulkResult struct {
+	Index   int           `json:"index"`
+	Op      string        `json:"op"`
+	TaskID  int64         `json:"task_id,omitempty"`
+	Success bool          `json:"success"`
+	Task    *TaskResponse `json:"task,omitempty"`
+	Error   string        `json:"error,omitempty"` // In the request locale
+	Code    string        `json:"code,omitempty"`  // Stable error code, as in error responses
+}
+
+// BulkResponse represents the response format for a bulk request
+type BulkResponse struct {
+	Mode      string       `json:"mode"`
+	Committed bool         `json:"committed"`
+	Succeeded int          `json:"succeeded"`
+	Failed    int          `json:"failed"`
+	Results   []BulkResult `json:"results"`
+}
+
+// Bulk operation kinds
+const (
+	BulkOpCreate       = "create"
+	BulkOpUpdate       = "update"
+	BulkOpDelete       = "delete"
+	BulkOpChangeState  = "change_state"
+	BulkOpMoveCategory = "move_category"
+)
+
+// Bulk execution modes
+const (
+	BulkModeAtomic  = "atomic"   // all operations commit or none do (default)
+	BulkModePerItem = "per_item" // each operation commits on its own
+)
+
+// Import and export formats
+const (
+	FormatCSV      = "csv"
+	FormatTodoTxt  = "todotxt"  // one task per line, see http://todotxt.org
+	FormatMarkdown = "markdown" // checklists grouped by category under headings
+)
+
+// ImportRow is one task of an imported file: a data row of a CSV file with the raw text of every
+// mapped column, or a line of a todo.txt or Markdown file
+type ImportRow struct {
+	Line        int // Line of the row in the file, the header of a CSV file being line 1
+	TaskText    string
+	EndDate     string
+	Category    string // Category name, created when no category has it
+	Priority    string
+	Important   string
+	Assignee    string
+	Recurrence  string
+	Tags        []string // Tag names, created when the user has no tag with the name
+	Completed   bool
+	CompletedAt string // YYYY-MM-DD, "" to complete the task at the time of the import
+}
+
+// ImportRequest represents the rows of a CSV file to import as new tasks
+type ImportRequest struct {
+	Rows   []ImportRow
+	DryRun bool // Only validate the rows
+}
+
+// ImportRowResult reports the outcome of a single imported row
+type ImportRowResult struct {
+	Line    int    `json:"line"`
+	Success bool   `json:"success"`
+	TaskID  int64  `json:"task_id,omitempty"` // Only set once committed
+	Error   string `json:"error,omitempty"`   // In the request locale
+	Code    string `json:"code,omitempty"`    // Stable error code, as in error responses
+}
+
+// ImportResult represents the response format for a CSV import
+type ImportResult struct {
+	DryRun        bool              `json:"dry_run"`
+	Committed     bool              `json:"committed"`
+	Succeeded     int               `json:"succeeded"`
+	Failed        int               `json:"failed"`
+	NewCategories []string          `json:"new_categories"` // Categories created, or to be created in a dry run
+	NewTags       []string          `json:"new_tags"`       // Tags created, or to be created in a dry run
+	Rows          []ImportRowResult `json:"rows"`
+}
+
+// TaskEvent records a change made to a task, as a list of field-level differences
+type TaskEvent struct {
+	ID        int64         `json:"id"`
+	TaskID    int64         `json:"task_id"`
+	UserID    int64         `json:"-"` // Owner of the task when the change was made
+	ActorID   int64         `json:"actor_id"`
+	Action    string        `json:"action"`
+	Changes   []FieldChange `json:"changes"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+// FieldChange is the old and new value of a single task field; values are null when unset
+type FieldChange struct {
+	Field string      `json:"field"`
+	Old   interface{} `json:"old"`
+	New   interface{} `json:"new"`
+}
+
+// Task event actions
+const (
+	EventCreated  = "created"
+	EventUpdated  = "updated"
+	EventDeleted  = "deleted"
+	EventRestored = "restored"
+)
+
+// Policies applied to the subtasks of a parent that is completed or deleted
+const (
+	ParentPolicyCascade = "cascade" // complete/delete all descendants along with the parent
+	ParentPolicyBlock   = "block"   // refuse while the parent has unfinished (or any, for delete) descendants
+)
+
+// Built-in workflow states, shared by every user. Users can add their own states
+// (see the states package); whether a state counts as finished comes from its is_done flag.
+const (
+	StateNotStarted = int64(1) // 'Not Started', the state of new tasks
 	StateInProgress = int64(2) // 'In Progress'
-	StateCompleted  = int64(3) // 'Completed'
+	StateCompleted  = int64(3) // 'Completed', done
 )
 
 // ToResponse converts a Task to TaskResponse
@@ -69,25 +479,34 @@ func (t *Task) ToResponse() TaskResponse {
 		CreationDate: t.CreationDate,
 		EndDate:      t.EndDate,
 		UserID:       t.UserID,
-		// State and Category will be populated separately when fetching related data
+		ParentID:     t.ParentID,
+		Recurrence:   t.Recurrence,
+		SeriesID:     t.SeriesID,
+		Occurrence:   t.Occurrence,
+		Priority:     t.Priority,
+		Important:    t.Important,
+		StartedAt:    t.StartedAt,
+		CompletedAt:  t.CompletedAt,
+		CreatedBy:    t.CreatedBy,
+		WorkspaceID:  t.WorkspaceID,
+		Position:     t.Position,
+		Version:      t.Version,
+		// State, Category and Assignee will be populated separately when fetching related data
 	}
 }
 
-// IsValidState checks if the provided state ID is valid
-func IsValidState(stateID int64) bool {
-	return stateID == StateNotStarted || stateID == StateInProgress || stateID == StateCompleted
+// columnOf returns the board column a task is in once it moves to stateID
+func columnOf(t *Task, stateID int64) Column {
+	return Column{StateID: stateID, UserID: t.UserID, WorkspaceID: t.WorkspaceID}
 }
 
-// GetStateName returns the English name for a state ID
-func GetStateName(stateID int64) string {
-	switch stateID {
-	case StateNotStarted:
-		return "Not Started"
-	case StateInProgress:
-		return "In Progress"
-	case StateCompleted:
-		return "Completed"
-	default:
-		return "Unknown"
-	}
-}
\ No newline at end of file
+// IsImportant reports whether a task counts as important in the Eisenhower matrix:
+// either explicitly flagged or of high priority
+func (t *TaskResponse) IsImportant() bool {
+	return t.Important || t.Priority >= PriorityHigh
+}
+
+// IsValidPriority checks if the provided priority is within range
+func IsValidPriority(priority int) bool {
+	return priority >= PriorityNone && priority <= PriorityHigh
+}
@@ -0,0 +1,133 @@
+package tasks
+
+import (
+	"errors"
+	"strings"
+)
+
+// Board positions are fractional indexes: base 62 fractions written without the leading "0.",
+// so that comparing two keys byte by byte orders them like the numbers they stand for.
+// A card can always be placed between two others by computing a key between theirs,
+// which means moving a card rewrites that card only.
+
+// positionDigits are the digits of a position key, in ascending byte order
+const positionDigits = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// MaxPositionLength is the key length above which a column is rebalanced
+const MaxPositionLength = 10
+
+// errPositionOrder is returned when the bounds of a new position are not in ascending order
+var errPositionOrder = errors.New("position bounds are not in ascending order")
+
+// positionBetween returns a key that sorts strictly between lower and upper.
+// An empty lower means the top of the column and an empty upper its bottom.
+func positionBetween(lower string, upper string) (string, error) {
+	if upper != "" && lower >= upper {
+		return "", errPositionOrder
+	}
+	if strings.HasSuffix(lower, "0") || strings.HasSuffix(upper, "0") {
+		return "", errPositionOrder
+	}
+
+	// Cards are mostly added at either end of a column; stepping one digit instead of
+	// halving the remaining space keeps those keys short for much longer
+	switch {
+	case lower != "" && upper == "":
+		for i := 0; i < len(lower); i++ {
+			if digit := strings.IndexByte(positionDigits, lower[i]); digit < len(positionDigits)-1 {
+				return lower[:i] + string(positionDigits[digit+1]), nil
+			}
+		}
+	case lower == "" && upper != "":
+		for i := 0; i < len(upper); i++ {
+			if digit := strings.IndexByte(positionDigits, upper[i]); digit > 1 {
+				return upper[:i] + string(positionDigits[digit-1]), nil
+			}
+		}
+	}
+
+	return midpoint(lower, upper), nil
+}
+
+// midpoint computes the key between lower and upper, which are valid keys with lower < upper.
+// A missing digit of lower counts as zero, and an empty upper as one past the last key.
+func midpoint(lower string, upper string) string {
+	if upper != "" {
+		// Keep the prefix both keys share and split the rest
+		n := 0
+		for n < len(upper) && digitAt(lower, n) == upper[n] {
+			n++
+		}
+		if n > 0 {
+			rest := ""
+			if n < len(lower) {
+				rest = lower[n:]
+			}
+			return upper[:n] + midpoint(rest, upper[n:])
+		}
+	}
+
+	low := 0
+	if lower != "" {
+		low = strings.IndexByte(positionDigits, lower[0])
+	}
+	high := len(positionDigits)
+	if upper != "" {
+		high = strings.IndexByte(positionDigits, upper[0])
+	}
+
+	// A digit fits between the first digits of the bounds
+	if high-low > 1 {
+		return string(positionDigits[(low+high+1)/2])
+	}
+
+	// The first digits are consecutive: the first digit of upper alone is already
+	// smaller than upper when more digits follow it
+	if len(upper) > 1 {
+		return upper[:1]
+	}
+
+	rest := ""
+	if len(lower) > 1 {
+		rest = lower[1:]
+	}
+	return string(positionDigits[low]) + midpoint(rest, "")
+}
+
+// digitAt returns the digit of key at index i, zero past its end
+func digitAt(key string, i int) byte {
+	if i < len(key) {
+		return key[i]
+	}
+	return positionDigits[0]
+}
+
+// spreadPositions returns count ascending keys spread evenly over the whole key space,
+// all short enough to leave room for many moves between any two of them
+func spreadPositions(count int) []string {
+	// Use enough digits to keep a gap of at least len(positionDigits) between neighbors
+	width, space := 1, len(positionDigits)
+	for space/(count+1) < len(positionDigits) {
+		width++
+		space *= len(positionDigits)
+	}
+
+	keys := make([]string, count)
+	step := space / (count + 1)
+	for i := range keys {
+		keys[i] = encodePosition((i+1)*step, width)
+	}
+
+	return keys
+}
+
+// encodePosition writes value as a key of width digits, dropping the trailing zeros
+func encodePosition(value int, width int) string {
+	key := make([]byte, width)
+	for i := width - 1; i >= 0; i-- {
+		key[i] = positionDigits[value%len(positionDigits)]
+		value /= len(positionDigits)
+	}
+
+	return strings.TrimRight(string(key), "0")
+}
@@ -0,0 +1,255 @@
+package tasks
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"backend/root/internal/i18n"
+)
+
+// QuickAdd creates a task from a single line such as "Pay rent tomorrow #Home !high". The
+// line is read by parseQuickAdd, the category is looked up by name in the current space and
+// the task is created with Create, so the usual validation applies.
+func (s *service) QuickAdd(ctx context.Context, userID int64, req QuickAddRequest) (*QuickAddResult, error) {
+	parsed := parseQuickAdd(req.Text, time.Now().UTC().Truncate(24*time.Hour))
+
+	create := CreateTaskRequest{
+		TaskText: parsed.TaskText,
+		EndDate:  parsed.EndDate,
+		Priority: parsed.Priority,
+	}
+
+	if parsed.Category != "" {
+		// "#Home_Office" stands for "Home Office", unless a category has the name as written
+		spaced := fromTodoTxtName(parsed.Category)
+		ids, err := s.repo.GetCategoryIDs(ctx, []string{parsed.Category, spaced})
+		if err != nil {
+			return nil, err
+		}
+		id, ok := ids[strings.ToLower(parsed.Category)]
+		if !ok {
+			id, ok = ids[strings.ToLower(spaced)]
+		}
+		if !ok {
+			return nil, i18n.NewError("task_quick_category_missing", parsed.Category)
+		}
+		parsed.CategoryID = &id
+		create.CategoryID = &id
+	}
+
+	task, err := s.Create(ctx, userID, create)
+	if err != nil {
+		return nil, err
+	}
+
+	return &QuickAddResult{Task: task, Parsed: parsed}, nil
+}
+
+// quickPriorities maps the priority markers of quick-add, in English and Spanish, to priorities
+var quickPriorities = map[string]int{
+	"!high": PriorityHigh, "!alta": PriorityHigh, "!3": PriorityHigh,
+	"!medium": PriorityMedium, "!media": PriorityMedium, "!2": PriorityMedium,
+	"!low": PriorityLow, "!baja": PriorityLow, "!1": PriorityLow,
+}
+
+// quickDate is a date phrase of quick-add, as normalized words
+type quickDate struct {
+	words []string
+	date  func(today time.Time) time.Time
+}
+
+// quickDatePrefixes may precede any date phrase, e.g. "by friday" or "para mañana"
+var quickDatePrefixes = [][]string{{"on"}, {"by"}, {"due"}, {"para", "el"}, {"para"}, {"el"}}
+
+// quickUnits maps the units of "in 3 days" and "en 3 días" to a number of days or months
+var quickUnits = map[string]struct{ days, months int }{
+	"day": {1, 0}, "days": {1, 0}, "dia": {1, 0}, "dias": {1, 0},
+	"week": {7, 0}, "weeks": {7, 0}, "semana": {7, 0}, "semanas": {7, 0},
+	"month": {0, 1}, "months": {0, 1}, "mes": {0, 1}, "meses": {0, 1},
+	"year": {0, 12}, "years": {0, 12}, "ano": {0, 12}, "anos": {0, 12},
+}
+
+// quickDates lists the date phrases of quick-add, longest first
+var quickDates = buildQuickDates()
+
+// buildQuickDates lists the relative dates understood in English and Spanish: today,
+// tomorrow, the day after tomorrow, weekdays, next week and next month
+func buildQuickDates() []quickDate {
+	days := func(n int) func(time.Time) time.Time {
+		return func(today time.Time) time.Time { return today.AddDate(0, 0, n) }
+	}
+	nextMonday := func(today time.Time) time.Time { return nextWeekday(today, time.Monday) }
+	nextMonth := func(today time.Time) time.Time {
+		return time.Date(today.Year(), today.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+	}
+
+	dates := []quickDate{
+		{[]string{"today"}, days(0)},
+		{[]string{"hoy"}, days(0)},
+		{[]string{"tomorrow"}, days(1)},
+		{[]string{"manana"}, days(1)},
+		{[]string{"day", "after", "tomorrow"}, days(2)},
+		{[]string{"pasado", "manana"}, days(2)},
+		{[]string{"next", "week"}, nextMonday},
+		{[]string{"proxima", "semana"}, nextMonday},
+		{[]string{"la", "proxima", "semana"}, nextMonday},
+		{[]string{"semana", "que", "viene"}, nextMonday},
+		{[]string{"la", "semana", "que", "viene"}, nextMonday},
+		{[]string{"next", "month"}, nextMonth},
+		{[]string{"proximo", "mes"}, nextMonth},
+		{[]string{"mes", "que", "viene"}, nextMonth},
+	}
+
+	weekdays := map[time.Weekday][]string{
+		time.Monday:    {"monday", "lunes"},
+		time.Tuesday:   {"tuesday", "martes"},
+		time.Wednesday: {"wednesday", "miercoles"},
+		time.Thursday:  {"thursday", "jueves"},
+		time.Friday:    {"friday", "viernes"},
+		time.Saturday:  {"saturday", "sabado"},
+		time.Sunday:    {"sunday", "domingo"},
+	}
+	for weekday, names := range weekdays {
+		weekday := weekday
+		date := func(today time.Time) time.Time { return nextWeekday(today, weekday) }
+		english, spanish := names[0], names[1]
+		dates = append(dates,
+			quickDate{[]string{english}, date},
+			quickDate{[]string{"next", english}, date},
+			quickDate{[]string{"this", english}, date},
+			quickDate{[]string{spanish}, date},
+			quickDate{[]string{"este", spanish}, date},
+			quickDate{[]string{"proximo", spanish}, date},
+			quickDate{[]string{spanish, "que", "viene"}, date},
+		)
+	}
+
+	sort.SliceStable(dates, func(i, j int) bool { return len(dates[i].words) > len(dates[j].words) })
+	return dates
+}
+
+// parseQuickAdd reads a quick-add line. The first #word is the category, the first priority
+// marker the priority and the first date phrase the end date, relative to today; the other
+// words are the task text. Date phrases may be English or Spanish, with or without accents.
+func parseQuickAdd(text string, today time.Time) QuickAddParse {
+	original := strings.Fields(text)
+	words := make([]string, len(original))
+	for i, word := range original {
+		words[i] = normalizeQuickWord(word)
+	}
+
+	var parsed QuickAddParse
+	var rest []string
+	for i := 0; i < len(original); i++ {
+		word := strings.TrimRight(original[i], ",.;:")
+
+		if parsed.Category == "" && len(word) > 1 && word[0] == '#' {
+			parsed.Category = word[1:]
+			continue
+		}
+		if priority, ok := quickPriorities[normalizeQuickWord(word)]; ok && parsed.Priority == nil {
+			parsed.Priority = &priority
+			continue
+		}
+		if parsed.EndDate == "" {
+			if n, date, ok := matchQuickDate(words[i:], today); ok {
+				parsed.DatePhrase = strings.TrimRight(strings.Join(original[i:i+n], " "), ",.;:")
+				parsed.EndDate = date.Format("2006-01-02")
+				i += n - 1
+				continue
+			}
+		}
+
+		rest = append(rest, original[i])
+	}
+	parsed.TaskText = strings.Join(rest, " ")
+
+	return parsed
+}
+
+// matchQuickDate reads a date phrase at the start of words, optionally after a prefix such
+// as "by", and returns the number of words it takes
+func matchQuickDate(words []string, today time.Time) (int, time.Time, bool) {
+	if n, date, ok := matchQuickDatePhrase(words, today); ok {
+		return n, date, true
+	}
+
+	for _, prefix := range quickDatePrefixes {
+		if hasQuickWords(words, prefix) {
+			if n, date, ok := matchQuickDatePhrase(words[len(prefix):], today); ok {
+				return len(prefix) + n, date, true
+			}
+		}
+	}
+
+	return 0, time.Time{}, false
+}
+
+// matchQuickDatePhrase reads a date phrase without prefix: an ISO date, "in 3 days" or
+// "en 3 días" (also "dentro de"), or one of quickDates
+func matchQuickDatePhrase(words []string, today time.Time) (int, time.Time, bool) {
+	if len(words) == 0 {
+		return 0, time.Time{}, false
+	}
+
+	if date, err := time.Parse("2006-01-02", words[0]); err == nil {
+		return 1, date, true
+	}
+
+	for _, lead := range [][]string{{"in"}, {"en"}, {"dentro", "de"}} {
+		if !hasQuickWords(words, lead) || len(words) < len(lead)+2 {
+			continue
+		}
+		count, unit := words[len(lead)], words[len(lead)+1]
+		n, err := strconv.Atoi(count)
+		if count == "a" || count == "an" || count == "un" || count == "una" {
+			n, err = 1, nil
+		}
+		step, ok := quickUnits[unit]
+		if err != nil || !ok || n < 0 || n > 1000 {
+			continue
+		}
+		return len(lead) + 2, today.AddDate(0, n*step.months, n*step.days), true
+	}
+
+	for _, phrase := range quickDates {
+		if hasQuickWords(words, phrase.words) {
+			return len(phrase.words), phrase.date(today), true
+		}
+	}
+
+	return 0, time.Time{}, false
+}
+
+// hasQuickWords reports whether words starts with prefix
+func hasQuickWords(words []string, prefix []string) bool {
+	if len(words) < len(prefix) {
+		return false
+	}
+	for i, word := range prefix {
+		if words[i] != word {
+			return false
+		}
+	}
+	return true
+}
+
+// quickAccents strips the Spanish accents, so "mañana" and "manana" read the same
+var quickAccents = strings.NewReplacer("á", "a", "é", "e", "í", "i", "ó", "o", "ú", "u", "ü", "u", "ñ", "n")
+
+// normalizeQuickWord lowercases a word, strips its accents and trailing punctuation
+func normalizeQuickWord(word string) string {
+	return strings.TrimRight(quickAccents.Replace(strings.ToLower(word)), ",.;:?")
+}
+
+// nextWeekday returns the first given day of the week after today
+func nextWeekday(today time.Time, weekday time.Weekday) time.Time {
+	days := (int(weekday) - int(today.Weekday()) + 7) % 7
+	if days == 0 {
+		days = 7
+	}
+	return today.AddDate(0, 0, days)
+}
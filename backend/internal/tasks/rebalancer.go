@@ -0,0 +1,67 @@
+package tasks
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Rebalancer periodically rewrites the positions of the board columns whose keys grew too long
+// from repeated moves, or that hold cards without a position or sharing one
+type Rebalancer struct {
+	repo     Repository
+	interval time.Duration
+}
+
+// NewRebalancer creates a new board rebalancer
+func NewRebalancer(repo Repository, interval time.Duration) *Rebalancer {
+	if interval <= 0 {
+		interval = time.Hour
+	}
+
+	return &Rebalancer{
+		repo:     repo,
+		interval: interval,
+	}
+}
+
+// Run rebalances the columns that need it every interval until ctx is cancelled
+func (r *Rebalancer) Run(ctx context.Context) {
+	log.Printf("Board rebalancer started (every %s)", r.interval)
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := r.Rebalance(ctx); err != nil {
+			log.Printf("Board rebalancer: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			log.Printf("Board rebalancer stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Rebalance rewrites every column with a position longer than MaxPositionLength, a missing
+// position or a position shared by two cards
+func (r *Rebalancer) Rebalance(ctx context.Context) error {
+	columns, err := r.repo.GetUnbalancedColumns(ctx, MaxPositionLength)
+	if err != nil {
+		return err
+	}
+
+	for _, column := range columns {
+		if err := r.repo.RebalanceColumn(ctx, column); err != nil {
+			return err
+		}
+	}
+
+	if len(columns) > 0 {
+		log.Printf("Board rebalancer: rebalanced %d columns", len(columns))
+	}
+
+	return nil
+}
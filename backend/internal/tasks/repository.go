@@ -3,102 +3,186 @@ package tasks
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"math"
+	"strconv"
+	"strings"
 	"time"
+
+	"github.com/lib/pq"
+
+	"backend/root/internal/i18n"
+	"backend/root/internal/workspaces"
 )
 
 // Repository defines the interface for task data persistence
 type Repository interface {
-	Create(ctx context.Context, userID int64, taskText string, endDate *time.Time, categoryID *int64) (*Task, error)
+	Create(ctx context.Context, task *Task) (*Task, error)
 	GetByID(ctx context.Context, id int64) (*Task, error)
-	GetAllByUser(ctx context.Context, userID int64, categoryID *int64, stateID *int64) ([]*TaskResponse, error)
+	GetAllByUser(ctx context.Context, userID int64, filter TaskFilter) ([]*TaskResponse, error)
 	GetByIDWithDetails(ctx context.Context, id int64) (*TaskResponse, error)
-	Update(ctx context.Context, id int64, taskText string, categoryID *int64, endDate *time.Time, stateID *int64) (*Task, error)
-	Delete(ctx context.Context, id int64) error
+	GetChildren(ctx context.Context, parentID int64) ([]*TaskResponse, error)
+	GetSubtree(ctx context.Context, rootID int64) ([]*TaskResponse, error)
+	GetProgress(ctx context.Context, id int64) (*TaskProgress, error)
+	IsDescendant(ctx context.Context, ancestorID int64, id int64) (bool, error)
+	GetDescendants(ctx context.Context, id int64) ([]*Task, error)
+	CompleteDescendants(ctx context.Context, id int64) ([]int64, error)
+	Update(ctx context.Context, task *Task) (*Task, error)
+	Delete(ctx context.Context, id int64, version *int64) error
+	GetDeletedByID(ctx context.Context, id int64) (*Task, error)
+	GetTrash(ctx context.Context, userID int64) ([]*TaskResponse, error)
+	Restore(ctx context.Context, id int64) ([]int64, error)
+	Purge(ctx context.Context, before time.Time) (int64, error)
+	GetRoles(ctx context.Context, ids []int64, userID int64) (map[int64]string, error)
+	GetRole(ctx context.Context, id int64, userID int64) (string, error)
+	GetShared(ctx context.Context, userID int64) ([]*TaskResponse, error)
+	IsWorkspaceMember(ctx context.Context, workspaceID int64, userID int64) (bool, error)
+	GetState(ctx context.Context, id int64, userID int64) (*StateInfo, error)
+	AddDependency(ctx context.Context, taskID int64, blockerID int64) error
+	RemoveDependency(ctx context.Context, taskID int64, blockerID int64) error
+	DependsOn(ctx context.Context, taskID int64, blockerID int64) (bool, error)
+	LockDependencies(ctx context.Context, userID int64) error
+	GetBlockers(ctx context.Context, taskID int64) ([]BlockerInfo, error)
+	GetSeries(ctx context.Context, seriesID int64) ([]*TaskResponse, error)
+	GetSeriesTasks(ctx context.Context, seriesID int64) ([]*Task, error)
+	UpdateSeries(ctx context.Context, seriesID int64, taskText string, categoryID *int64, recurrence string) ([]int64, error)
+	StopSeries(ctx context.Context, seriesID int64) error
+	CreateEvent(ctx context.Context, event *TaskEvent) error
+	GetEvents(ctx context.Context, taskID int64, userID int64) ([]*TaskEvent, error)
+	LastPosition(ctx context.Context, column Column) (string, error)
+	HasTasksBetween(ctx context.Context, column Column, lower string, upper string, excludeID int64) (bool, error)
+	RebalanceColumn(ctx context.Context, column Column) error
+	GetUnbalancedColumns(ctx context.Context, maxLength int) ([]Column, error)
+	GetBoardStates(ctx context.Context, userID int64, stateIDs []int64) ([]*StateInfo, error)
+	GetCategoryIDs(ctx context.Context, names []string) (map[string]int64, error)
+	CreateCategory(ctx context.Context, name string) (int64, error)
+	GetTagIDs(ctx context.Context, userID int64, names []string) (map[string]int64, error)
+	CreateTag(ctx context.Context, userID int64, name string) (int64, error)
+	AttachTag(ctx context.Context, taskID int64, tagID int64) error
+	GetStats(ctx context.Context, userID int64, from time.Time, to time.Time, interval string) (*Stats, error)
+	WithTx(ctx context.Context, fn func(repo Repository) error) error
+}
+
+// dbtx is the subset of *sql.DB and *sql.Tx used by the repository, so the same
+// queries can run either directly on the pool or inside a transaction
+type dbtx interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
 }
 
+// rowScanner is implemented by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// taskColumns lists the tasks table columns in the order expected by scanTask
+const taskColumns = `id, task_text, creation_date, end_date, id_state, id_category, id_user, parent_id,
+	recurrence, series_id, occurrence, priority, important, started_at, completed_at, created_by, assignee_id, workspace_id, position, version`
+
+// doneStates selects the IDs of the workflow states flagged as done
+const doneStates = `(SELECT id FROM states WHERE is_done)`
+
+// taskDetailsSelect selects a task joined with its state and category, in the order expected by scanTaskResponse
+const taskDetailsSelect = `
+		SELECT
+			t.id, t.task_text, t.creation_date, t.end_date, t.id_user, t.parent_id,
+			t.recurrence, t.series_id, t.occurrence, t.priority, t.important, t.started_at, t.completed_at,
+			t.created_by, a.id as assignee_id, a.username as assignee_username, t.workspace_id,
+			(SELECT COUNT(*) FROM task_comments tc WHERE tc.task_id = t.id) as comment_count,
+			s.id as state_id, s.description as state_description, COALESCE(s.is_done, FALSE) as state_is_done,
+			c.id as category_id, c.name as category_name, c.description as category_description,
+			t.position, t.deleted_at, t.version
+		FROM tasks t
+		LEFT JOIN states s ON t.id_state = s.id
+		LEFT JOIN categories c ON t.id_category = c.id AND c.deleted_at IS NULL
+		LEFT JOIN users a ON t.assignee_id = a.id`
+
 // PostgresRepository implements Repository using PostgreSQL
 type PostgresRepository struct {
-	db *sql.DB
+	db   dbtx
+	pool *sql.DB
 }
 
 // NewPostgresRepository creates a new PostgreSQL repository
 func NewPostgresRepository(db *sql.DB) Repository {
-	return &PostgresRepository{db: db}
+	return &PostgresRepository{db: db, pool: db}
 }
 
-// Create creates a new task (defaults to "Not Started" state)
-func (r *PostgresRepository) Create(ctx context.Context, userID int64, taskText string, endDate *time.Time, categoryID *int64) (*Task, error) {
-	query := `
-		INSERT INTO tasks (task_text, end_date, id_state, id_category, id_user) 
-		VALUES ($1, $2, $3, $4, $5) 
-		RETURNING id, task_text, creation_date, end_date, id_state, id_category, id_user`
-	
-	var task Task
-	err := r.db.QueryRowContext(ctx, query, taskText, endDate, StateNotStarted, categoryID, userID).Scan(
-		&task.ID, &task.TaskText, &task.CreationDate, &task.EndDate, &task.StateID, &task.CategoryID, &task.UserID,
-	)
+// WithTx runs fn with a repository bound to a single database transaction.
+// The transaction is committed if fn returns nil and rolled back otherwise.
+// Calls made on a repository that is already inside a transaction reuse it.
+func (r *PostgresRepository) WithTx(ctx context.Context, fn func(repo Repository) error) error {
+	if _, inTx := r.db.(*sql.Tx); inTx {
+		return fn(r)
+	}
+
+	tx, err := r.pool.BeginTx(ctx, nil)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	
-	return &task, nil
+
+	if err := fn(&PostgresRepository{db: tx, pool: r.pool}); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
 }
 
-// GetByID retrieves a task by ID
-func (r *PostgresRepository) GetByID(ctx context.Context, id int64) (*Task, error) {
-	query := `SELECT id, task_text, creation_date, end_date, id_state, id_category, id_user FROM tasks WHERE id = $1`
-	
+// scanTask scans a row selected with taskColumns
+func scanTask(row rowScanner) (*Task, error) {
 	var task Task
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
+	err := row.Scan(
 		&task.ID, &task.TaskText, &task.CreationDate, &task.EndDate, &task.StateID, &task.CategoryID, &task.UserID,
+		&task.ParentID, &task.Recurrence, &task.SeriesID, &task.Occurrence, &task.Priority, &task.Important,
+		&task.StartedAt, &task.CompletedAt, &task.CreatedBy, &task.AssigneeID, &task.WorkspaceID, &task.Position, &task.Version,
 	)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, nil
-		}
 		return nil, err
 	}
-	
+
 	return &task, nil
 }
 
-// GetByIDWithDetails retrieves a task by ID with its related state and category information
-func (r *PostgresRepository) GetByIDWithDetails(ctx context.Context, id int64) (*TaskResponse, error) {
-	query := `
-		SELECT 
-			t.id, t.task_text, t.creation_date, t.end_date, t.id_user,
-			s.id as state_id, s.description as state_description,
-			c.id as category_id, c.name as category_name, c.description as category_description
-		FROM tasks t
-		LEFT JOIN states s ON t.id_state = s.id
-		LEFT JOIN categories c ON t.id_category = c.id
-		WHERE t.id = $1`
-	
+// scanTaskResponse scans a row selected with taskDetailsSelect
+func scanTaskResponse(row rowScanner) (*TaskResponse, error) {
 	var resp TaskResponse
 	var stateID, categoryID sql.NullInt64
-	var stateDesc, categoryName, categoryDesc sql.NullString
-	
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&resp.ID, &resp.TaskText, &resp.CreationDate, &resp.EndDate, &resp.UserID,
-		&stateID, &stateDesc,
+	var assigneeID sql.NullInt64
+	var stateDesc, categoryName, categoryDesc, assigneeName sql.NullString
+	var stateIsDone bool
+
+	err := row.Scan(
+		&resp.ID, &resp.TaskText, &resp.CreationDate, &resp.EndDate, &resp.UserID, &resp.ParentID,
+		&resp.Recurrence, &resp.SeriesID, &resp.Occurrence, &resp.Priority, &resp.Important,
+		&resp.StartedAt, &resp.CompletedAt, &resp.CreatedBy, &assigneeID, &assigneeName, &resp.WorkspaceID, &resp.CommentCount,
+		&stateID, &stateDesc, &stateIsDone,
 		&categoryID, &categoryName, &categoryDesc,
+		&resp.Position, &resp.DeletedAt, &resp.Version,
 	)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, nil
-		}
 		return nil, err
 	}
-	
+
 	// Populate state info if available
 	if stateID.Valid {
 		resp.State = &StateInfo{
 			ID:          stateID.Int64,
 			Description: stateDesc.String,
+			IsDone:      stateIsDone,
 		}
 	}
-	
+
+	// Populate assignee info if the task is assigned
+	if assigneeID.Valid {
+		resp.Assignee = &AssigneeInfo{
+			ID:       assigneeID.Int64,
+			Username: assigneeName.String,
+		}
+	}
+
 	// Populate category info if available
 	if categoryID.Valid {
 		resp.Category = &CategoryInfo{
@@ -107,121 +191,1207 @@ func (r *PostgresRepository) GetByIDWithDetails(ctx context.Context, id int64) (
 			Description: categoryDesc.String,
 		}
 	}
-	
+
 	return &resp, nil
 }
 
-// GetAllByUser retrieves all tasks for a user with optional filtering by category and state
-func (r *PostgresRepository) GetAllByUser(ctx context.Context, userID int64, categoryID *int64, stateID *int64) ([]*TaskResponse, error) {
+// queryTaskResponses runs a taskDetailsSelect based query and returns the tasks with their progress, blockers and tags attached
+func (r *PostgresRepository) queryTaskResponses(ctx context.Context, query string, args ...interface{}) ([]*TaskResponse, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tasks []*TaskResponse
+	for rows.Next() {
+		resp, err := scanTaskResponse(rows)
+		if err != nil {
+			return nil, err
+		}
+		if resp.State != nil {
+			resp.State.Description = i18n.StateName(i18n.FromContext(ctx), resp.State.ID, resp.State.Description)
+		}
+		tasks = append(tasks, resp)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := r.attachProgress(ctx, tasks); err != nil {
+		return nil, err
+	}
+	if err := r.attachBlockers(ctx, tasks); err != nil {
+		return nil, err
+	}
+	if err := r.attachTags(ctx, tasks); err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+// Create creates a new task (defaults to "Not Started" state).
+// Without a position the task goes to the bottom of its board column.
+func (r *PostgresRepository) Create(ctx context.Context, task *Task) (*Task, error) {
 	query := `
-		SELECT 
-			t.id, t.task_text, t.creation_date, t.end_date, t.id_user,
-			s.id as state_id, s.description as state_description,
-			c.id as category_id, c.name as category_name, c.description as category_description
-		FROM tasks t
-		LEFT JOIN states s ON t.id_state = s.id
-		LEFT JOIN categories c ON t.id_category = c.id
-		WHERE t.id_user = $1`
-	
+		INSERT INTO tasks (task_text, end_date, id_state, id_category, id_user, parent_id,
+			recurrence, series_id, occurrence, priority, important, started_at, completed_at, created_by, assignee_id, workspace_id, position)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
+		RETURNING ` + taskColumns
+
+	stateID := StateNotStarted
+	if task.StateID != nil {
+		stateID = *task.StateID
+	}
+	occurrence := task.Occurrence
+	if occurrence < 1 {
+		occurrence = 1
+	}
+	position := task.Position
+	if position == nil {
+		last, err := r.LastPosition(ctx, columnOf(task, stateID))
+		if err != nil {
+			return nil, err
+		}
+		key, err := positionBetween(last, "")
+		if err != nil {
+			return nil, err
+		}
+		position = &key
+	}
+
+	return scanTask(r.db.QueryRowContext(ctx, query,
+		task.TaskText, task.EndDate, stateID, task.CategoryID, task.UserID, task.ParentID,
+		task.Recurrence, task.SeriesID, occurrence, task.Priority, task.Important, task.StartedAt, task.CompletedAt,
+		task.CreatedBy, task.AssigneeID, task.WorkspaceID, position,
+	))
+}
+
+// GetByID retrieves a task by ID
+func (r *PostgresRepository) GetByID(ctx context.Context, id int64) (*Task, error) {
+	query := `SELECT ` + taskColumns + ` FROM tasks WHERE id = $1 AND deleted_at IS NULL AND ` + workspaces.Condition(ctx, "workspace_id")
+
+	task, err := scanTask(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return task, nil
+}
+
+// GetByIDWithDetails retrieves a task by ID with its related state and category information
+func (r *PostgresRepository) GetByIDWithDetails(ctx context.Context, id int64) (*TaskResponse, error) {
+	tasks, err := r.queryTaskResponses(ctx, taskDetailsSelect+` WHERE t.id = $1 AND t.deleted_at IS NULL AND `+workspaces.Condition(ctx, "t.workspace_id"), id)
+	if err != nil {
+		return nil, err
+	}
+	if len(tasks) == 0 {
+		return nil, nil
+	}
+
+	return tasks[0], nil
+}
+
+// GetAllByUser retrieves all tasks for a user with optional filtering and sorting
+func (r *PostgresRepository) GetAllByUser(ctx context.Context, userID int64, filter TaskFilter) ([]*TaskResponse, error) {
+	// The user's own tasks, and in a workspace every task of the workspace; tasks assigned
+	// to the user are listed whoever owns them
+	query := taskDetailsSelect + ` WHERE (t.id_user = $1 OR t.workspace_id IS NOT NULL) AND t.deleted_at IS NULL AND ` +
+		workspaces.Condition(ctx, "t.workspace_id")
+	if filter.AssignedToMe {
+		query = taskDetailsSelect + ` WHERE t.assignee_id = $1 AND t.deleted_at IS NULL AND ` + workspaces.Condition(ctx, "t.workspace_id")
+	}
+	if filter.CreatedByMe {
+		query += ` AND t.created_by = $1`
+	}
+
 	var args []interface{}
 	args = append(args, userID)
 	argIndex := 2
-	
+
 	// Add category filter if provided
-	if categoryID != nil {
+	if filter.CategoryID != nil {
 		query += fmt.Sprintf(" AND t.id_category = $%d", argIndex)
-		args = append(args, *categoryID)
+		args = append(args, *filter.CategoryID)
 		argIndex++
 	}
-	
+
 	// Add state filter if provided
-	if stateID != nil {
+	if filter.StateID != nil {
 		query += fmt.Sprintf(" AND t.id_state = $%d", argIndex)
-		args = append(args, *stateID)
+		args = append(args, *filter.StateID)
 		argIndex++
 	}
-	
-	query += " ORDER BY t.creation_date DESC"
-	
+
+	// Add tag filter if provided: any of the tags, or all of them
+	if len(filter.TagIDs) > 0 {
+		tagIDs := uniqueIDs(filter.TagIDs)
+		if filter.TagMode == TagModeAll {
+			query += fmt.Sprintf(" AND (SELECT COUNT(*) FROM task_tags tt WHERE tt.task_id = t.id AND tt.tag_id = ANY($%d)) = $%d", argIndex, argIndex+1)
+			args = append(args, pq.Array(tagIDs), len(tagIDs))
+			argIndex += 2
+		} else {
+			query += fmt.Sprintf(" AND EXISTS (SELECT 1 FROM task_tags tt WHERE tt.task_id = t.id AND tt.tag_id = ANY($%d))", argIndex)
+			args = append(args, pq.Array(tagIDs))
+			argIndex++
+		}
+	}
+
+	// Exclude tasks in a done state if requested
+	if filter.OnlyOpen {
+		query += " AND (t.id_state IS NULL OR t.id_state NOT IN " + doneStates + ")"
+	}
+
+	switch filter.Sort {
+	case SortPriority:
+		query += " ORDER BY t.priority DESC, t.end_date ASC NULLS LAST, t.creation_date DESC, t.id DESC"
+	case SortEndDate:
+		query += " ORDER BY t.end_date ASC NULLS LAST, t.priority DESC, t.id DESC"
+	case SortPosition:
+		query += " ORDER BY t.position ASC NULLS LAST, t.id ASC"
+	default:
+		query += " ORDER BY t.creation_date DESC"
+	}
+
+	return r.queryTaskResponses(ctx, query, args...)
+}
+
+// GetChildren retrieves the direct subtasks of a task
+func (r *PostgresRepository) GetChildren(ctx context.Context, parentID int64) ([]*TaskResponse, error) {
+	return r.queryTaskResponses(ctx, taskDetailsSelect+` WHERE t.parent_id = $1 AND t.deleted_at IS NULL AND `+workspaces.Condition(ctx, "t.workspace_id")+` ORDER BY t.id`, parentID)
+}
+
+// GetSubtree retrieves a task together with all of its descendants
+func (r *PostgresRepository) GetSubtree(ctx context.Context, rootID int64) ([]*TaskResponse, error) {
+	query := `
+		WITH RECURSIVE subtree AS (
+			SELECT id FROM tasks WHERE id = $1 AND deleted_at IS NULL AND ` + workspaces.Condition(ctx, "workspace_id") + `
+			UNION
+			SELECT t.id FROM tasks t JOIN subtree st ON t.parent_id = st.id WHERE t.deleted_at IS NULL
+		)` + taskDetailsSelect + ` WHERE t.id IN (SELECT id FROM subtree) ORDER BY t.id`
+
+	return r.queryTaskResponses(ctx, query, rootID)
+}
+
+// GetProgress returns the completion roll-up of a task's descendants, or nil if it has none
+func (r *PostgresRepository) GetProgress(ctx context.Context, id int64) (*TaskProgress, error) {
+	progress, err := r.progressByRoot(ctx, []int64{id})
+	if err != nil {
+		return nil, err
+	}
+
+	return progress[id], nil
+}
+
+// IsDescendant reports whether id is somewhere below ancestorID in the task hierarchy
+func (r *PostgresRepository) IsDescendant(ctx context.Context, ancestorID int64, id int64) (bool, error) {
+	query := `
+		WITH RECURSIVE descendants AS (
+			SELECT id FROM tasks WHERE parent_id = $1 AND deleted_at IS NULL AND ` + workspaces.Condition(ctx, "workspace_id") + `
+			UNION
+			SELECT t.id FROM tasks t JOIN descendants d ON t.parent_id = d.id WHERE t.deleted_at IS NULL
+		)
+		SELECT EXISTS(SELECT 1 FROM descendants WHERE id = $2)`
+
+	var exists bool
+	if err := r.db.QueryRowContext(ctx, query, ancestorID, id).Scan(&exists); err != nil {
+		return false, err
+	}
+
+	return exists, nil
+}
+
+// GetDescendants retrieves every descendant of a task, locking the rows for the rest of the transaction
+func (r *PostgresRepository) GetDescendants(ctx context.Context, id int64) ([]*Task, error) {
+	query := `
+		WITH RECURSIVE descendants AS (
+			SELECT id FROM tasks WHERE parent_id = $1 AND deleted_at IS NULL AND ` + workspaces.Condition(ctx, "workspace_id") + `
+			UNION
+			SELECT t.id FROM tasks t JOIN descendants d ON t.parent_id = d.id WHERE t.deleted_at IS NULL
+		)
+		SELECT ` + taskColumns + ` FROM tasks WHERE id IN (SELECT id FROM descendants) ORDER BY id FOR UPDATE`
+
+	return r.queryTasks(ctx, query, id)
+}
+
+// queryTasks runs a taskColumns based query
+func (r *PostgresRepository) queryTasks(ctx context.Context, query string, args ...interface{}) ([]*Task, error) {
 	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	
-	var tasks []*TaskResponse
+
+	var tasks []*Task
 	for rows.Next() {
-		var resp TaskResponse
-		var stateID, categoryID sql.NullInt64
-		var stateDesc, categoryName, categoryDesc sql.NullString
-		
-		err := rows.Scan(
-			&resp.ID, &resp.TaskText, &resp.CreationDate, &resp.EndDate, &resp.UserID,
-			&stateID, &stateDesc,
-			&categoryID, &categoryName, &categoryDesc,
-		)
+		task, err := scanTask(rows)
 		if err != nil {
 			return nil, err
 		}
-		
-		// Populate state info if available
-		if stateID.Valid {
-			resp.State = &StateInfo{
-				ID:          stateID.Int64,
-				Description: stateDesc.String,
-			}
-		}
-		
-		// Populate category info if available
-		if categoryID.Valid {
-			resp.Category = &CategoryInfo{
-				ID:          categoryID.Int64,
-				Name:        categoryName.String,
-				Description: categoryDesc.String,
-			}
-		}
-		
-		tasks = append(tasks, &resp)
+		tasks = append(tasks, task)
 	}
-	
-	return tasks, nil
+
+	return tasks, rows.Err()
 }
 
-// Update updates an existing task's text, end date, and state
-func (r *PostgresRepository) Update(ctx context.Context, id int64, taskText string, categoryID *int64, endDate *time.Time, stateID *int64) (*Task, error) {
+// CompleteDescendants moves every descendant of a task that is not in a done state to Completed
+// and returns the IDs of the tasks it changed
+func (r *PostgresRepository) CompleteDescendants(ctx context.Context, id int64) ([]int64, error) {
 	query := `
-		UPDATE tasks 
-		SET task_text = $1, id_category = $2, end_date = $3, id_state = $4 
-		WHERE id = $5 
-		RETURNING id, task_text, creation_date, end_date, id_state, id_category, id_user`
-	
-	var task Task
-	err := r.db.QueryRowContext(ctx, query, taskText, categoryID, endDate, stateID, id).Scan(
-		&task.ID, &task.TaskText, &task.CreationDate, &task.EndDate, &task.StateID, &task.CategoryID, &task.UserID,
-	)
+		WITH RECURSIVE descendants AS (
+			SELECT id FROM tasks WHERE parent_id = $1 AND deleted_at IS NULL AND ` + workspaces.Condition(ctx, "workspace_id") + `
+			UNION
+			SELECT t.id FROM tasks t JOIN descendants d ON t.parent_id = d.id WHERE t.deleted_at IS NULL
+		)
+		UPDATE tasks SET id_state = $2, completed_at = NOW(), version = version + 1
+		WHERE id IN (SELECT id FROM descendants) AND (id_state IS NULL OR id_state NOT IN ` + doneStates + `)
+		RETURNING id`
+
+	return r.queryIDs(ctx, query, id, StateCompleted)
+}
+
+// attachProgress fills in the progress roll-up of every task that has descendants
+func (r *PostgresRepository) attachProgress(ctx context.Context, tasks []*TaskResponse) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, len(tasks))
+	for i, task := range tasks {
+		ids[i] = task.ID
+	}
+
+	progress, err := r.progressByRoot(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	for _, task := range tasks {
+		task.Progress = progress[task.ID]
+	}
+
+	return nil
+}
+
+// progressByRoot counts total and completed descendants for each of the given tasks in a single query
+func (r *PostgresRepository) progressByRoot(ctx context.Context, ids []int64) (map[int64]*TaskProgress, error) {
+	query := `
+		WITH RECURSIVE descendants AS (
+			SELECT parent_id AS root_id, id, id_state FROM tasks WHERE parent_id = ANY($1) AND deleted_at IS NULL AND ` + workspaces.Condition(ctx, "workspace_id") + `
+			UNION
+			SELECT d.root_id, t.id, t.id_state FROM tasks t JOIN descendants d ON t.parent_id = d.id
+			WHERE t.deleted_at IS NULL
+		)
+		SELECT root_id, COUNT(*), COUNT(*) FILTER (WHERE id_state IN ` + doneStates + `)
+		FROM descendants
+		GROUP BY root_id`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(ids))
 	if err != nil {
 		return nil, err
 	}
-	
-	return &task, nil
+	defer rows.Close()
+
+	progress := make(map[int64]*TaskProgress)
+	for rows.Next() {
+		var rootID int64
+		var p TaskProgress
+		if err := rows.Scan(&rootID, &p.Total, &p.Completed); err != nil {
+			return nil, err
+		}
+		p.Percent = int(math.Round(float64(p.Completed) * 100 / float64(p.Total)))
+		progress[rootID] = &p
+	}
+
+	return progress, rows.Err()
+}
+
+// Update updates an existing task's text, category, end date, state and parent.
+// The write only applies if the row is still at task.Version, otherwise ErrVersionMismatch is returned.
+func (r *PostgresRepository) Update(ctx context.Context, task *Task) (*Task, error) {
+	query := `
+		UPDATE tasks
+		SET task_text = $1, id_category = $2, end_date = $3, id_state = $4, parent_id = $5,
+			recurrence = $6, series_id = $7, priority = $8, important = $9, started_at = $10, completed_at = $11,
+			assignee_id = $12, position = $13, version = version + 1
+		WHERE id = $14 AND version = $15 AND deleted_at IS NULL AND ` + workspaces.Condition(ctx, "workspace_id") + `
+		RETURNING ` + taskColumns
+
+	updated, err := scanTask(r.db.QueryRowContext(ctx, query,
+		task.TaskText, task.CategoryID, task.EndDate, task.StateID, task.ParentID,
+		task.Recurrence, task.SeriesID, task.Priority, task.Important, task.StartedAt, task.CompletedAt,
+		task.AssigneeID, task.Position, task.ID, task.Version,
+	))
+	if err == sql.ErrNoRows {
+		return nil, ErrVersionMismatch
+	}
+
+	return updated, err
 }
 
-// Delete removes a task by ID
-func (r *PostgresRepository) Delete(ctx context.Context, id int64) error {
-	query := `DELETE FROM tasks WHERE id = $1`
-	result, err := r.db.ExecContext(ctx, query, id)
+// Delete moves a task and all of its subtasks to the trash, marking them with the same deleted_at.
+// When version is set the task must still be at that version, otherwise ErrVersionMismatch is returned.
+func (r *PostgresRepository) Delete(ctx context.Context, id int64, version *int64) error {
+	query := `
+		WITH RECURSIVE subtree AS (
+			SELECT id FROM tasks
+			WHERE id = $1 AND deleted_at IS NULL AND ($2::BIGINT IS NULL OR version = $2) AND ` + workspaces.Condition(ctx, "workspace_id") + `
+			UNION
+			SELECT t.id FROM tasks t JOIN subtree st ON t.parent_id = st.id WHERE t.deleted_at IS NULL
+		)
+		UPDATE tasks SET deleted_at = NOW(), version = version + 1 WHERE id IN (SELECT id FROM subtree)`
+	result, err := r.db.ExecContext(ctx, query, id, version)
 	if err != nil {
 		return err
 	}
-	
+
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
 		return err
 	}
-	
+
 	if rowsAffected == 0 {
+		if version != nil {
+			return ErrVersionMismatch
+		}
 		return sql.ErrNoRows
 	}
-	
+
 	return nil
-}
\ No newline at end of file
+}
+
+// GetDeletedByID retrieves a task from the trash by ID
+func (r *PostgresRepository) GetDeletedByID(ctx context.Context, id int64) (*Task, error) {
+	query := `SELECT ` + taskColumns + ` FROM tasks WHERE id = $1 AND deleted_at IS NOT NULL AND ` + workspaces.Condition(ctx, "workspace_id")
+
+	task, err := scanTask(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return task, nil
+}
+
+// GetTrash retrieves a user's deleted tasks, most recently deleted first
+func (r *PostgresRepository) GetTrash(ctx context.Context, userID int64) ([]*TaskResponse, error) {
+	query := taskDetailsSelect + ` WHERE t.id_user = $1 AND t.deleted_at IS NOT NULL AND ` + workspaces.Condition(ctx, "t.workspace_id") +
+		` ORDER BY t.deleted_at DESC, t.id`
+
+	return r.queryTaskResponses(ctx, query, userID)
+}
+
+// Restore takes a task out of the trash together with the subtasks deleted along with it,
+// returning the IDs of every restored task
+func (r *PostgresRepository) Restore(ctx context.Context, id int64) ([]int64, error) {
+	query := `
+		WITH RECURSIVE subtree AS (
+			SELECT id, deleted_at FROM tasks WHERE id = $1 AND deleted_at IS NOT NULL AND ` + workspaces.Condition(ctx, "workspace_id") + `
+			UNION
+			SELECT t.id, t.deleted_at FROM tasks t JOIN subtree st ON t.parent_id = st.id
+			WHERE t.deleted_at = st.deleted_at
+		)
+		UPDATE tasks SET deleted_at = NULL, version = version + 1 WHERE id IN (SELECT id FROM subtree)
+		RETURNING id`
+
+	return r.queryIDs(ctx, query, id)
+}
+
+// queryIDs runs a query returning a single column of task IDs
+func (r *PostgresRepository) queryIDs(ctx context.Context, query string, args ...interface{}) ([]int64, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+// Purge permanently removes the tasks that were moved to the trash before the given time
+func (r *PostgresRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM tasks WHERE deleted_at < $1`, before)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// columnCondition selects the live tasks of a board column, numbering its arguments from $1.
+// Tasks without a state sit in the Not Started column.
+func columnCondition(column Column) (string, []interface{}) {
+	condition := fmt.Sprintf(`deleted_at IS NULL AND COALESCE(id_state, %d) = $1`, StateNotStarted)
+	if column.WorkspaceID != nil {
+		return condition + ` AND workspace_id = $2`, []interface{}{column.StateID, *column.WorkspaceID}
+	}
+
+	return condition + ` AND workspace_id IS NULL AND id_user = $2`, []interface{}{column.StateID, column.UserID}
+}
+
+// LastPosition returns the position of the bottom card of a board column, "" when it has none
+func (r *PostgresRepository) LastPosition(ctx context.Context, column Column) (string, error) {
+	condition, args := columnCondition(column)
+
+	var position string
+	err := r.db.QueryRowContext(ctx, `SELECT COALESCE(MAX(position), '') FROM tasks WHERE `+condition, args...).Scan(&position)
+	if err != nil {
+		return "", fmt.Errorf("failed to get last position: %w", err)
+	}
+
+	return position, nil
+}
+
+// HasTasksBetween reports whether any card of a column other than excludeID sorts strictly between
+// two positions. An empty lower or upper leaves that side unbounded.
+func (r *PostgresRepository) HasTasksBetween(ctx context.Context, column Column, lower string, upper string, excludeID int64) (bool, error) {
+	condition, args := columnCondition(column)
+	query := `SELECT EXISTS (SELECT 1 FROM tasks WHERE ` + condition + ` AND id <> $3
+		AND ($4 = '' OR position > $4) AND ($5 = '' OR position < $5))`
+
+	var exists bool
+	err := r.db.QueryRowContext(ctx, query, append(args, excludeID, lower, upper)...).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check positions: %w", err)
+	}
+
+	return exists, nil
+}
+
+// RebalanceColumn rewrites the positions of every card of a column, keeping their order, with
+// short keys spread evenly over the key space. Versions are left alone since nothing else changes.
+func (r *PostgresRepository) RebalanceColumn(ctx context.Context, column Column) error {
+	condition, args := columnCondition(column)
+
+	return r.WithTx(ctx, func(repo Repository) error {
+		tx := repo.(*PostgresRepository)
+		ids, err := tx.queryIDs(ctx, `SELECT id FROM tasks WHERE `+condition+` ORDER BY position ASC NULLS LAST, id ASC FOR UPDATE`, args...)
+		if err != nil {
+			return fmt.Errorf("failed to rebalance column: %w", err)
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+
+		query := `
+			UPDATE tasks SET position = p.position
+			FROM unnest($1::INT[], $2::TEXT[]) AS p(id, position)
+			WHERE tasks.id = p.id`
+		if _, err := tx.db.ExecContext(ctx, query, pq.Array(ids), pq.Array(spreadPositions(len(ids)))); err != nil {
+			return fmt.Errorf("failed to rebalance column: %w", err)
+		}
+		return nil
+	})
+}
+
+// GetUnbalancedColumns lists the board columns holding a card without a position, two cards
+// with the same position or a position longer than maxLength
+func (r *PostgresRepository) GetUnbalancedColumns(ctx context.Context, maxLength int) ([]Column, error) {
+	query := fmt.Sprintf(`
+		SELECT COALESCE(id_state, %d), workspace_id, CASE WHEN workspace_id IS NULL THEN id_user END
+		FROM tasks
+		WHERE deleted_at IS NULL
+		GROUP BY 1, 2, 3
+		HAVING COUNT(*) > COUNT(DISTINCT position) OR MAX(LENGTH(position)) > $1`, StateNotStarted)
+
+	rows, err := r.db.QueryContext(ctx, query, maxLength)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find unbalanced columns: %w", err)
+	}
+	defer rows.Close()
+
+	var columns []Column
+	for rows.Next() {
+		var column Column
+		var userID sql.NullInt64
+		if err := rows.Scan(&column.StateID, &column.WorkspaceID, &userID); err != nil {
+			return nil, fmt.Errorf("failed to scan column: %w", err)
+		}
+		column.UserID = userID.Int64
+		columns = append(columns, column)
+	}
+
+	return columns, rows.Err()
+}
+
+// GetBoardStates lists the built-in states, the user's own states and any of stateIDs, in workflow order
+func (r *PostgresRepository) GetBoardStates(ctx context.Context, userID int64, stateIDs []int64) ([]*StateInfo, error) {
+	query := `
+		SELECT id, description, is_done FROM states
+		WHERE user_id IS NULL OR user_id = $1 OR id = ANY($2)
+		ORDER BY position, id`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, pq.Array(uniqueIDs(stateIDs)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get board states: %w", err)
+	}
+	defer rows.Close()
+
+	var states []*StateInfo
+	for rows.Next() {
+		var state StateInfo
+		if err := rows.Scan(&state.ID, &state.Description, &state.IsDone); err != nil {
+			return nil, fmt.Errorf("failed to scan state: %w", err)
+		}
+		state.Description = i18n.StateName(i18n.FromContext(ctx), state.ID, state.Description)
+		states = append(states, &state)
+	}
+
+	return states, rows.Err()
+}
+
+// GetCategoryIDs looks up the live categories of the current space by name, ignoring case.
+// The result is keyed by lowercase name; when several categories share a name the oldest wins.
+func (r *PostgresRepository) GetCategoryIDs(ctx context.Context, names []string) (map[string]int64, error) {
+	lowered := make([]string, len(names))
+	for i, name := range names {
+		lowered[i] = strings.ToLower(name)
+	}
+
+	query := `
+		SELECT LOWER(name), MIN(id) FROM categories
+		WHERE LOWER(name) = ANY($1) AND deleted_at IS NULL AND ` + workspaces.Condition(ctx, "workspace_id") + `
+		GROUP BY LOWER(name)`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(lowered))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get categories: %w", err)
+	}
+	defer rows.Close()
+
+	ids := make(map[string]int64)
+	for rows.Next() {
+		var name string
+		var id int64
+		if err := rows.Scan(&name, &id); err != nil {
+			return nil, fmt.Errorf("failed to scan category: %w", err)
+		}
+		ids[name] = id
+	}
+
+	return ids, rows.Err()
+}
+
+// CreateCategory creates a category without description in the current space, so that it
+// is created in the same transaction as the tasks using it
+func (r *PostgresRepository) CreateCategory(ctx context.Context, name string) (int64, error) {
+	var id int64
+	err := r.db.QueryRowContext(ctx, `INSERT INTO categories (name, description, workspace_id) VALUES ($1, '', $2) RETURNING id`,
+		name, workspaces.IDFromContext(ctx)).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create category: %w", err)
+	}
+
+	return id, nil
+}
+
+// GetTagIDs looks up the tags of a user by name, ignoring case. The result is keyed by lowercase name.
+func (r *PostgresRepository) GetTagIDs(ctx context.Context, userID int64, names []string) (map[string]int64, error) {
+	lowered := make([]string, len(names))
+	for i, name := range names {
+		lowered[i] = strings.ToLower(name)
+	}
+
+	rows, err := r.db.QueryContext(ctx, `SELECT LOWER(name), id FROM tags WHERE user_id = $1 AND LOWER(name) = ANY($2)`,
+		userID, pq.Array(lowered))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tags: %w", err)
+	}
+	defer rows.Close()
+
+	ids := make(map[string]int64)
+	for rows.Next() {
+		var name string
+		var id int64
+		if err := rows.Scan(&name, &id); err != nil {
+			return nil, fmt.Errorf("failed to scan tag: %w", err)
+		}
+		ids[name] = id
+	}
+
+	return ids, rows.Err()
+}
+
+// CreateTag creates a tag of a user, so that it is created in the same transaction as the tasks using it
+func (r *PostgresRepository) CreateTag(ctx context.Context, userID int64, name string) (int64, error) {
+	var id int64
+	err := r.db.QueryRowContext(ctx, `INSERT INTO tags (user_id, name) VALUES ($1, $2) RETURNING id`, userID, name).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create tag: %w", err)
+	}
+
+	return id, nil
+}
+
+// AttachTag attaches a tag to a task; attaching it twice has no effect
+func (r *PostgresRepository) AttachTag(ctx context.Context, taskID int64, tagID int64) error {
+	_, err := r.db.ExecContext(ctx, `INSERT INTO task_tags (task_id, tag_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`, taskID, tagID)
+	if err != nil {
+		return fmt.Errorf("failed to attach tag: %w", err)
+	}
+
+	return nil
+}
+
+// GetState retrieves a workflow state visible to the user: a built-in state or one of their own.
+// It returns nil when there is no such state.
+func (r *PostgresRepository) GetState(ctx context.Context, id int64, userID int64) (*StateInfo, error) {
+	query := `SELECT id, description, is_done FROM states WHERE id = $1 AND (user_id IS NULL OR user_id = $2)`
+
+	var state StateInfo
+	err := r.db.QueryRowContext(ctx, query, id, userID).Scan(&state.ID, &state.Description, &state.IsDone)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	state.Description = i18n.StateName(i18n.FromContext(ctx), state.ID, state.Description)
+	return &state, nil
+}
+
+// shareRole is the SQL expression for the role that the user bound to userParam holds on
+// task t through a share of the task itself or of its owner's category, NULL when none.
+// When both apply the stronger role wins.
+func shareRole(userParam string) string {
+	return `(SELECT CASE WHEN bool_or(sh.role = 'editor') THEN 'editor' ELSE 'viewer' END
+		FROM task_shares sh
+		WHERE sh.user_id = ` + userParam + ` AND sh.owner_id = t.id_user
+		  AND (sh.task_id = t.id OR sh.category_id = t.id_category)
+		HAVING COUNT(*) > 0)`
+}
+
+// GetRoles returns the role the user holds on every live task in ids, keyed by task ID.
+// Tasks that do not exist or that the user cannot access are absent from the returned map.
+func (r *PostgresRepository) GetRoles(ctx context.Context, ids []int64, userID int64) (map[int64]string, error) {
+	query := `
+		SELECT t.id,
+			CASE
+				WHEN t.id_user = $2 OR m.role IN ('owner', 'admin') THEN 'owner'
+				WHEN t.assignee_id = $2 OR m.role = 'member' THEN 'editor'
+				ELSE COALESCE(` + shareRole("$2") + `, CASE WHEN m.role = 'guest' THEN 'viewer' END)
+			END
+		FROM tasks t
+		LEFT JOIN workspace_members m ON m.workspace_id = t.workspace_id AND m.user_id = $2
+		WHERE t.id = ANY($1) AND t.deleted_at IS NULL AND ` + workspaces.Condition(ctx, "t.workspace_id")
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(ids), userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	roles := make(map[int64]string, len(ids))
+	for rows.Next() {
+		var id int64
+		var role sql.NullString
+		if err := rows.Scan(&id, &role); err != nil {
+			return nil, err
+		}
+		if role.Valid {
+			roles[id] = role.String
+		}
+	}
+
+	return roles, rows.Err()
+}
+
+// GetRole returns the role the user holds on a live task, "" when they cannot access it
+func (r *PostgresRepository) GetRole(ctx context.Context, id int64, userID int64) (string, error) {
+	roles, err := r.GetRoles(ctx, []int64{id}, userID)
+	if err != nil {
+		return "", err
+	}
+
+	return roles[id], nil
+}
+
+// IsWorkspaceMember reports whether the user belongs to a workspace
+func (r *PostgresRepository) IsWorkspaceMember(ctx context.Context, workspaceID int64, userID int64) (bool, error) {
+	query := `SELECT EXISTS(SELECT 1 FROM workspace_members WHERE workspace_id = $1 AND user_id = $2)`
+
+	var member bool
+	if err := r.db.QueryRowContext(ctx, query, workspaceID, userID).Scan(&member); err != nil {
+		return false, err
+	}
+
+	return member, nil
+}
+
+// GetShared retrieves the live tasks of other users shared with the user, newest first
+func (r *PostgresRepository) GetShared(ctx context.Context, userID int64) ([]*TaskResponse, error) {
+	query := taskDetailsSelect + `
+		WHERE t.id_user <> $1 AND t.deleted_at IS NULL AND ` + shareRole("$1") + ` IS NOT NULL AND ` + workspaces.Condition(ctx, "t.workspace_id") + `
+		ORDER BY t.creation_date DESC, t.id DESC`
+
+	return r.queryTaskResponses(ctx, query, userID)
+}
+
+// AddDependency records that taskID cannot progress until blockerID is completed
+func (r *PostgresRepository) AddDependency(ctx context.Context, taskID int64, blockerID int64) error {
+	query := `
+		INSERT INTO task_dependencies (task_id, blocker_id)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING`
+
+	_, err := r.db.ExecContext(ctx, query, taskID, blockerID)
+	return err
+}
+
+// RemoveDependency deletes a dependency, returning sql.ErrNoRows if it did not exist
+func (r *PostgresRepository) RemoveDependency(ctx context.Context, taskID int64, blockerID int64) error {
+	query := `DELETE FROM task_dependencies WHERE task_id = $1 AND blocker_id = $2`
+	result, err := r.db.ExecContext(ctx, query, taskID, blockerID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// DependsOn reports whether taskID is blocked, directly or transitively, by blockerID
+func (r *PostgresRepository) DependsOn(ctx context.Context, taskID int64, blockerID int64) (bool, error) {
+	query := `
+		WITH RECURSIVE blockers AS (
+			SELECT blocker_id FROM task_dependencies WHERE task_id = $1
+			UNION
+			SELECT d.blocker_id FROM task_dependencies d JOIN blockers b ON d.task_id = b.blocker_id
+		)
+		SELECT EXISTS(SELECT 1 FROM blockers WHERE blocker_id = $2)`
+
+	var exists bool
+	if err := r.db.QueryRowContext(ctx, query, taskID, blockerID).Scan(&exists); err != nil {
+		return false, err
+	}
+
+	return exists, nil
+}
+
+// LockDependencies serializes changes to the dependency graph of an owner until the end of the
+// transaction. Dependencies only link tasks of the same owner, so once it holds the lock a caller
+// sees every edge added before it and cannot close a cycle together with a concurrent request.
+func (r *PostgresRepository) LockDependencies(ctx context.Context, userID int64) error {
+	query := `SELECT pg_advisory_xact_lock(hashtextextended('task_dependencies:' || $1::bigint, 0))`
+
+	if _, err := r.db.ExecContext(ctx, query, userID); err != nil {
+		return fmt.Errorf("failed to lock task dependencies: %w", err)
+	}
+
+	return nil
+}
+
+// GetBlockers retrieves the direct blockers of a task
+func (r *PostgresRepository) GetBlockers(ctx context.Context, taskID int64) ([]BlockerInfo, error) {
+	blockers, err := r.blockersByTask(ctx, []int64{taskID})
+	if err != nil {
+		return nil, err
+	}
+
+	return blockers[taskID], nil
+}
+
+// attachBlockers fills in the blockers and the computed blocked flag of every task
+func (r *PostgresRepository) attachBlockers(ctx context.Context, tasks []*TaskResponse) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, len(tasks))
+	for i, task := range tasks {
+		ids[i] = task.ID
+	}
+
+	blockers, err := r.blockersByTask(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	for _, task := range tasks {
+		task.BlockedBy = blockers[task.ID]
+		if task.BlockedBy == nil {
+			task.BlockedBy = []BlockerInfo{}
+		}
+		task.Blocked = false
+		for _, blocker := range task.BlockedBy {
+			if !blocker.Completed {
+				task.Blocked = true
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// blockersByTask loads the direct blockers of each of the given tasks in a single query
+func (r *PostgresRepository) blockersByTask(ctx context.Context, ids []int64) (map[int64][]BlockerInfo, error) {
+	query := `
+		SELECT d.task_id, b.id, b.task_text, COALESCE(b.id_state IN ` + doneStates + `, FALSE)
+		FROM task_dependencies d
+		JOIN tasks b ON b.id = d.blocker_id AND b.deleted_at IS NULL
+		WHERE d.task_id = ANY($1)
+		ORDER BY d.task_id, b.id`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	blockers := make(map[int64][]BlockerInfo)
+	for rows.Next() {
+		var taskID int64
+		var blocker BlockerInfo
+		if err := rows.Scan(&taskID, &blocker.ID, &blocker.TaskText, &blocker.Completed); err != nil {
+			return nil, err
+		}
+		blockers[taskID] = append(blockers[taskID], blocker)
+	}
+
+	return blockers, rows.Err()
+}
+
+// attachTags fills in the tags of every task
+func (r *PostgresRepository) attachTags(ctx context.Context, tasks []*TaskResponse) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, len(tasks))
+	for i, task := range tasks {
+		ids[i] = task.ID
+	}
+
+	tags, err := r.tagsByTask(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	for _, task := range tasks {
+		task.Tags = tags[task.ID]
+		if task.Tags == nil {
+			task.Tags = []TagInfo{}
+		}
+	}
+
+	return nil
+}
+
+// tagsByTask loads the tags of each of the given tasks in a single query
+func (r *PostgresRepository) tagsByTask(ctx context.Context, ids []int64) (map[int64][]TagInfo, error) {
+	query := `
+		SELECT tt.task_id, g.id, g.name
+		FROM task_tags tt
+		JOIN tags g ON g.id = tt.tag_id
+		WHERE tt.task_id = ANY($1)
+		ORDER BY tt.task_id, LOWER(g.name), g.id`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := make(map[int64][]TagInfo)
+	for rows.Next() {
+		var taskID int64
+		var tag TagInfo
+		if err := rows.Scan(&taskID, &tag.ID, &tag.Name); err != nil {
+			return nil, err
+		}
+		tags[taskID] = append(tags[taskID], tag)
+	}
+
+	return tags, rows.Err()
+}
+
+// uniqueIDs returns ids without duplicates, keeping their order
+func uniqueIDs(ids []int64) []int64 {
+	seen := make(map[int64]bool, len(ids))
+	unique := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+
+	return unique
+}
+
+// GetSeries retrieves every occurrence of a recurring series, oldest first
+func (r *PostgresRepository) GetSeries(ctx context.Context, seriesID int64) ([]*TaskResponse, error) {
+	return r.queryTaskResponses(ctx, taskDetailsSelect+` WHERE t.series_id = $1 AND t.deleted_at IS NULL AND `+workspaces.Condition(ctx, "t.workspace_id")+` ORDER BY t.occurrence, t.id`, seriesID)
+}
+
+// GetSeriesTasks retrieves every occurrence of a series, locking the rows for the rest of the transaction
+func (r *PostgresRepository) GetSeriesTasks(ctx context.Context, seriesID int64) ([]*Task, error) {
+	query := `SELECT ` + taskColumns + ` FROM tasks WHERE series_id = $1 AND deleted_at IS NULL AND ` + workspaces.Condition(ctx, "workspace_id") +
+		` ORDER BY occurrence, id FOR UPDATE`
+
+	return r.queryTasks(ctx, query, seriesID)
+}
+
+// UpdateSeries edits the text, category and rule of every unfinished occurrence of a series
+// and returns the IDs of the occurrences it changed
+func (r *PostgresRepository) UpdateSeries(ctx context.Context, seriesID int64, taskText string, categoryID *int64, recurrence string) ([]int64, error) {
+	query := `
+		UPDATE tasks
+		SET task_text = $2, id_category = $3, recurrence = $4, version = version + 1
+		WHERE series_id = $1 AND (id_state IS NULL OR id_state NOT IN ` + doneStates + `) AND deleted_at IS NULL
+			AND ` + workspaces.Condition(ctx, "workspace_id") + `
+		RETURNING id`
+
+	return r.queryIDs(ctx, query, seriesID, taskText, categoryID, recurrence)
+}
+
+// StopSeries removes the rule from every occurrence of a series so no further occurrences are generated
+func (r *PostgresRepository) StopSeries(ctx context.Context, seriesID int64) error {
+	query := `UPDATE tasks SET recurrence = NULL, version = version + 1
+		WHERE series_id = $1 AND recurrence IS NOT NULL AND deleted_at IS NULL AND ` + workspaces.Condition(ctx, "workspace_id")
+
+	_, err := r.db.ExecContext(ctx, query, seriesID)
+	return err
+}
+
+// CreateEvent stores a task history event
+func (r *PostgresRepository) CreateEvent(ctx context.Context, event *TaskEvent) error {
+	changes, err := json.Marshal(event.Changes)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO task_events (task_id, user_id, actor_id, action, changes)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at`
+
+	return r.db.QueryRowContext(ctx, query,
+		event.TaskID, event.UserID, event.ActorID, event.Action, changes,
+	).Scan(&event.ID, &event.CreatedAt)
+}
+
+// GetEvents retrieves the history of a task owned by the user, oldest first
+func (r *PostgresRepository) GetEvents(ctx context.Context, taskID int64, userID int64) ([]*TaskEvent, error) {
+	query := `
+		SELECT id, task_id, user_id, COALESCE(actor_id, 0), action, changes, created_at
+		FROM task_events
+		WHERE task_id = $1 AND user_id = $2
+		ORDER BY created_at, id`
+
+	rows, err := r.db.QueryContext(ctx, query, taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []*TaskEvent{}
+	for rows.Next() {
+		var event TaskEvent
+		var changes []byte
+		if err := rows.Scan(&event.ID, &event.TaskID, &event.UserID, &event.ActorID, &event.Action, &changes, &event.CreatedAt); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(changes, &event.Changes); err != nil {
+			return nil, err
+		}
+		events = append(events, &event)
+	}
+
+	return events, rows.Err()
+}
+
+// statsScope selects the live tasks GetAllByUser lists for the user ($1) in the current space
+func statsScope(ctx context.Context) string {
+	return `(t.id_user = $1 OR t.workspace_id IS NOT NULL) AND t.deleted_at IS NULL AND ` + workspaces.Condition(ctx, "t.workspace_id")
+}
+
+// statsToday is the current date in UTC, the time zone completion dates are counted in
+const statsToday = `(NOW() AT TIME ZONE 'UTC')::date`
+
+// GetStats computes the statistics of the user's tasks in the current space with aggregate
+// queries. The timeline covers the days from and to, grouped by interval ("day" or "week").
+func (r *PostgresRepository) GetStats(ctx context.Context, userID int64, from time.Time, to time.Time, interval string) (*Stats, error) {
+	scope := statsScope(ctx)
+	stats := &Stats{ByState: []StateCount{}, ByCategory: []CategoryCount{}, Timeline: []StatsPeriod{}}
+
+	// Totals; a task done before the day it was created counts as done on that day
+	query := `
+		SELECT
+			COUNT(*),
+			COUNT(*) FILTER (WHERE t.id_state IN ` + doneStates + `),
+			COUNT(*) FILTER (WHERE COALESCE(t.id_state, ` + strconv.FormatInt(StateNotStarted, 10) + `) NOT IN ` + doneStates + `
+				AND t.end_date < ` + statsToday + `),
+			AVG(GREATEST((t.completed_at AT TIME ZONE 'UTC')::date - t.creation_date, 0))
+				FILTER (WHERE t.id_state IN ` + doneStates + ` AND t.completed_at IS NOT NULL)
+		FROM tasks t
+		WHERE ` + scope
+
+	var average sql.NullFloat64
+	err := r.db.QueryRowContext(ctx, query, userID).Scan(&stats.Total, &stats.Completed, &stats.Overdue, &average)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task statistics: %w", err)
+	}
+	stats.Open = stats.Total - stats.Completed
+	if average.Valid {
+		stats.AvgDaysToComplete = &average.Float64
+	}
+
+	if err := r.statsByState(ctx, userID, scope, stats); err != nil {
+		return nil, err
+	}
+	if err := r.statsByCategory(ctx, userID, scope, stats); err != nil {
+		return nil, err
+	}
+	if err := r.statsTimeline(ctx, userID, scope, from, to, interval, stats); err != nil {
+		return nil, err
+	}
+
+	// The current streak is the last run of consecutive completion days, if it reaches yesterday.
+	// Subtracting the rank of each day from it gives the same date for all days of a run.
+	query = `
+		WITH days AS (
+			SELECT DISTINCT (t.completed_at AT TIME ZONE 'UTC')::date AS day
+			FROM tasks t
+			WHERE ` + scope + ` AND t.id_state IN ` + doneStates + ` AND t.completed_at IS NOT NULL
+		), runs AS (
+			SELECT day, day - (ROW_NUMBER() OVER (ORDER BY day))::int AS run FROM days
+		)
+		SELECT COUNT(*) FROM runs
+		WHERE run = (SELECT run FROM runs ORDER BY day DESC LIMIT 1)
+			AND (SELECT MAX(day) FROM days) >= ` + statsToday + ` - 1`
+
+	if err := r.db.QueryRowContext(ctx, query, userID).Scan(&stats.StreakDays); err != nil {
+		return nil, fmt.Errorf("failed to get completion streak: %w", err)
+	}
+
+	return stats, nil
+}
+
+// statsByState counts the tasks in each workflow state
+func (r *PostgresRepository) statsByState(ctx context.Context, userID int64, scope string, stats *Stats) error {
+	query := `
+		SELECT s.id, s.description, s.is_done, COUNT(*)
+		FROM tasks t
+		JOIN states s ON s.id = COALESCE(t.id_state, ` + strconv.FormatInt(StateNotStarted, 10) + `)
+		WHERE ` + scope + `
+		GROUP BY s.id, s.description, s.is_done
+		ORDER BY s.id`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return fmt.Errorf("failed to count tasks by state: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var count StateCount
+		if err := rows.Scan(&count.State.ID, &count.State.Description, &count.State.IsDone, &count.Count); err != nil {
+			return fmt.Errorf("failed to scan state count: %w", err)
+		}
+		count.State.Description = i18n.StateName(i18n.FromContext(ctx), count.State.ID, count.State.Description)
+		stats.ByState = append(stats.ByState, count)
+	}
+
+	return rows.Err()
+}
+
+// statsByCategory counts the tasks of each category, most used first; the tasks without a category come last
+func (r *PostgresRepository) statsByCategory(ctx context.Context, userID int64, scope string, stats *Stats) error {
+	query := `
+		SELECT c.id, c.name, c.description, COUNT(*), COUNT(*) FILTER (WHERE t.id_state IN ` + doneStates + `)
+		FROM tasks t
+		LEFT JOIN categories c ON c.id = t.id_category
+		WHERE ` + scope + `
+		GROUP BY c.id, c.name, c.description
+		ORDER BY c.id IS NULL, COUNT(*) DESC, c.name`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return fmt.Errorf("failed to count tasks by category: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id sql.NullInt64
+		var name, description sql.NullString
+		var count CategoryCount
+		if err := rows.Scan(&id, &name, &description, &count.Count, &count.Completed); err != nil {
+			return fmt.Errorf("failed to scan category count: %w", err)
+		}
+		if id.Valid {
+			count.Category = &CategoryInfo{ID: id.Int64, Name: name.String, Description: description.String}
+		}
+		stats.ByCategory = append(stats.ByCategory, count)
+	}
+
+	return rows.Err()
+}
+
+// statsTimeline counts the tasks created and completed in each day or week between from and to.
+// Weeks start on Monday; the first and last week only count the days in the range.
+func (r *PostgresRepository) statsTimeline(ctx context.Context, userID int64, scope string, from time.Time, to time.Time, interval string, stats *Stats) error {
+	query := `
+		WITH periods AS (
+			SELECT generate_series(date_trunc($2, $3::date), $4::date, ('1 ' || $2)::interval)::date AS start
+		), created AS (
+			SELECT date_trunc($2, t.creation_date)::date AS start, COUNT(*) AS n
+			FROM tasks t
+			WHERE ` + scope + ` AND t.creation_date BETWEEN $3::date AND $4::date
+			GROUP BY 1
+		), completed AS (
+			SELECT date_trunc($2, (t.completed_at AT TIME ZONE 'UTC')::date)::date AS start, COUNT(*) AS n
+			FROM tasks t
+			WHERE ` + scope + ` AND t.id_state IN ` + doneStates + `
+				AND (t.completed_at AT TIME ZONE 'UTC')::date BETWEEN $3::date AND $4::date
+			GROUP BY 1
+		)
+		SELECT p.start, COALESCE(cr.n, 0), COALESCE(co.n, 0)
+		FROM periods p
+		LEFT JOIN created cr ON cr.start = p.start
+		LEFT JOIN completed co ON co.start = p.start
+		ORDER BY p.start`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, interval, from.Format("2006-01-02"), to.Format("2006-01-02"))
+	if err != nil {
+		return fmt.Errorf("failed to get task timeline: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var start time.Time
+		var period StatsPeriod
+		if err := rows.Scan(&start, &period.Created, &period.Completed); err != nil {
+			return fmt.Errorf("failed to scan timeline period: %w", err)
+		}
+		period.Start = start.Format("2006-01-02")
+		stats.Timeline = append(stats.Timeline, period)
+	}
+
+	return rows.Err()
+}
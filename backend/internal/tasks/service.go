@@ -2,24 +2,64 @@ package tasks
 
 import (
 	"context"
+	"database/sql"
 	"errors"
+	"strconv"
 	"strings"
 	"time"
+
+	"backend/root/internal/i18n"
+	"backend/root/internal/recurrence"
+	"backend/root/internal/workspaces"
 )
 
 // Service defines the interface for task business logic
 type Service interface {
 	Create(ctx context.Context, userID int64, req CreateTaskRequest) (*TaskResponse, error)
 	GetByID(ctx context.Context, taskID int64, userID int64) (*TaskResponse, error)
-	GetAllByUser(ctx context.Context, userID int64, categoryID *int64, stateID *int64) ([]*TaskResponse, error)
+	GetAllByUser(ctx context.Context, userID int64, filter TaskFilter) ([]*TaskResponse, error)
+	GetMatrix(ctx context.Context, userID int64) (*EisenhowerMatrix, error)
+	GetStats(ctx context.Context, userID int64, req StatsRequest) (*Stats, error)
+	QuickAdd(ctx context.Context, userID int64, req QuickAddRequest) (*QuickAddResult, error)
 	Update(ctx context.Context, taskID int64, userID int64, req UpdateTaskRequest) (*TaskResponse, error)
-	Delete(ctx context.Context, taskID int64, userID int64) error
+	Transition(ctx context.Context, taskID int64, userID int64, req TransitionRequest) (*TaskResponse, error)
+	Delete(ctx context.Context, taskID int64, userID int64, ifMatch *int64) error
+	Bulk(ctx context.Context, userID int64, req BulkRequest) (*BulkResponse, error)
+	GetChildren(ctx context.Context, taskID int64, userID int64) ([]*TaskResponse, error)
+	GetTree(ctx context.Context, taskID int64, userID int64) (*TaskNode, error)
+	AddBlocker(ctx context.Context, taskID int64, userID int64, blockerID int64) (*TaskResponse, error)
+	RemoveBlocker(ctx context.Context, taskID int64, userID int64, blockerID int64) (*TaskResponse, error)
+	GetSeries(ctx context.Context, seriesID int64, userID int64) ([]*TaskResponse, error)
+	UpdateSeries(ctx context.Context, seriesID int64, userID int64, req UpdateSeriesRequest) ([]*TaskResponse, error)
+	StopSeries(ctx context.Context, seriesID int64, userID int64) ([]*TaskResponse, error)
+	GetHistory(ctx context.Context, taskID int64, userID int64) ([]*TaskEvent, error)
+	GetTrash(ctx context.Context, userID int64) ([]*TaskResponse, error)
+	Restore(ctx context.Context, taskID int64, userID int64) (*TaskResponse, error)
+	GetShared(ctx context.Context, userID int64) ([]*TaskResponse, error)
+	Assign(ctx context.Context, taskID int64, userID int64, req AssignRequest) (*TaskResponse, error)
+	Unassign(ctx context.Context, taskID int64, userID int64) (*TaskResponse, error)
+	Move(ctx context.Context, taskID int64, userID int64, req MoveTaskRequest) (*TaskResponse, error)
+	GetBoard(ctx context.Context, userID int64, filter TaskFilter) (*Board, error)
+	Import(ctx context.Context, userID int64, req ImportRequest) (*ImportResult, error)
+}
+
+// Options configures policy-driven service behavior
+type Options struct {
+	CompleteParentPolicy string           // ParentPolicyCascade or ParentPolicyBlock (default)
+	DeleteParentPolicy   string           // ParentPolicyCascade or ParentPolicyBlock (default)
+	UrgentWithinDays     int              // Tasks due within this many days are urgent (default 2)
+	AssignmentHooks      []AssignmentHook // Run after a task is assigned or unassigned
 }
 
+// MaxBulkOperations caps the number of operations accepted in a single bulk request
+const MaxBulkOperations = 100
+
 // service implements the Service interface
 type service struct {
 	repo     Repository
 	catRepo  CategoryRepository // For validating category exists
+	userRepo UserRepository     // For resolving assignees
+	opts     Options
 }
 
 // CategoryRepository defines the minimal interface needed to validate categories
@@ -28,23 +68,72 @@ type CategoryRepository interface {
 }
 
 // NewService creates a new task service
-func NewService(repo Repository, catRepo CategoryRepository) Service {
+func NewService(repo Repository, catRepo CategoryRepository, userRepo UserRepository, opts Options) Service {
+	if opts.CompleteParentPolicy != ParentPolicyCascade {
+		opts.CompleteParentPolicy = ParentPolicyBlock
+	}
+	if opts.DeleteParentPolicy != ParentPolicyCascade {
+		opts.DeleteParentPolicy = ParentPolicyBlock
+	}
+	if opts.UrgentWithinDays <= 0 {
+		opts.UrgentWithinDays = 2
+	}
+	
 	return &service{
-		repo:    repo,
-		catRepo: catRepo,
+		repo:     repo,
+		catRepo:  catRepo,
+		userRepo: userRepo,
+		opts:     opts,
 	}
 }
 
 // Create creates a new task with validation
 func (s *service) Create(ctx context.Context, userID int64, req CreateTaskRequest) (*TaskResponse, error) {
+	task, err := s.createTask(ctx, userID, req)
+	if err != nil {
+		return nil, err
+	}
+	
+	s.notifyNewAssignee(ctx, userID, task)
+	
+	// Return the task with complete details (state and category info)
+	return s.repo.GetByIDWithDetails(ctx, task.ID)
+}
+
+// createTask validates and inserts a new task without running the assignment hooks, which the
+// caller runs once the task is committed
+func (s *service) createTask(ctx context.Context, userID int64, req CreateTaskRequest) (*Task, error) {
+	task, err := s.newTask(ctx, userID, req)
+	if err != nil {
+		return nil, err
+	}
+	
+	err = s.withTx(ctx, func(tx *service) error {
+		return tx.insertTask(ctx, userID, task)
+	})
+	if err != nil {
+		return nil, err
+	}
+	
+	return task, nil
+}
+
+// newTask validates a create request and returns the task to insert, owned by the user
+// and placed in the current workspace
+func (s *service) newTask(ctx context.Context, userID int64, req CreateTaskRequest) (*Task, error) {
+	// Guests of a workspace cannot add tasks to it
+	if err := workspaces.CheckWritable(ctx); err != nil {
+		return nil, err
+	}
+	
 	// Validate and clean input
 	taskText := strings.TrimSpace(req.TaskText)
 	if taskText == "" {
-		return nil, errors.New("task text cannot be empty")
+		return nil, i18n.NewError("task_text_empty")
 	}
 	
 	if len(taskText) > 1000 {
-		return nil, errors.New("task text cannot exceed 1000 characters")
+		return nil, i18n.NewError("task_text_too_long")
 	}
 	
 	// Parse and validate end date if provided
@@ -52,12 +141,12 @@ func (s *service) Create(ctx context.Context, userID int64, req CreateTaskReques
 	if req.EndDate != "" {
 		parsedDate, err := time.Parse("2006-01-02", req.EndDate)
 		if err != nil {
-			return nil, errors.New("invalid end date format, use YYYY-MM-DD")
+			return nil, i18n.NewError("task_end_date_format")
 		}
 		
 		// Check that end date is not in the past
 		if parsedDate.Before(time.Now().Truncate(24 * time.Hour)) {
-			return nil, errors.New("end date cannot be in the past")
+			return nil, i18n.NewError("task_end_date_past")
 		}
 		
 		endDate = &parsedDate
@@ -70,92 +159,182 @@ func (s *service) Create(ctx context.Context, userID int64, req CreateTaskReques
 			return nil, errors.New("failed to validate category")
 		}
 		if !exists {
-			return nil, errors.New("category does not exist")
+			return nil, i18n.NewError("category_missing")
+		}
+	}
+	
+	// Validate parent task if provided
+	if req.ParentID != nil {
+		if err := s.validateParent(ctx, 0, *req.ParentID, userID); err != nil {
+			return nil, err
 		}
 	}
 	
-	// Create the task
-	task, err := s.repo.Create(ctx, userID, taskText, endDate, req.CategoryID)
+	// Validate recurrence rule if provided
+	recurrence, err := normalizeRecurrence(req.Recurrence)
 	if err != nil {
 		return nil, err
 	}
+	if recurrence != nil && endDate == nil {
+		return nil, i18n.NewError("recurrence_needs_end_date")
+	}
 	
-	// Return the task with complete details (state and category info)
-	return s.repo.GetByIDWithDetails(ctx, task.ID)
+	// Validate priority if provided
+	priority := PriorityNone
+	if req.Priority != nil {
+		if !IsValidPriority(*req.Priority) {
+			return nil, i18n.NewError("task_priority_invalid")
+		}
+		priority = *req.Priority
+	}
+	important := req.Important != nil && *req.Important
+	
+	// Resolve the assignee if provided; the creator owns the task either way
+	var assigneeID *int64
+	if req.Assignee != "" {
+		id, err := s.resolveAssignee(ctx, req.Assignee)
+		if err != nil {
+			return nil, err
+		}
+		assigneeID = &id
+	}
+	
+	return &Task{
+		TaskText:    taskText,
+		EndDate:     endDate,
+		CategoryID:  req.CategoryID,
+		UserID:      userID,
+		ParentID:    req.ParentID,
+		Recurrence:  recurrence,
+		Priority:    priority,
+		Important:   important,
+		CreatedBy:   &userID,
+		AssigneeID:  assigneeID,
+		WorkspaceID: workspaces.IDFromContext(ctx),
+	}, nil
 }
 
-// GetByID retrieves a task by ID with ownership validation
-func (s *service) GetByID(ctx context.Context, taskID int64, userID int64) (*TaskResponse, error) {
-	if taskID <= 0 {
-		return nil, errors.New("invalid task ID")
+// insertTask stores a validated task, replacing it with the stored row, and records its creation.
+// A recurring task starts its own series. It must run inside a transaction.
+func (s *service) insertTask(ctx context.Context, actorID int64, task *Task) error {
+	created, err := s.repo.Create(ctx, task)
+	if err != nil {
+		return err
 	}
 	
-	// First check if task exists and belongs to user
-	existingTask, err := s.repo.GetByID(ctx, taskID)
+	if created.Recurrence != nil {
+		created.SeriesID = &created.ID
+		if created, err = s.repo.Update(ctx, created); err != nil {
+			return err
+		}
+	}
+	
+	*task = *created
+	return s.recordEvent(ctx, actorID, nil, task)
+}
+
+// GetByID retrieves a task by ID if the user owns it or it was shared with them
+func (s *service) GetByID(ctx context.Context, taskID int64, userID int64) (*TaskResponse, error) {
+	_, role, err := s.authorize(ctx, taskID, userID, RoleViewer)
 	if err != nil {
 		return nil, err
 	}
-	if existingTask == nil {
-		return nil, errors.New("task not found")
-	}
-	if existingTask.UserID != userID {
-		return nil, errors.New("task not found")
-	}
 	
 	// Return the task with detailed information
-	return s.repo.GetByIDWithDetails(ctx, taskID)
+	task, err := s.repo.GetByIDWithDetails(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if role != RoleOwner {
+		task.Role = role
+	}
+	return task, nil
 }
 
-// GetAllByUser retrieves all tasks for a user with optional filtering
-func (s *service) GetAllByUser(ctx context.Context, userID int64, categoryID *int64, stateID *int64) ([]*TaskResponse, error) {
+// GetAllByUser retrieves all tasks for a user with optional filtering and sorting
+func (s *service) GetAllByUser(ctx context.Context, userID int64, filter TaskFilter) ([]*TaskResponse, error) {
 	// Validate category if provided
-	if categoryID != nil && *categoryID > 0 {
-		exists, err := s.catRepo.Exists(ctx, *categoryID)
+	if filter.CategoryID != nil && *filter.CategoryID > 0 {
+		exists, err := s.catRepo.Exists(ctx, *filter.CategoryID)
 		if err != nil {
 			return nil, errors.New("failed to validate category")
 		}
 		if !exists {
-			return nil, errors.New("category does not exist")
+			return nil, i18n.NewError("category_missing")
 		}
 	}
 	
 	// Validate state if provided
-	if stateID != nil && *stateID > 0 {
-		if !IsValidState(*stateID) {
-			return nil, errors.New("invalid state ID")
+	if filter.StateID != nil && *filter.StateID > 0 {
+		state, err := s.repo.GetState(ctx, *filter.StateID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if state == nil {
+			return nil, i18n.NewError("state_id_invalid")
 		}
 	}
 	
 	// Get tasks from repository
-	return s.repo.GetAllByUser(ctx, userID, categoryID, stateID)
+	return s.repo.GetAllByUser(ctx, userID, filter)
 }
 
-// Update updates an existing task with validation
-func (s *service) Update(ctx context.Context, taskID int64, userID int64, req UpdateTaskRequest) (*TaskResponse, error) {
-	if taskID <= 0 {
-		return nil, errors.New("invalid task ID")
+// GetMatrix sorts the user's open tasks into the four Eisenhower quadrants.
+// A task is urgent when its end date falls within the configured urgency window
+// (overdue tasks included) and important when flagged or of high priority.
+func (s *service) GetMatrix(ctx context.Context, userID int64) (*EisenhowerMatrix, error) {
+	tasks, err := s.repo.GetAllByUser(ctx, userID, TaskFilter{OnlyOpen: true, Sort: SortPriority})
+	if err != nil {
+		return nil, err
+	}
+	
+	today := time.Now().Truncate(24 * time.Hour)
+	matrix := &EisenhowerMatrix{
+		DoFirst:      []*TaskResponse{},
+		Schedule:     []*TaskResponse{},
+		Delegate:     []*TaskResponse{},
+		Eliminate:    []*TaskResponse{},
+		UrgentBefore: today.AddDate(0, 0, s.opts.UrgentWithinDays+1),
+	}
+	
+	for _, task := range tasks {
+		urgent := task.EndDate != nil && task.EndDate.Before(matrix.UrgentBefore)
+		switch {
+		case urgent && task.IsImportant():
+			matrix.DoFirst = append(matrix.DoFirst, task)
+		case task.IsImportant():
+			matrix.Schedule = append(matrix.Schedule, task)
+		case urgent:
+			matrix.Delegate = append(matrix.Delegate, task)
+		default:
+			matrix.Eliminate = append(matrix.Eliminate, task)
+		}
 	}
 	
-	// Check if task exists and belongs to user
-	existingTask, err := s.repo.GetByID(ctx, taskID)
+	return matrix, nil
+}
+
+// Update updates an existing task with validation
+func (s *service) Update(ctx context.Context, taskID int64, userID int64, req UpdateTaskRequest) (*TaskResponse, error) {
+	// The owner and editors may change a task
+	existingTask, _, err := s.authorize(ctx, taskID, userID, RoleEditor)
 	if err != nil {
 		return nil, err
 	}
-	if existingTask == nil {
-		return nil, errors.New("task not found")
-	}
-	if existingTask.UserID != userID {
-		return nil, errors.New("task not found")
+	
+	// Refuse to overwrite changes the client has not seen
+	if req.IfMatch != nil && *req.IfMatch != existingTask.Version {
+		return nil, ErrVersionMismatch
 	}
 	
 	// Validate and clean input
 	taskText := strings.TrimSpace(req.TaskText)
 	if taskText == "" {
-		return nil, errors.New("task text cannot be empty")
+		return nil, i18n.NewError("task_text_empty")
 	}
 	
 	if len(taskText) > 1000 {
-		return nil, errors.New("task text cannot exceed 1000 characters")
+		return nil, i18n.NewError("task_text_too_long")
 	}
 	
 	// Parse and validate end date if provided
@@ -163,52 +342,995 @@ func (s *service) Update(ctx context.Context, taskID int64, userID int64, req Up
 	if req.EndDate != "" {
 		parsedDate, err := time.Parse("2006-01-02", req.EndDate)
 		if err != nil {
-			return nil, errors.New("invalid end date format, use YYYY-MM-DD")
+			return nil, i18n.NewError("task_end_date_format")
 		}
 		
-		// Check that end date is not in the past
-		if parsedDate.Before(time.Now().Truncate(24 * time.Hour)) {
-			return nil, errors.New("end date cannot be in the past")
+		// Check that end date is not in the past; an overdue task can keep its date
+		if parsedDate.Before(time.Now().Truncate(24 * time.Hour)) && !sameDate(existingTask.EndDate, parsedDate) {
+			return nil, i18n.NewError("task_end_date_past")
 		}
 		
 		endDate = &parsedDate
 	}
 	// If req.EndDate is empty string, endDate will be nil (clears the date)
 	
-	// Validate state if provided
+	// Validate state if provided; it must be reachable from the current one
+	if req.StateID != nil {
+		if err := s.checkStateChange(ctx, existingTask, *req.StateID); err != nil {
+			return nil, err
+		}
+	}
+	
+	// Validate parent task if provided; it must belong to the task owner, and 0 detaches the task
+	if req.ParentID != nil && *req.ParentID != 0 {
+		if err := s.validateParent(ctx, taskID, *req.ParentID, existingTask.UserID); err != nil {
+			return nil, err
+		}
+	}
+	
+	// Starting or completing a task requires its blockers to be finished unless explicitly overridden
+	if !req.IgnoreBlockers {
+		if err := s.checkBlockers(ctx, existingTask, req.StateID); err != nil {
+			return nil, err
+		}
+	}
+	
+	// Update the task and return it with detailed information
+	updatedTask := *existingTask
+	updatedTask.TaskText = taskText
+	updatedTask.CategoryID = req.CategoryID
+	updatedTask.EndDate = endDate
 	if req.StateID != nil {
-		if !IsValidState(*req.StateID) {
-			return nil, errors.New("invalid state ID")
+		updatedTask.StateID = req.StateID
+	}
+	if req.ParentID != nil {
+		updatedTask.ParentID = req.ParentID
+		if *req.ParentID == 0 {
+			updatedTask.ParentID = nil
 		}
 	}
 	
-	// Update the task
-	_, err = s.repo.Update(ctx, taskID, taskText, req.CategoryID, endDate, req.StateID)
+	// Replace or stop the recurrence rule if requested; the first rule starts a series
+	if req.Recurrence != nil {
+		recurrence, err := normalizeRecurrence(*req.Recurrence)
+		if err != nil {
+			return nil, err
+		}
+		updatedTask.Recurrence = recurrence
+		if recurrence != nil && updatedTask.SeriesID == nil {
+			updatedTask.SeriesID = &updatedTask.ID
+		}
+	}
+	if updatedTask.Recurrence != nil && updatedTask.EndDate == nil {
+		return nil, i18n.NewError("recurrence_needs_end_date")
+	}
+	
+	// Change priority and importance only when provided
+	if req.Priority != nil {
+		if !IsValidPriority(*req.Priority) {
+			return nil, i18n.NewError("task_priority_invalid")
+		}
+		updatedTask.Priority = *req.Priority
+	}
+	if req.Important != nil {
+		updatedTask.Important = *req.Important
+	}
+	
+	return s.save(ctx, userID, existingTask, &updatedTask)
+}
+
+// Transition moves a task along the state graph by action (start, complete, reset or reopen),
+// the only way to bring a completed task back. When IfMatch is set the task must still be at that version.
+func (s *service) Transition(ctx context.Context, taskID int64, userID int64, req TransitionRequest) (*TaskResponse, error) {
+	existingTask, _, err := s.authorize(ctx, taskID, userID, RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+	
+	if req.IfMatch != nil && *req.IfMatch != existingTask.Version {
+		return nil, ErrVersionMismatch
+	}
+	
+	from, err := s.stateOf(ctx, existingTask)
+	if err != nil {
+		return nil, err
+	}
+	stateID, err := resolveTransition(from, req.Action)
+	if err != nil {
+		return nil, err
+	}
+	
+	if !req.IgnoreBlockers {
+		if err := s.checkBlockers(ctx, existingTask, &stateID); err != nil {
+			return nil, err
+		}
+	}
+	
+	updatedTask := *existingTask
+	updatedTask.StateID = &stateID
+	return s.save(ctx, userID, existingTask, &updatedTask)
+}
+
+// Delete moves a task (only if it belongs to the user) and its subtasks to the trash.
+// When ifMatch is set the task must still be at that version.
+func (s *service) Delete(ctx context.Context, taskID int64, userID int64, ifMatch *int64) error {
+	existingTask, _, err := s.authorize(ctx, taskID, userID, RoleOwner)
+	if err != nil {
+		return err
+	}
+	
+	// Subtasks go to the trash together with their parent and are restored with it, unless the policy blocks deleting a parent
+	progress, err := s.repo.GetProgress(ctx, taskID)
+	if err != nil {
+		return err
+	}
+	if progress != nil && s.opts.DeleteParentPolicy == ParentPolicyBlock {
+		return i18n.NewError("task_has_subtasks")
+	}
+	
+	return s.withTx(ctx, func(tx *service) error {
+		deleted, err := tx.repo.GetDescendants(ctx, taskID)
+		if err != nil {
+			return err
+		}
+		deleted = append(deleted, existingTask)
+		
+		if err := tx.repo.Delete(ctx, taskID, ifMatch); err != nil {
+			return err
+		}
+		
+		for _, task := range deleted {
+			if err := tx.recordEvent(ctx, userID, task, nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// GetTrash retrieves the user's deleted tasks
+func (s *service) GetTrash(ctx context.Context, userID int64) ([]*TaskResponse, error) {
+	return s.repo.GetTrash(ctx, userID)
+}
+
+// Restore takes one of the user's tasks out of the trash, together with the subtasks deleted along with it
+func (s *service) Restore(ctx context.Context, taskID int64, userID int64) (*TaskResponse, error) {
+	if taskID <= 0 {
+		return nil, ErrInvalidTaskID
+	}
+	
+	task, err := s.repo.GetDeletedByID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if task == nil || task.UserID != userID {
+		return nil, ErrNotInTrash
+	}
+	
+	// A subtask can only come back under a live parent
+	if task.ParentID != nil {
+		parent, err := s.repo.GetByID(ctx, *task.ParentID)
+		if err != nil {
+			return nil, err
+		}
+		if parent == nil {
+			return nil, i18n.NewError("task_parent_deleted")
+		}
+	}
+	
+	err = s.withTx(ctx, func(tx *service) error {
+		restored, err := tx.repo.Restore(ctx, taskID)
+		if err != nil {
+			return err
+		}
+		
+		for _, id := range restored {
+			err := tx.repo.CreateEvent(ctx, &TaskEvent{
+				TaskID:  id,
+				UserID:  userID,
+				ActorID: userID,
+				Action:  EventRestored,
+				Changes: []FieldChange{},
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
 	
-	// Return the updated task with detailed information
 	return s.repo.GetByIDWithDetails(ctx, taskID)
 }
 
-// Delete removes a task (only if it belongs to the user)
-func (s *service) Delete(ctx context.Context, taskID int64, userID int64) error {
+// GetHistory retrieves the change history of a task, oldest first.
+// The history of a deleted task stays available to its owner only.
+func (s *service) GetHistory(ctx context.Context, taskID int64, userID int64) ([]*TaskEvent, error) {
 	if taskID <= 0 {
-		return errors.New("invalid task ID")
+		return nil, ErrInvalidTaskID
+	}
+	
+	events, err := s.repo.GetEvents(ctx, taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(events) == 0 {
+		// Tasks created before history was recorded have no events yet, and
+		// the history of a shared task is stored under its owner
+		task, _, err := s.authorize(ctx, taskID, userID, RoleViewer)
+		if err != nil {
+			return nil, err
+		}
+		if task.UserID != userID {
+			return s.repo.GetEvents(ctx, taskID, task.UserID)
+		}
+	}
+	
+	return events, nil
+}
+
+// GetChildren retrieves the direct subtasks of a task the user can see
+func (s *service) GetChildren(ctx context.Context, taskID int64, userID int64) ([]*TaskResponse, error) {
+	if _, _, err := s.authorize(ctx, taskID, userID, RoleViewer); err != nil {
+		return nil, err
+	}
+	
+	return s.repo.GetChildren(ctx, taskID)
+}
+
+// GetTree retrieves a task the user can see with all of its descendants nested below it
+func (s *service) GetTree(ctx context.Context, taskID int64, userID int64) (*TaskNode, error) {
+	if _, _, err := s.authorize(ctx, taskID, userID, RoleViewer); err != nil {
+		return nil, err
+	}
+	
+	subtree, err := s.repo.GetSubtree(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	
+	nodes := make(map[int64]*TaskNode, len(subtree))
+	for _, task := range subtree {
+		nodes[task.ID] = &TaskNode{TaskResponse: *task, Children: []*TaskNode{}}
+	}
+	
+	var root *TaskNode
+	for _, task := range subtree {
+		node := nodes[task.ID]
+		if task.ID == taskID {
+			root = node
+			continue
+		}
+		if task.ParentID != nil {
+			if parent, ok := nodes[*task.ParentID]; ok {
+				parent.Children = append(parent.Children, node)
+			}
+		}
+	}
+	if root == nil {
+		return nil, ErrTaskNotFound
+	}
+	
+	return root, nil
+}
+
+// validateParent checks that parentID may become the parent of taskID (0 for a task being created)
+// owned by userID
+func (s *service) validateParent(ctx context.Context, taskID int64, parentID int64, userID int64) error {
+	if parentID == taskID {
+		return i18n.NewError("task_own_parent")
+	}
+	
+	parent, err := s.repo.GetByID(ctx, parentID)
+	if err != nil {
+		return err
+	}
+	if parent == nil || parent.UserID != userID {
+		return i18n.NewError("task_parent_not_found")
+	}
+	
+	// Prevent cycles: the new parent cannot live below the task itself
+	if taskID > 0 {
+		isDescendant, err := s.repo.IsDescendant(ctx, taskID, parentID)
+		if err != nil {
+			return err
+		}
+		if isDescendant {
+			return i18n.NewError("task_move_below_subtask")
+		}
+	}
+	
+	return nil
+}
+
+// save persists an updated task inside a transaction, applying the rules that depend on what changed
+// and recording the change in the task history on behalf of actorID
+func (s *service) save(ctx context.Context, actorID int64, before *Task, after *Task) (*TaskResponse, error) {
+	var resp *TaskResponse
+	err := s.withTx(ctx, func(tx *service) error {
+		// Moving into a done state completes the task; state changes also maintain its timestamps
+		completing := false
+		if currentState(before.StateID) != currentState(after.StateID) {
+			from, err := tx.stateOf(ctx, before)
+			if err != nil {
+				return err
+			}
+			to, err := tx.stateOf(ctx, after)
+			if err != nil {
+				return err
+			}
+			stampState(after, from, to, time.Now())
+			completing = to.IsDone && !from.IsDone
+			
+			// A card changing columns goes to the bottom of the new one unless it was placed explicitly
+			if samePosition(before.Position, after.Position) {
+				if err := tx.placeLast(ctx, after); err != nil {
+					return err
+				}
+			}
+		}
+		
+		// Completing a parent either cascades to its subtasks or is blocked by unfinished ones
+		cascade := false
+		if completing {
+			progress, err := tx.repo.GetProgress(ctx, after.ID)
+			if err != nil {
+				return err
+			}
+			if progress != nil && progress.Completed < progress.Total {
+				if tx.opts.CompleteParentPolicy != ParentPolicyCascade {
+					return i18n.NewError("task_unfinished_subtasks")
+				}
+				cascade = true
+			}
+		}
+		
+		if _, err := tx.repo.Update(ctx, after); err != nil {
+			return err
+		}
+		if err := tx.recordEvent(ctx, actorID, before, after); err != nil {
+			return err
+		}
+		
+		if cascade {
+			if err := tx.completeDescendants(ctx, actorID, after.ID); err != nil {
+				return err
+			}
+		}
+		
+		// Completing an occurrence of a recurring series schedules the next one
+		if completing && after.Recurrence != nil {
+			if err := tx.createNextOccurrence(ctx, actorID, after); err != nil {
+				return err
+			}
+		}
+		
+		var err error
+		resp, err = tx.repo.GetByIDWithDetails(ctx, after.ID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	
+	return resp, nil
+}
+
+// completeDescendants completes every unfinished descendant of a task, recording each change
+func (s *service) completeDescendants(ctx context.Context, actorID int64, id int64) error {
+	descendants, err := s.repo.GetDescendants(ctx, id)
+	if err != nil {
+		return err
+	}
+	
+	completedIDs, err := s.repo.CompleteDescendants(ctx, id)
+	if err != nil {
+		return err
+	}
+	changed := make(map[int64]bool, len(completedIDs))
+	for _, completedID := range completedIDs {
+		changed[completedID] = true
 	}
 	
-	// Check if task exists and belongs to user
-	existingTask, err := s.repo.GetByID(ctx, taskID)
+	completed := StateCompleted
+	now := time.Now()
+	for _, before := range descendants {
+		if !changed[before.ID] {
+			continue
+		}
+		after := *before
+		after.StateID = &completed
+		after.CompletedAt = &now
+		if err := s.recordEvent(ctx, actorID, before, &after); err != nil {
+			return err
+		}
+	}
+	
+	return nil
+}
+
+// stateOf resolves the workflow state of a task, as seen by its owner
+func (s *service) stateOf(ctx context.Context, task *Task) (*StateInfo, error) {
+	state, err := s.repo.GetState(ctx, currentState(task.StateID), task.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if state == nil {
+		return nil, i18n.NewError("task_state_not_found")
+	}
+	
+	return state, nil
+}
+
+// checkStateChange validates moving a task to stateID by setting state_id: the state must be
+// a built-in one or belong to the task owner, and be reachable from the current state
+func (s *service) checkStateChange(ctx context.Context, task *Task, stateID int64) error {
+	to, err := s.repo.GetState(ctx, stateID, task.UserID)
 	if err != nil {
 		return err
 	}
-	if existingTask == nil {
-		return errors.New("task not found")
+	if to == nil {
+		return i18n.NewError("state_id_invalid")
+	}
+	
+	from, err := s.stateOf(ctx, task)
+	if err != nil {
+		return err
+	}
+	
+	return checkMove(from, to)
+}
+
+// Bulk executes a batch of create/update/delete/change_state/move_category operations.
+// In atomic mode (the default) every operation runs in one transaction and nothing is
+// persisted unless all of them succeed; in per_item mode each operation commits on its own.
+func (s *service) Bulk(ctx context.Context, userID int64, req BulkRequest) (*BulkResponse, error) {
+	mode := req.Mode
+	if mode == "" {
+		mode = BulkModeAtomic
+	}
+	if mode != BulkModeAtomic && mode != BulkModePerItem {
+		return nil, i18n.NewError("bulk_mode_invalid")
+	}
+	
+	if len(req.Operations) == 0 {
+		return nil, i18n.NewError("bulk_empty")
+	}
+	if len(req.Operations) > MaxBulkOperations {
+		return nil, i18n.NewError("bulk_too_large", MaxBulkOperations)
+	}
+	
+	// Resolve the user's role on every referenced task with a single query
+	var ids []int64
+	for _, op := range req.Operations {
+		if op.Op != BulkOpCreate && op.TaskID > 0 {
+			ids = append(ids, op.TaskID)
+		}
+	}
+	roles := map[int64]string{}
+	if len(ids) > 0 {
+		var err error
+		roles, err = s.repo.GetRoles(ctx, ids, userID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	
+	resp := &BulkResponse{
+		Mode:    mode,
+		Results: make([]BulkResult, len(req.Operations)),
+	}
+	invalid := false
+	for i, op := range req.Operations {
+		resp.Results[i] = BulkResult{Index: i, Op: op.Op, TaskID: op.TaskID}
+		if err := checkBulkOperation(op, roles); err != nil {
+			resp.Results[i].Code, resp.Results[i].Error = i18n.Describe(ctx, err)
+			invalid = true
+		}
+	}
+	
+	// Assignment hooks of created tasks run once their transaction commits, as in Import
+	var created []*Task
+	if mode == BulkModeAtomic {
+		// Refuse the whole batch if any operation is malformed or references a foreign task
+		if !invalid {
+			err := s.withTx(ctx, func(tx *service) error {
+				for i, op := range req.Operations {
+					task, err := tx.applyBulkOperation(ctx, userID, op, &created)
+					if err != nil {
+						resp.Results[i].Code, resp.Results[i].Error = i18n.Describe(ctx, err)
+						return err
+					}
+					resp.Results[i].Success = true
+					resp.Results[i].Task = task
+					if task != nil {
+						resp.Results[i].TaskID = task.ID
+					}
+				}
+				return nil
+			})
+			resp.Committed = err == nil
+		}
+		if !resp.Committed {
+			created = nil
+		}
+		
+		if !resp.Committed {
+			for i := range resp.Results {
+				resp.Results[i].Success = false
+				resp.Results[i].Task = nil
+				if resp.Results[i].Error == "" {
+					resp.Results[i].Code, resp.Results[i].Error = i18n.Describe(ctx, i18n.NewError("bulk_rolled_back"))
+				}
+			}
+		}
+	} else {
+		for i, op := range req.Operations {
+			if resp.Results[i].Error != "" {
+				continue
+			}
+			var task *TaskResponse
+			var opCreated []*Task
+			err := s.withTx(ctx, func(tx *service) error {
+				var err error
+				task, err = tx.applyBulkOperation(ctx, userID, op, &opCreated)
+				return err
+			})
+			if err != nil {
+				resp.Results[i].Code, resp.Results[i].Error = i18n.Describe(ctx, err)
+				continue
+			}
+			created = append(created, opCreated...)
+			resp.Results[i].Success = true
+			resp.Results[i].Task = task
+			if task != nil {
+				resp.Results[i].TaskID = task.ID
+			}
+		}
+		resp.Committed = true
+	}
+	
+	for _, task := range created {
+		s.notifyNewAssignee(ctx, userID, task)
+	}
+	
+	for _, result := range resp.Results {
+		if result.Success {
+			resp.Succeeded++
+		} else {
+			resp.Failed++
+		}
+	}
+	
+	return resp, nil
+}
+
+// checkBulkOperation validates the shape of a bulk operation and the user's role on the task it
+// references: deleting requires ownership, the other operations an editor role
+func checkBulkOperation(op BulkOperation, roles map[int64]string) error {
+	required := RoleEditor
+	switch op.Op {
+	case BulkOpCreate:
+		if op.TaskID != 0 {
+			return i18n.NewError("bulk_create_task_id")
+		}
+		return nil
+	case BulkOpUpdate, BulkOpMoveCategory:
+	case BulkOpDelete:
+		required = RoleOwner
+	case BulkOpChangeState:
+		if op.StateID == nil {
+			return i18n.NewError("bulk_state_required")
+		}
+	default:
+		return i18n.NewError("bulk_unknown_op", op.Op)
+	}
+	
+	if op.TaskID <= 0 {
+		return ErrInvalidTaskID
+	}
+	role, ok := roles[op.TaskID]
+	if !ok {
+		return ErrTaskNotFound
+	}
+	if !HasRole(role, required) {
+		return ErrPermissionDenied
+	}
+	return nil
+}
+
+// applyBulkOperation runs a single bulk operation through the regular service rules.
+// Tasks it creates are appended to created, to be notified after the commit.
+func (s *service) applyBulkOperation(ctx context.Context, userID int64, op BulkOperation, created *[]*Task) (*TaskResponse, error) {
+	switch op.Op {
+	case BulkOpCreate:
+		req := CreateTaskRequest{
+			TaskText:   op.TaskText,
+			EndDate:    op.EndDate,
+			CategoryID: op.CategoryID,
+			ParentID:   op.ParentID,
+			Assignee:   op.Assignee,
+			Priority:   op.Priority,
+			Important:  op.Important,
+		}
+		if op.Recurrence != nil {
+			req.Recurrence = *op.Recurrence
+		}
+		task, err := s.createTask(ctx, userID, req)
+		if err != nil {
+			return nil, err
+		}
+		*created = append(*created, task)
+		return s.repo.GetByIDWithDetails(ctx, task.ID)
+	case BulkOpUpdate:
+		return s.Update(ctx, op.TaskID, userID, UpdateTaskRequest{
+			TaskText:       op.TaskText,
+			CategoryID:     op.CategoryID,
+			EndDate:        op.EndDate,
+			StateID:        op.StateID,
+			ParentID:       op.ParentID,
+			Recurrence:     op.Recurrence,
+			Priority:       op.Priority,
+			Important:      op.Important,
+			IgnoreBlockers: op.IgnoreBlockers,
+		})
+	case BulkOpDelete:
+		return nil, s.Delete(ctx, op.TaskID, userID, nil)
+	case BulkOpChangeState:
+		return s.changeState(ctx, op.TaskID, userID, *op.StateID, op.IgnoreBlockers)
+	case BulkOpMoveCategory:
+		return s.moveCategory(ctx, op.TaskID, userID, op.CategoryID)
+	default:
+		return nil, i18n.NewError("bulk_unknown_op", op.Op)
+	}
+}
+
+// changeState moves a task to another state, leaving every other field untouched
+func (s *service) changeState(ctx context.Context, taskID int64, userID int64, stateID int64, ignoreBlockers bool) (*TaskResponse, error) {
+	existingTask, _, err := s.authorize(ctx, taskID, userID, RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+	
+	if err := s.checkStateChange(ctx, existingTask, stateID); err != nil {
+		return nil, err
+	}
+	
+	if !ignoreBlockers {
+		if err := s.checkBlockers(ctx, existingTask, &stateID); err != nil {
+			return nil, err
+		}
+	}
+	
+	updatedTask := *existingTask
+	updatedTask.StateID = &stateID
+	return s.save(ctx, userID, existingTask, &updatedTask)
+}
+
+// moveCategory assigns a task to another category (nil removes the category)
+func (s *service) moveCategory(ctx context.Context, taskID int64, userID int64, categoryID *int64) (*TaskResponse, error) {
+	existingTask, _, err := s.authorize(ctx, taskID, userID, RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+	
+	if categoryID != nil {
+		exists, err := s.catRepo.Exists(ctx, *categoryID)
+		if err != nil {
+			return nil, errors.New("failed to validate category")
+		}
+		if !exists {
+			return nil, i18n.NewError("category_missing")
+		}
 	}
-	if existingTask.UserID != userID {
-		return errors.New("task not found")
+	
+	updatedTask := *existingTask
+	updatedTask.CategoryID = categoryID
+	return s.save(ctx, userID, existingTask, &updatedTask)
+}
+
+// withTx runs fn with a copy of the service whose repository is bound to one transaction
+func (s *service) withTx(ctx context.Context, fn func(tx *service) error) error {
+	return s.repo.WithTx(ctx, func(repo Repository) error {
+		tx := *s
+		tx.repo = repo
+		return fn(&tx)
+	})
+}
+
+// AddBlocker records that a task cannot start or be completed until blockerID is completed
+func (s *service) AddBlocker(ctx context.Context, taskID int64, userID int64, blockerID int64) (*TaskResponse, error) {
+	err := s.withTx(ctx, func(tx *service) error {
+		task, _, err := tx.authorize(ctx, taskID, userID, RoleEditor)
+		if err != nil {
+			return err
+		}
+		
+		if blockerID == taskID {
+			return i18n.NewError("dependency_self")
+		}
+		
+		// The blocker must belong to the same owner and be visible to the user
+		blocker, _, err := tx.authorize(ctx, blockerID, userID, RoleViewer)
+		if err != nil && !errors.Is(err, ErrTaskNotFound) {
+			return err
+		}
+		if blocker == nil || blocker.UserID != task.UserID {
+			return i18n.NewError("blocker_not_found")
+		}
+		
+		// Serialize dependency changes of the owner so a concurrent request adding another edge
+		// waits for this one and then sees it in the cycle check
+		if err := tx.repo.LockDependencies(ctx, task.UserID); err != nil {
+			return err
+		}
+		
+		// Reject the dependency if the blocker already waits, directly or transitively, on this task
+		cycle, err := tx.repo.DependsOn(ctx, blockerID, taskID)
+		if err != nil {
+			return err
+		}
+		if cycle {
+			return ErrDependencyCycle
+		}
+		
+		return tx.repo.AddDependency(ctx, taskID, blockerID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	
+	return s.repo.GetByIDWithDetails(ctx, taskID)
+}
+
+// RemoveBlocker deletes a dependency between a task and one of its blockers
+func (s *service) RemoveBlocker(ctx context.Context, taskID int64, userID int64, blockerID int64) (*TaskResponse, error) {
+	if _, _, err := s.authorize(ctx, taskID, userID, RoleEditor); err != nil {
+		return nil, err
 	}
 	
-	return s.repo.Delete(ctx, taskID)
-}
\ No newline at end of file
+	if err := s.repo.RemoveDependency(ctx, taskID, blockerID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, i18n.NewError("dependency_not_found")
+		}
+		return nil, err
+	}
+	
+	return s.repo.GetByIDWithDetails(ctx, taskID)
+}
+
+// checkBlockers refuses to move a task out of Not Started (into progress, a custom state or
+// completion) while any of its blockers is unfinished
+func (s *service) checkBlockers(ctx context.Context, task *Task, stateID *int64) error {
+	if stateID == nil || *stateID == StateNotStarted {
+		return nil
+	}
+	if currentState(task.StateID) == *stateID {
+		return nil
+	}
+	
+	blockers, err := s.repo.GetBlockers(ctx, task.ID)
+	if err != nil {
+		return err
+	}
+	
+	var pending []string
+	for _, blocker := range blockers {
+		if !blocker.Completed {
+			pending = append(pending, strconv.FormatInt(blocker.ID, 10))
+		}
+	}
+	if len(pending) > 0 {
+		return i18n.NewError("task_blocked", strings.Join(pending, ", "))
+	}
+	
+	return nil
+}
+
+// GetSeries retrieves every occurrence of a recurring series owned by the user
+func (s *service) GetSeries(ctx context.Context, seriesID int64, userID int64) ([]*TaskResponse, error) {
+	if seriesID <= 0 {
+		return nil, i18n.NewError("series_id_invalid")
+	}
+	
+	series, err := s.repo.GetSeries(ctx, seriesID)
+	if err != nil {
+		return nil, err
+	}
+	if len(series) == 0 || series[0].UserID != userID {
+		return nil, i18n.NewError("series_not_found")
+	}
+	
+	return series, nil
+}
+
+// UpdateSeries edits the text, category and rule of every unfinished occurrence of a series
+func (s *service) UpdateSeries(ctx context.Context, seriesID int64, userID int64, req UpdateSeriesRequest) ([]*TaskResponse, error) {
+	if _, err := s.GetSeries(ctx, seriesID, userID); err != nil {
+		return nil, err
+	}
+	
+	taskText := strings.TrimSpace(req.TaskText)
+	if taskText == "" {
+		return nil, i18n.NewError("task_text_empty")
+	}
+	
+	if len(taskText) > 1000 {
+		return nil, i18n.NewError("task_text_too_long")
+	}
+	
+	if req.CategoryID != nil {
+		exists, err := s.catRepo.Exists(ctx, *req.CategoryID)
+		if err != nil {
+			return nil, errors.New("failed to validate category")
+		}
+		if !exists {
+			return nil, i18n.NewError("category_missing")
+		}
+	}
+	
+	recurrence, err := normalizeRecurrence(req.Recurrence)
+	if err != nil {
+		return nil, err
+	}
+	if recurrence == nil {
+		return nil, i18n.NewError("recurrence_empty_stop")
+	}
+	
+	err = s.withTx(ctx, func(tx *service) error {
+		occurrences, err := tx.repo.GetSeriesTasks(ctx, seriesID)
+		if err != nil {
+			return err
+		}
+		
+		updatedIDs, err := tx.repo.UpdateSeries(ctx, seriesID, taskText, req.CategoryID, *recurrence)
+		if err != nil {
+			return err
+		}
+		updated := make(map[int64]bool, len(updatedIDs))
+		for _, id := range updatedIDs {
+			updated[id] = true
+		}
+		
+		for _, before := range occurrences {
+			if !updated[before.ID] {
+				continue
+			}
+			after := *before
+			after.TaskText = taskText
+			after.CategoryID = req.CategoryID
+			after.Recurrence = recurrence
+			if err := tx.recordEvent(ctx, userID, before, &after); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	
+	return s.repo.GetSeries(ctx, seriesID)
+}
+
+// StopSeries ends a recurring series: existing occurrences are kept but no new ones are generated
+func (s *service) StopSeries(ctx context.Context, seriesID int64, userID int64) ([]*TaskResponse, error) {
+	if _, err := s.GetSeries(ctx, seriesID, userID); err != nil {
+		return nil, err
+	}
+	
+	err := s.withTx(ctx, func(tx *service) error {
+		occurrences, err := tx.repo.GetSeriesTasks(ctx, seriesID)
+		if err != nil {
+			return err
+		}
+		
+		if err := tx.repo.StopSeries(ctx, seriesID); err != nil {
+			return err
+		}
+		
+		for _, before := range occurrences {
+			after := *before
+			after.Recurrence = nil
+			if err := tx.recordEvent(ctx, userID, before, &after); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	
+	return s.repo.GetSeries(ctx, seriesID)
+}
+
+// createNextOccurrence creates the occurrence that follows a completed task of a recurring series.
+// Occurrences that would already be overdue are skipped, and nothing is created once COUNT or UNTIL is reached.
+func (s *service) createNextOccurrence(ctx context.Context, actorID int64, task *Task) error {
+	rule, err := recurrence.Parse(*task.Recurrence)
+	if err != nil {
+		return err
+	}
+	
+	seriesID := task.ID
+	if task.SeriesID != nil {
+		seriesID = *task.SeriesID
+	}
+	
+	// An occurrence completed again after being reopened already scheduled the next one
+	occurrences, err := s.repo.GetSeriesTasks(ctx, seriesID)
+	if err != nil {
+		return err
+	}
+	for _, other := range occurrences {
+		if other.Occurrence > task.Occurrence {
+			return nil
+		}
+	}
+	
+	today := time.Now().Truncate(24 * time.Hour)
+	next := today
+	if task.EndDate != nil {
+		next = *task.EndDate
+	}
+	
+	occurrence := task.Occurrence
+	for {
+		occurrence++
+		if rule.Count > 0 && occurrence > rule.Count {
+			return nil
+		}
+		
+		var ok bool
+		next, ok = rule.Next(next)
+		if !ok {
+			return nil
+		}
+		if !next.Before(today) {
+			break
+		}
+	}
+	
+	created, err := s.repo.Create(ctx, &Task{
+		TaskText:   task.TaskText,
+		EndDate:    &next,
+		CategoryID: task.CategoryID,
+		UserID:     task.UserID,
+		ParentID:   task.ParentID,
+		Recurrence: task.Recurrence,
+		SeriesID:   &seriesID,
+		Occurrence: occurrence,
+		Priority:   task.Priority,
+		Important:  task.Important,
+		CreatedBy:   task.CreatedBy,
+		AssigneeID:  task.AssigneeID,
+		WorkspaceID: task.WorkspaceID,
+	})
+	if err != nil {
+		return err
+	}
+	
+	return s.recordEvent(ctx, actorID, nil, created)
+}
+
+// normalizeRecurrence validates an RRULE and returns its canonical form, or nil for an empty rule
+func normalizeRecurrence(value string) (*string, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+	
+	rule, err := recurrence.Parse(value)
+	if err != nil {
+		return nil, i18n.NewError("recurrence_invalid", err)
+	}
+	
+	canonical := rule.String()
+	return &canonical, nil
+}
+// sameDate reports whether an optional end date is the given day
+func sameDate(date *time.Time, day time.Time) bool {
+	return date != nil && date.Format("2006-01-02") == day.Format("2006-01-02")
+}
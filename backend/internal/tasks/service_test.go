@@ -0,0 +1,202 @@
+package tasks
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// fakeStates are the built-in states the fake repository knows
+var fakeStates = map[int64]*StateInfo{
+	StateNotStarted: {ID: StateNotStarted, Description: "Not Started"},
+	StateInProgress: {ID: StateInProgress, Description: "In Progress"},
+	StateCompleted:  {ID: StateCompleted, Description: "Completed", IsDone: true},
+}
+
+// fakeRepo keeps tasks in memory and records the tasks the service creates and updates
+type fakeRepo struct {
+	Repository
+	tasks   map[int64]*Task
+	nextID  int64
+	created []*Task
+	updated []*Task
+}
+
+func newFakeRepo(tasks ...*Task) *fakeRepo {
+	repo := &fakeRepo{tasks: make(map[int64]*Task)}
+	for _, task := range tasks {
+		repo.tasks[task.ID] = task
+		if task.ID > repo.nextID {
+			repo.nextID = task.ID
+		}
+	}
+	return repo
+}
+
+func (r *fakeRepo) WithTx(ctx context.Context, fn func(repo Repository) error) error {
+	return fn(r)
+}
+
+func (r *fakeRepo) GetByID(ctx context.Context, id int64) (*Task, error) {
+	return r.tasks[id], nil
+}
+
+func (r *fakeRepo) GetByIDWithDetails(ctx context.Context, id int64) (*TaskResponse, error) {
+	task := r.tasks[id]
+	return &TaskResponse{ID: task.ID, TaskText: task.TaskText, ParentID: task.ParentID}, nil
+}
+
+func (r *fakeRepo) IsDescendant(ctx context.Context, ancestorID int64, id int64) (bool, error) {
+	return false, nil
+}
+
+func (r *fakeRepo) Create(ctx context.Context, task *Task) (*Task, error) {
+	r.nextID++
+	saved := *task
+	saved.ID = r.nextID
+	r.tasks[saved.ID] = &saved
+	r.created = append(r.created, &saved)
+	return &saved, nil
+}
+
+func (r *fakeRepo) GetState(ctx context.Context, id int64, userID int64) (*StateInfo, error) {
+	return fakeStates[id], nil
+}
+
+func (r *fakeRepo) GetProgress(ctx context.Context, id int64) (*TaskProgress, error) {
+	return nil, nil
+}
+
+func (r *fakeRepo) GetBlockers(ctx context.Context, taskID int64) ([]BlockerInfo, error) {
+	return nil, nil
+}
+
+func (r *fakeRepo) LastPosition(ctx context.Context, column Column) (string, error) {
+	return "", nil
+}
+
+func (r *fakeRepo) GetSeriesTasks(ctx context.Context, seriesID int64) ([]*Task, error) {
+	var series []*Task
+	for _, task := range r.tasks {
+		if task.SeriesID != nil && *task.SeriesID == seriesID {
+			series = append(series, task)
+		}
+	}
+	return series, nil
+}
+
+func (r *fakeRepo) Update(ctx context.Context, task *Task) (*Task, error) {
+	saved := *task
+	r.tasks[task.ID] = &saved
+	r.updated = append(r.updated, &saved)
+	return &saved, nil
+}
+
+func (r *fakeRepo) CreateEvent(ctx context.Context, event *TaskEvent) error {
+	return nil
+}
+
+func id(v int64) *int64 {
+	return &v
+}
+
+func TestUpdateParent(t *testing.T) {
+	tests := []struct {
+		name     string
+		parentID *int64
+		want     *int64
+	}{
+		{name: "omitting parent_id keeps the current parent", parentID: nil, want: id(1)},
+		{name: "parent_id 0 detaches the task", parentID: id(0), want: nil},
+		{name: "another parent_id moves the task", parentID: id(2), want: id(2)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeRepo(
+				&Task{ID: 1, TaskText: "Move house", UserID: 10},
+				&Task{ID: 2, TaskText: "Renovate", UserID: 10},
+				&Task{ID: 3, TaskText: "Pack boxes", UserID: 10, ParentID: id(1)},
+			)
+			svc := NewService(repo, nil, nil, Options{})
+
+			resp, err := svc.Update(context.Background(), 3, 10, UpdateTaskRequest{TaskText: "Pack the boxes", ParentID: tt.parentID})
+			if err != nil {
+				t.Fatalf("Update returned %v", err)
+			}
+
+			if got := repo.tasks[3].ParentID; !sameID(got, tt.want) {
+				t.Errorf("saved parent_id = %v, want %v", idString(got), idString(tt.want))
+			}
+			if !sameID(resp.ParentID, tt.want) {
+				t.Errorf("response parent_id = %v, want %v", idString(resp.ParentID), idString(tt.want))
+			}
+		})
+	}
+}
+
+func TestCompletingRecurringTask(t *testing.T) {
+	tests := []struct {
+		name       string
+		recurrence string
+		occurrence int
+		actions    []string
+		want       int // occurrences created
+	}{
+		{
+			name:       "completing an occurrence schedules the next one",
+			recurrence: "FREQ=DAILY",
+			occurrence: 1,
+			actions:    []string{ActionComplete},
+			want:       1,
+		},
+		{
+			name:       "completing again after reopening does not schedule another one",
+			recurrence: "FREQ=DAILY",
+			occurrence: 1,
+			actions:    []string{ActionComplete, ActionReopen, ActionComplete},
+			want:       1,
+		},
+		{
+			name:       "the last occurrence of a COUNT schedules none",
+			recurrence: "FREQ=DAILY;COUNT=2",
+			occurrence: 2,
+			actions:    []string{ActionComplete},
+			want:       0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			today := time.Now().UTC().Truncate(24 * time.Hour)
+			repo := newFakeRepo(&Task{
+				ID: 1, TaskText: "Water plants", UserID: 10, StateID: id(StateNotStarted), EndDate: &today,
+				Recurrence: &tt.recurrence, SeriesID: id(1), Occurrence: tt.occurrence,
+			})
+			svc := NewService(repo, nil, nil, Options{})
+
+			for _, action := range tt.actions {
+				if _, err := svc.Transition(context.Background(), 1, 10, TransitionRequest{Action: action}); err != nil {
+					t.Fatalf("Transition(%s) returned %v", action, err)
+				}
+			}
+
+			if len(repo.created) != tt.want {
+				t.Fatalf("created %d occurrences, want %d", len(repo.created), tt.want)
+			}
+			for _, next := range repo.created {
+				if next.Occurrence != tt.occurrence+1 || !next.EndDate.After(today) {
+					t.Errorf("next occurrence = %d due %s, want %d after %s", next.Occurrence, next.EndDate, tt.occurrence+1, today)
+				}
+			}
+		})
+	}
+}
+
+// idString formats an optional ID for failure messages
+func idString(v *int64) interface{} {
+	if v == nil {
+		return nil
+	}
+	return *v
+}
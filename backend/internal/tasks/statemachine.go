@@ -0,0 +1,161 @@
+package tasks
+
+import (
+	"errors"
+	"time"
+
+	"backend/root/internal/i18n"
+)
+
+// State classes group workflow states for the transition graph, so that user-defined
+// states follow the same rules as the built-in ones
+const (
+	ClassNotStarted = "not_started" // the built-in Not Started state
+	ClassOpen       = "open"        // any other state that is not flagged as done
+	ClassDone       = "done"        // any state flagged as done
+)
+
+// Transition is an edge of the task state graph: Action moves a task in any of the From classes to To
+type Transition struct {
+	Action string
+	From   []string
+	To     int64
+}
+
+// Transition actions
+const (
+	ActionStart    = "start"    // Not Started -> In Progress
+	ActionComplete = "complete" // any open state -> Completed
+	ActionReset    = "reset"    // any open state -> Not Started
+	ActionReopen   = "reopen"   // any done state -> Not Started
+)
+
+// transitions are the actions of POST /tasks/:id/transition
+var transitions = []Transition{
+	{Action: ActionStart, From: []string{ClassNotStarted}, To: StateInProgress},
+	{Action: ActionComplete, From: []string{ClassNotStarted, ClassOpen}, To: StateCompleted},
+	{Action: ActionReset, From: []string{ClassOpen}, To: StateNotStarted},
+	{Action: ActionReopen, From: []string{ClassDone}, To: StateNotStarted},
+}
+
+// moves lists the classes a task may move to by setting state_id.
+// A finished task only comes back through an explicit reopen.
+var moves = map[string][]string{
+	ClassNotStarted: {ClassOpen, ClassDone},
+	ClassOpen:       {ClassNotStarted, ClassOpen, ClassDone},
+	ClassDone:       {ClassDone},
+}
+
+// TransitionError is returned when a task cannot move between two states, or an action
+// does not apply to the state the task is in
+type TransitionError struct {
+	From   string
+	To     string
+	Action string
+	Hint   string // Action that applies instead, such as reopen
+}
+
+func (e *TransitionError) Error() string {
+	return e.Unwrap().Error()
+}
+
+// Unwrap returns the coded message of the error, which LocalizeErrors renders in the request locale
+func (e *TransitionError) Unwrap() error {
+	switch {
+	case e.Action != "":
+		return i18n.NewError("task_transition_action", e.Action, e.From)
+	case e.Hint != "":
+		return i18n.NewError("task_transition_illegal_hint", e.From, e.To, e.Hint)
+	default:
+		return i18n.NewError("task_transition_illegal", e.From, e.To)
+	}
+}
+
+// IsTransitionError reports whether err is an illegal state transition
+func IsTransitionError(err error) bool {
+	var transitionErr *TransitionError
+	return errors.As(err, &transitionErr)
+}
+
+// currentState returns the state of a task, treating tasks without a state as Not Started
+func currentState(stateID *int64) int64 {
+	if stateID == nil {
+		return StateNotStarted
+	}
+	return *stateID
+}
+
+// classOf returns the class of a workflow state in the transition graph
+func classOf(state *StateInfo) string {
+	switch {
+	case state.IsDone:
+		return ClassDone
+	case state.ID == StateNotStarted:
+		return ClassNotStarted
+	default:
+		return ClassOpen
+	}
+}
+
+// checkMove validates moving a task between two states by setting state_id.
+// Staying in the same state is always allowed.
+func checkMove(from *StateInfo, to *StateInfo) error {
+	if from.ID == to.ID {
+		return nil
+	}
+
+	if contains(moves[classOf(from)], classOf(to)) {
+		return nil
+	}
+
+	err := &TransitionError{From: from.Description, To: to.Description}
+	if classOf(from) == ClassDone {
+		err.Hint = ActionReopen
+	}
+	return err
+}
+
+// resolveTransition returns the state an action moves a task in the given state to
+func resolveTransition(from *StateInfo, action string) (int64, error) {
+	for _, t := range transitions {
+		if t.Action != action {
+			continue
+		}
+		if !contains(t.From, classOf(from)) {
+			return 0, &TransitionError{From: from.Description, Action: action}
+		}
+		return t.To, nil
+	}
+
+	return 0, i18n.NewError("task_transition_unknown", action)
+}
+
+// contains reports whether class is one of classes
+func contains(classes []string, class string) bool {
+	for _, c := range classes {
+		if c == class {
+			return true
+		}
+	}
+	return false
+}
+
+// stampState maintains the started_at and completed_at timestamps of a task that moved between two states:
+// started_at is set the first time the task leaves Not Started and cleared when it goes back,
+// completed_at is set when the task becomes done and cleared whenever it is open
+func stampState(after *Task, from *StateInfo, to *StateInfo, now time.Time) {
+	switch classOf(to) {
+	case ClassNotStarted:
+		after.StartedAt = nil
+		after.CompletedAt = nil
+	case ClassOpen:
+		if after.StartedAt == nil {
+			after.StartedAt = &now
+		}
+		after.CompletedAt = nil
+	case ClassDone:
+		if classOf(from) != ClassDone {
+			after.CompletedAt = &now
+		}
+	}
+}
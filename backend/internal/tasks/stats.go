@@ -0,0 +1,67 @@
+package tasks
+
+import (
+	"context"
+	"time"
+
+	"backend/root/internal/i18n"
+)
+
+// StatsRequestError is returned when the parameters of a statistics request are invalid
+type StatsRequestError struct {
+	Err error // Coded message of the error
+}
+
+func (e *StatsRequestError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *StatsRequestError) Unwrap() error {
+	return e.Err
+}
+
+// GetStats summarizes the tasks of the current space: counts by state and category, overdue
+// tasks, the completion rate, the average time to completion, the current completion streak
+// and a timeline of the tasks created and completed per day or week.
+func (s *service) GetStats(ctx context.Context, userID int64, req StatsRequest) (*Stats, error) {
+	interval := req.Interval
+	if interval == "" {
+		interval = IntervalDay
+	}
+	if interval != IntervalDay && interval != IntervalWeek {
+		return nil, &StatsRequestError{Err: i18n.NewError("task_stats_interval_invalid")}
+	}
+
+	to := time.Now().UTC().Truncate(24 * time.Hour)
+	if req.To != nil {
+		to = *req.To
+	}
+	from := to.AddDate(0, 0, -29)
+	if interval == IntervalWeek {
+		from = to.AddDate(0, 0, -7*12+1)
+	}
+	if req.From != nil {
+		from = *req.From
+	}
+
+	if from.After(to) {
+		return nil, &StatsRequestError{Err: i18n.NewError("time_range_order")}
+	}
+	if to.Sub(from) >= MaxStatsDays*24*time.Hour {
+		return nil, &StatsRequestError{Err: i18n.NewError("time_range_too_long")}
+	}
+
+	stats, err := s.repo.GetStats(ctx, userID, from, to, interval)
+	if err != nil {
+		return nil, err
+	}
+
+	if stats.Total > 0 {
+		stats.CompletionRate = float64(stats.Completed) / float64(stats.Total)
+	}
+	stats.From = from.Format("2006-01-02")
+	stats.To = to.Format("2006-01-02")
+	stats.Interval = interval
+
+	return stats, nil
+}
@@ -0,0 +1,256 @@
+package tasks
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"backend/root/internal/i18n"
+)
+
+// todo.txt priorities: (A) is high, (B) medium and (C) low. Lower letters import as low.
+const (
+	todoTxtPriorityHigh   = "A"
+	todoTxtPriorityMedium = "B"
+	todoTxtPriorityLow    = "C"
+)
+
+// writeTasksTodoTxt writes tasks in the todo.txt format, one per line:
+//
+//	x 2024-01-16 2024-01-10 (A) Task text +Category @tag due:2024-01-20
+//
+// Completed tasks start with x and their completion date, and keep their priority as pri:A,
+// since todo.txt has no priority for them. Spaces in category and tag names become underscores.
+// Words of the text that would read back as markers, such as +word or due:, are escaped with a
+// backslash, as are underscores in names; parseImportTodoTxt removes the escapes.
+func writeTasksTodoTxt(w io.Writer, tasks []*TaskResponse) error {
+	writer := bufio.NewWriter(w)
+
+	for _, task := range tasks {
+		var parts []string
+		completed := task.State != nil && task.State.IsDone
+		priority := todoTxtPriority(task.Priority)
+
+		if completed {
+			parts = append(parts, "x")
+			if task.CompletedAt != nil {
+				parts = append(parts, task.CompletedAt.UTC().Format("2006-01-02"))
+			}
+		} else if priority != "" {
+			parts = append(parts, "("+priority+")")
+		}
+		if !completed || task.CompletedAt != nil {
+			parts = append(parts, task.CreationDate.UTC().Format("2006-01-02"))
+		}
+
+		parts = append(parts, escapeTaskText(task.TaskText, isTodoTxtMarker))
+		if task.Category != nil {
+			parts = append(parts, "+"+todoTxtName(task.Category.Name))
+		}
+		for _, tag := range task.Tags {
+			parts = append(parts, "@"+todoTxtName(tag.Name))
+		}
+		if task.EndDate != nil {
+			parts = append(parts, "due:"+task.EndDate.Format("2006-01-02"))
+		}
+		if completed && priority != "" {
+			parts = append(parts, "pri:"+priority)
+		}
+
+		if _, err := writer.WriteString(strings.Join(parts, " ") + "\n"); err != nil {
+			return err
+		}
+	}
+
+	return writer.Flush()
+}
+
+// parseImportTodoTxt reads the tasks of a todo.txt file. The first +project is the category and
+// every @context a tag; further projects stay in the text. Blank lines are skipped and the
+// creation date is ignored, since imported tasks are created at the time of the import.
+func parseImportTodoTxt(r io.Reader) ([]ImportRow, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), MaxImportBytes)
+
+	var rows []ImportRow
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if line == 1 {
+			text = strings.TrimPrefix(text, "\ufeff") // Byte order mark written by some editors
+		}
+		if text == "" {
+			continue
+		}
+		if len(rows) == MaxImportRows {
+			return nil, i18n.NewError("task_import_file_too_many")
+		}
+
+		row, err := parseTodoTxtLine(text)
+		if err != nil {
+			return nil, i18n.NewError("task_import_todotxt_line", line, err)
+		}
+		row.Line = line
+		rows = append(rows, row)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, i18n.NewError("task_import_file_read_failed")
+	}
+
+	if len(rows) == 0 {
+		return nil, i18n.NewError("task_import_file_no_tasks")
+	}
+
+	return rows, nil
+}
+
+// parseTodoTxtLine reads a single todo.txt task
+func parseTodoTxtLine(line string) (ImportRow, error) {
+	var row ImportRow
+	fields := strings.Fields(line)
+
+	if len(fields) > 0 && fields[0] == "x" {
+		row.Completed = true
+		fields = fields[1:]
+		// The completion date comes first and is only followed by the creation date
+		if len(fields) > 0 && isTodoTxtDate(fields[0]) {
+			row.CompletedAt = fields[0]
+			fields = fields[1:]
+		}
+	} else if len(fields) > 0 && isTodoTxtPriority(fields[0]) {
+		row.Priority = strconv.Itoa(taskPriorityFromTodoTxt(fields[0][1:2]))
+		fields = fields[1:]
+	}
+	if len(fields) > 0 && isTodoTxtDate(fields[0]) {
+		fields = fields[1:] // creation date
+	}
+
+	words := make([]string, 0, len(fields))
+	for _, field := range fields {
+		switch {
+		case len(field) > 1 && field[0] == '\\':
+			words = append(words, field[1:]) // Escaped word, taken as written
+		case len(field) > 1 && field[0] == '+' && row.Category == "":
+			row.Category = fromTodoTxtName(field[1:])
+		case len(field) > 1 && field[0] == '@':
+			row.Tags = appendName(row.Tags, fromTodoTxtName(field[1:]))
+		case strings.HasPrefix(field, "due:") && len(field) > len("due:"):
+			row.EndDate = strings.TrimPrefix(field, "due:")
+		case strings.HasPrefix(field, "pri:") && len(field) == len("pri:")+1:
+			letter := strings.ToUpper(field[len("pri:"):])
+			if letter < "A" || letter > "Z" {
+				return row, i18n.NewError("task_import_pri_invalid")
+			}
+			row.Priority = strconv.Itoa(taskPriorityFromTodoTxt(letter))
+		default:
+			words = append(words, field)
+		}
+	}
+	row.TaskText = strings.Join(words, " ")
+
+	return row, nil
+}
+
+// todoTxtPriority returns the todo.txt letter of a task priority, "" for none
+func todoTxtPriority(priority int) string {
+	switch priority {
+	case PriorityHigh:
+		return todoTxtPriorityHigh
+	case PriorityMedium:
+		return todoTxtPriorityMedium
+	case PriorityLow:
+		return todoTxtPriorityLow
+	default:
+		return ""
+	}
+}
+
+// taskPriorityFromTodoTxt returns the task priority of a todo.txt letter
+func taskPriorityFromTodoTxt(letter string) int {
+	switch letter {
+	case todoTxtPriorityHigh:
+		return PriorityHigh
+	case todoTxtPriorityMedium:
+		return PriorityMedium
+	default:
+		return PriorityLow
+	}
+}
+
+// isTodoTxtPriority reports whether a word is a priority such as (A)
+func isTodoTxtPriority(word string) bool {
+	return len(word) == 3 && word[0] == '(' && word[2] == ')' && word[1] >= 'A' && word[1] <= 'Z'
+}
+
+// isTodoTxtDate reports whether a word is a date in the YYYY-MM-DD format
+func isTodoTxtDate(word string) bool {
+	_, err := time.Parse("2006-01-02", word)
+	return err == nil
+}
+
+// isTodoTxtMarker reports whether a word of a task text would be read as a todo.txt marker. The
+// completion mark, a priority and a date only count as the first word of the text, which follows
+// them when the task has no dates.
+func isTodoTxtMarker(word string, first bool) bool {
+	if first && (word == "x" || isTodoTxtPriority(word) || isTodoTxtDate(word)) {
+		return true
+	}
+	return (len(word) > 1 && (word[0] == '+' || word[0] == '@')) ||
+		(strings.HasPrefix(word, "due:") && len(word) > len("due:")) ||
+		(strings.HasPrefix(word, "pri:") && len(word) == len("pri:")+1)
+}
+
+// escapeTaskText normalizes the spaces of a task text and escapes with a backslash the words
+// that isMarker reports, and those already starting with one, so that they import as text
+func escapeTaskText(text string, isMarker func(word string, first bool) bool) string {
+	words := strings.Fields(text)
+	for i, word := range words {
+		if word[0] == '\\' || isMarker(word, i == 0) {
+			words[i] = `\` + word
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// todoTxtName writes a category or tag name as a single word, replacing spaces with underscores
+// and escaping the underscores and backslashes of the name with a backslash
+func todoTxtName(name string) string {
+	name = strings.NewReplacer(`\`, `\\`, "_", `\_`).Replace(name)
+	return strings.Join(strings.Fields(name), "_")
+}
+
+// fromTodoTxtName reverses todoTxtName
+func fromTodoTxtName(word string) string {
+	var name strings.Builder
+	escaped := false
+	for _, r := range word {
+		switch {
+		case escaped:
+			name.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '_':
+			name.WriteRune(' ')
+		default:
+			name.WriteRune(r)
+		}
+	}
+	if escaped {
+		name.WriteRune('\\')
+	}
+	return name.String()
+}
+
+// appendName adds a name to a list unless it already holds it, ignoring case
+func appendName(names []string, name string) []string {
+	for _, existing := range names {
+		if strings.EqualFold(existing, name) {
+			return names
+		}
+	}
+	return append(names, name)
+}
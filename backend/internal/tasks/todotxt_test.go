@@ -0,0 +1,127 @@
+package tasks
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var (
+	doneState = &StateInfo{ID: 3, Description: "Done", IsDone: true}
+	openState = &StateInfo{ID: 1, Description: "To Do"}
+)
+
+// date returns a pointer to midnight UTC of a day
+func date(year int, month time.Month, day int) *time.Time {
+	t := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	return &t
+}
+
+// tags returns the TagInfo of tag names
+func tags(names ...string) []TagInfo {
+	infos := make([]TagInfo, len(names))
+	for i, name := range names {
+		infos[i] = TagInfo{ID: int64(i + 1), Name: name}
+	}
+	return infos
+}
+
+func TestTodoTxtRoundTrip(t *testing.T) {
+	created := *date(2024, 1, 10)
+
+	tests := []struct {
+		name string
+		task TaskResponse
+		want ImportRow
+	}{
+		{
+			name: "open task with every field",
+			task: TaskResponse{
+				TaskText: "Pay rent", State: openState, Priority: PriorityHigh, EndDate: date(2024, 1, 20),
+				Category: &CategoryInfo{Name: "Home Office"}, Tags: tags("bills", "due soon"),
+			},
+			want: ImportRow{TaskText: "Pay rent", Priority: "3", EndDate: "2024-01-20", Category: "Home Office", Tags: []string{"bills", "due soon"}},
+		},
+		{
+			name: "completed task keeps its completion date and priority",
+			task: TaskResponse{TaskText: "File taxes", State: doneState, Priority: PriorityLow, CompletedAt: date(2024, 1, 16)},
+			want: ImportRow{TaskText: "File taxes", Priority: "1", Completed: true, CompletedAt: "2024-01-16"},
+		},
+		{
+			name: "underscores and backslashes in names",
+			task: TaskResponse{
+				TaskText: "Clean up", State: openState,
+				Category: &CategoryInfo{Name: "snake_case work"}, Tags: tags(`C:\temp`, "a_b c"),
+			},
+			want: ImportRow{TaskText: "Clean up", Category: "snake_case work", Tags: []string{`C:\temp`, "a_b c"}},
+		},
+		{
+			name: "marker words in the text",
+			task: TaskResponse{TaskText: "Call +1 555 @ home about due:tomorrow pri:A and +project @office", State: openState},
+			want: ImportRow{TaskText: "Call +1 555 @ home about due:tomorrow pri:A and +project @office"},
+		},
+		{
+			name: "completed task without completion date and a date first in the text",
+			task: TaskResponse{TaskText: "2024-03-01 review", State: doneState},
+			want: ImportRow{TaskText: "2024-03-01 review", Completed: true},
+		},
+		{
+			name: "completed task without completion date and a priority first in the text",
+			task: TaskResponse{TaskText: "(A) grade", State: doneState},
+			want: ImportRow{TaskText: "(A) grade", Completed: true},
+		},
+		{
+			name: "open task starting with x",
+			task: TaskResponse{TaskText: "x marks the spot", State: openState},
+			want: ImportRow{TaskText: "x marks the spot"},
+		},
+		{
+			name: "words starting with a backslash",
+			task: TaskResponse{TaskText: `Replace \n with \\`, State: openState},
+			want: ImportRow{TaskText: `Replace \n with \\`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := tt.task
+			task.CreationDate = created
+
+			var buf bytes.Buffer
+			if err := writeTasksTodoTxt(&buf, []*TaskResponse{&task}); err != nil {
+				t.Fatalf("writeTasksTodoTxt returned %v", err)
+			}
+			rows, err := parseImportTodoTxt(&buf)
+			if err != nil {
+				t.Fatalf("parseImportTodoTxt returned %v", err)
+			}
+			if len(rows) != 1 {
+				t.Fatalf("parseImportTodoTxt returned %d rows, want 1", len(rows))
+			}
+
+			got := rows[0]
+			got.Line = 0
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("round trip of %q = %+v, want %+v", buf.String(), got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteTasksTodoTxtEscapes(t *testing.T) {
+	task := &TaskResponse{
+		TaskText: "2024-03-01 ask @team", State: doneState, CreationDate: *date(2024, 1, 10),
+		Category: &CategoryInfo{Name: "snake_case work"},
+	}
+
+	var buf bytes.Buffer
+	if err := writeTasksTodoTxt(&buf, []*TaskResponse{task}); err != nil {
+		t.Fatalf("writeTasksTodoTxt returned %v", err)
+	}
+
+	want := `x \2024-03-01 ask \@team +snake\_case_work` + "\n"
+	if buf.String() != want {
+		t.Errorf("writeTasksTodoTxt wrote %q, want %q", buf.String(), want)
+	}
+}
@@ -0,0 +1,304 @@
+package timetracking
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+
+	"backend/root/internal/i18n"
+	"backend/root/internal/tasks"
+)
+
+// defaultTotalsDays is the range covered by a totals request without from/to
+const defaultTotalsDays = 30
+
+// Handler handles HTTP requests for time tracking
+type Handler struct {
+	service Service
+}
+
+// NewHandler creates a new time tracking handler
+func NewHandler(service Service) *Handler {
+	return &Handler{
+		service: service,
+	}
+}
+
+// Start handles POST /tasks/:id/timer/start - starts a timer on a task
+func (h *Handler) Start(c *gin.Context) {
+	taskID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, i18n.NewError("task_id_invalid"))
+		return
+	}
+
+	// The body is optional, it only carries a note
+	var req StartTimerRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			i18n.RespondError(c, http.StatusBadRequest, i18n.NewError("request_invalid_detail", err))
+			return
+		}
+	}
+
+	userID, err := getUserIDFromClaims(c)
+	if err != nil {
+		i18n.RespondError(c, http.StatusUnauthorized, err)
+		return
+	}
+
+	entry, err := h.service.Start(c.Request.Context(), taskID, userID, req)
+	if err != nil {
+		i18n.RespondError(c, statusFor(err), err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, entry)
+}
+
+// Stop handles POST /timer/stop - stops the running timer
+func (h *Handler) Stop(c *gin.Context) {
+	userID, err := getUserIDFromClaims(c)
+	if err != nil {
+		i18n.RespondError(c, http.StatusUnauthorized, err)
+		return
+	}
+
+	entry, err := h.service.Stop(c.Request.Context(), userID)
+	if err != nil {
+		i18n.RespondError(c, statusFor(err), err)
+		return
+	}
+
+	c.JSON(http.StatusOK, entry)
+}
+
+// GetRunning handles GET /timer - returns the running timer, null when none is running
+func (h *Handler) GetRunning(c *gin.Context) {
+	userID, err := getUserIDFromClaims(c)
+	if err != nil {
+		i18n.RespondError(c, http.StatusUnauthorized, err)
+		return
+	}
+
+	entry, err := h.service.GetRunning(c.Request.Context(), userID)
+	if err != nil {
+		i18n.RespondError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"timer": entry})
+}
+
+// Create handles POST /tasks/:id/time-entries - logs time on a task manually
+func (h *Handler) Create(c *gin.Context) {
+	taskID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, i18n.NewError("task_id_invalid"))
+		return
+	}
+
+	var req CreateEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, i18n.NewError("request_invalid_detail", err))
+		return
+	}
+
+	userID, err := getUserIDFromClaims(c)
+	if err != nil {
+		i18n.RespondError(c, http.StatusUnauthorized, err)
+		return
+	}
+
+	entry, err := h.service.Create(c.Request.Context(), taskID, userID, req)
+	if err != nil {
+		i18n.RespondError(c, statusFor(err), err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, entry)
+}
+
+// GetByTask handles GET /tasks/:id/time-entries - lists a task's time entries
+func (h *Handler) GetByTask(c *gin.Context) {
+	taskID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, i18n.NewError("task_id_invalid"))
+		return
+	}
+
+	userID, err := getUserIDFromClaims(c)
+	if err != nil {
+		i18n.RespondError(c, http.StatusUnauthorized, err)
+		return
+	}
+
+	entries, err := h.service.GetByTask(c.Request.Context(), taskID, userID)
+	if err != nil {
+		i18n.RespondError(c, statusFor(err), err)
+		return
+	}
+
+	var totalSeconds int64
+	for _, entry := range entries {
+		totalSeconds += entry.DurationSeconds
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"time_entries":  entries,
+		"count":         len(entries),
+		"total_seconds": totalSeconds,
+	})
+}
+
+// Update handles PATCH /time-entries/:id - edits a time entry
+func (h *Handler) Update(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, i18n.NewError("time_entry_id_invalid"))
+		return
+	}
+
+	var req UpdateEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, i18n.NewError("request_invalid_detail", err))
+		return
+	}
+
+	userID, err := getUserIDFromClaims(c)
+	if err != nil {
+		i18n.RespondError(c, http.StatusUnauthorized, err)
+		return
+	}
+
+	entry, err := h.service.Update(c.Request.Context(), id, userID, req)
+	if err != nil {
+		i18n.RespondError(c, statusFor(err), err)
+		return
+	}
+
+	c.JSON(http.StatusOK, entry)
+}
+
+// Delete handles DELETE /time-entries/:id - removes a time entry
+func (h *Handler) Delete(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, i18n.NewError("time_entry_id_invalid"))
+		return
+	}
+
+	userID, err := getUserIDFromClaims(c)
+	if err != nil {
+		i18n.RespondError(c, http.StatusUnauthorized, err)
+		return
+	}
+
+	if err := h.service.Delete(c.Request.Context(), id, userID); err != nil {
+		i18n.RespondError(c, statusFor(err), err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "time entry deleted successfully"})
+}
+
+// Totals handles GET /time-entries/totals - sums tracked time
+// (?group_by=task|category|day&from=2024-01-01&to=2024-01-31&task_id=1&category_id=2).
+// from and to are inclusive UTC dates; the last 30 days are used when omitted.
+func (h *Handler) Totals(c *gin.Context) {
+	filter := TotalsFilter{GroupBy: c.Query("group_by")}
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	to := today
+	if value := c.Query("to"); value != "" {
+		parsed, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			i18n.RespondError(c, http.StatusBadRequest, i18n.NewError("time_to_invalid"))
+			return
+		}
+		to = parsed
+	}
+	filter.From = to.AddDate(0, 0, -(defaultTotalsDays - 1))
+	if value := c.Query("from"); value != "" {
+		parsed, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			i18n.RespondError(c, http.StatusBadRequest, i18n.NewError("time_from_invalid"))
+			return
+		}
+		filter.From = parsed
+	}
+	filter.To = to.AddDate(0, 0, 1)
+
+	if value := c.Query("task_id"); value != "" {
+		taskID, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			i18n.RespondError(c, http.StatusBadRequest, i18n.NewError("task_id_param_invalid"))
+			return
+		}
+		filter.TaskID = &taskID
+	}
+	if value := c.Query("category_id"); value != "" {
+		categoryID, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			i18n.RespondError(c, http.StatusBadRequest, i18n.NewError("task_category_param_invalid"))
+			return
+		}
+		filter.CategoryID = &categoryID
+	}
+
+	userID, err := getUserIDFromClaims(c)
+	if err != nil {
+		i18n.RespondError(c, http.StatusUnauthorized, err)
+		return
+	}
+
+	totals, err := h.service.Totals(c.Request.Context(), userID, filter)
+	if err != nil {
+		i18n.RespondError(c, statusFor(err), err)
+		return
+	}
+
+	c.JSON(http.StatusOK, totals)
+}
+
+// statusFor maps service errors to HTTP status codes
+func statusFor(err error) int {
+	if errors.Is(err, ErrTimerRunning) {
+		return http.StatusConflict
+	}
+
+	switch {
+	case errors.Is(err, tasks.ErrTaskNotFound), errors.Is(err, ErrEntryNotFound), errors.Is(err, ErrTimerNotRunning):
+		return http.StatusNotFound
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+// getUserIDFromClaims extracts the user ID from the JWT claims set by the auth middleware
+func getUserIDFromClaims(c *gin.Context) (int64, error) {
+	claims, exists := c.Get("claims")
+	if !exists {
+		return 0, i18n.NewError("user_not_authenticated")
+	}
+
+	claimsMap, ok := claims.(jwt.MapClaims)
+	if !ok {
+		return 0, errors.New("invalid claims")
+	}
+
+	switch v := claimsMap["uid"].(type) {
+	case float64:
+		return int64(v), nil
+	case int64:
+		return v, nil
+	case int:
+		return int64(v), nil
+	default:
+		return 0, i18n.NewError("user_id_missing")
+	}
+}
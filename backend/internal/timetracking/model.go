@@ -0,0 +1,79 @@
+package timetracking
+
+import (
+	"time"
+
+	"backend/root/internal/i18n"
+)
+
+// TimeEntry represents time spent on a task. A running timer has no StoppedAt.
+type TimeEntry struct {
+	ID              int64      `json:"id" db:"id"`
+	TaskID          int64      `json:"task_id" db:"task_id"`
+	UserID          int64      `json:"user_id" db:"user_id"`
+	StartedAt       time.Time  `json:"started_at" db:"started_at"`
+	StoppedAt       *time.Time `json:"stopped_at" db:"stopped_at"`
+	Note            string     `json:"note" db:"note"`
+	DurationSeconds int64      `json:"duration_seconds"` // Up to now for a running timer
+	Running         bool       `json:"running"`
+}
+
+// StartTimerRequest represents the request payload for starting a timer on a task
+type StartTimerRequest struct {
+	Note string `json:"note" binding:"max=1000"`
+}
+
+// CreateEntryRequest represents the request payload for logging time manually
+type CreateEntryRequest struct {
+	StartedAt string `json:"started_at" binding:"required"` // RFC 3339
+	StoppedAt string `json:"stopped_at" binding:"required"` // RFC 3339
+	Note      string `json:"note" binding:"max=1000"`
+}
+
+// UpdateEntryRequest represents the request payload for editing a time entry; omitted fields are kept
+type UpdateEntryRequest struct {
+	StartedAt *string `json:"started_at,omitempty"` // RFC 3339
+	StoppedAt *string `json:"stopped_at,omitempty"` // RFC 3339, stops a running timer
+	Note      *string `json:"note,omitempty" binding:"omitempty,max=1000"`
+}
+
+// TotalsFilter selects the entries aggregated by a totals request
+type TotalsFilter struct {
+	GroupBy    string    // GroupByTask, GroupByCategory or GroupByDay
+	From       time.Time // inclusive
+	To         time.Time // exclusive
+	TaskID     *int64
+	CategoryID *int64
+}
+
+// Totals groupings
+const (
+	GroupByTask     = "task"
+	GroupByCategory = "category"
+	GroupByDay      = "day"
+)
+
+// TotalGroup is the time spent within one group of a totals request
+type TotalGroup struct {
+	Key     string `json:"key"`            // Task or category ID, or the day as YYYY-MM-DD ("none" for uncategorized)
+	Name    string `json:"name,omitempty"` // Task text or category name
+	Seconds int64  `json:"seconds"`
+}
+
+// Totals is the time spent in a date range, overall and per group
+type Totals struct {
+	From         string       `json:"from"`
+	To           string       `json:"to"`
+	GroupBy      string       `json:"group_by"`
+	TotalSeconds int64        `json:"total_seconds"`
+	Groups       []TotalGroup `json:"groups"`
+}
+
+// ErrTimerRunning is returned when starting a timer while another one is running
+var ErrTimerRunning = i18n.NewError("timer_running")
+
+// ErrTimerNotRunning is returned when stopping the timer while none is running
+var ErrTimerNotRunning = i18n.NewError("timer_not_running")
+
+// ErrEntryNotFound is returned when a time entry does not exist or belongs to another user
+var ErrEntryNotFound = i18n.NewError("time_entry_not_found")
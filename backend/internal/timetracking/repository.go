@@ -0,0 +1,272 @@
+package timetracking
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"backend/root/internal/workspaces"
+)
+
+// Repository defines the interface for time entry persistence
+type Repository interface {
+	Start(ctx context.Context, taskID int64, userID int64, note string) (*TimeEntry, error)
+	Stop(ctx context.Context, userID int64) (*TimeEntry, error)
+	GetRunning(ctx context.Context, userID int64) (*TimeEntry, error)
+	Create(ctx context.Context, entry *TimeEntry) (*TimeEntry, error)
+	GetByID(ctx context.Context, id int64) (*TimeEntry, error)
+	GetByTask(ctx context.Context, taskID int64) ([]*TimeEntry, error)
+	Update(ctx context.Context, entry *TimeEntry) (*TimeEntry, error)
+	Delete(ctx context.Context, id int64) error
+	Totals(ctx context.Context, userID int64, filter TotalsFilter) ([]TotalGroup, error)
+}
+
+// entryColumns lists the time_entries columns in the order expected by scanEntry
+const entryColumns = `id, task_id, user_id, started_at, stopped_at, note,
+	EXTRACT(EPOCH FROM COALESCE(stopped_at, NOW()) - started_at)::BIGINT`
+
+// uniqueViolation is the PostgreSQL error code raised by a duplicate key
+const uniqueViolation = "23505"
+
+// PostgresRepository implements Repository using PostgreSQL
+type PostgresRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresRepository creates a new PostgreSQL time entries repository
+func NewPostgresRepository(db *sql.DB) *PostgresRepository {
+	return &PostgresRepository{db: db}
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanEntry(row rowScanner) (*TimeEntry, error) {
+	var entry TimeEntry
+	err := row.Scan(
+		&entry.ID, &entry.TaskID, &entry.UserID, &entry.StartedAt, &entry.StoppedAt, &entry.Note,
+		&entry.DurationSeconds,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	entry.Running = entry.StoppedAt == nil
+	return &entry, nil
+}
+
+// Start opens a running timer. The partial unique index on time_entries(user_id)
+// guarantees a user never has two running timers, even under concurrent requests.
+func (r *PostgresRepository) Start(ctx context.Context, taskID int64, userID int64, note string) (*TimeEntry, error) {
+	query := `
+		INSERT INTO time_entries (task_id, user_id, started_at, note)
+		VALUES ($1, $2, NOW(), $3)
+		RETURNING ` + entryColumns
+
+	entry, err := scanEntry(r.db.QueryRowContext(ctx, query, taskID, userID, note))
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+			return nil, ErrTimerRunning
+		}
+		return nil, fmt.Errorf("failed to start timer: %w", err)
+	}
+
+	return entry, nil
+}
+
+// Stop closes the user's running timer
+func (r *PostgresRepository) Stop(ctx context.Context, userID int64) (*TimeEntry, error) {
+	query := `
+		UPDATE time_entries SET stopped_at = GREATEST(NOW(), started_at)
+		WHERE user_id = $1 AND stopped_at IS NULL
+		RETURNING ` + entryColumns
+
+	entry, err := scanEntry(r.db.QueryRowContext(ctx, query, userID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrTimerNotRunning
+		}
+		return nil, fmt.Errorf("failed to stop timer: %w", err)
+	}
+
+	return entry, nil
+}
+
+// GetRunning retrieves the user's running timer, or nil if none is running
+func (r *PostgresRepository) GetRunning(ctx context.Context, userID int64) (*TimeEntry, error) {
+	query := `SELECT ` + entryColumns + ` FROM time_entries WHERE user_id = $1 AND stopped_at IS NULL`
+
+	entry, err := scanEntry(r.db.QueryRowContext(ctx, query, userID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get running timer: %w", err)
+	}
+
+	return entry, nil
+}
+
+// Create stores a manually logged, already finished time entry
+func (r *PostgresRepository) Create(ctx context.Context, entry *TimeEntry) (*TimeEntry, error) {
+	query := `
+		INSERT INTO time_entries (task_id, user_id, started_at, stopped_at, note)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING ` + entryColumns
+
+	created, err := scanEntry(r.db.QueryRowContext(ctx, query,
+		entry.TaskID, entry.UserID, entry.StartedAt, entry.StoppedAt, entry.Note,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create time entry: %w", err)
+	}
+
+	return created, nil
+}
+
+// GetByID retrieves a time entry by its ID
+func (r *PostgresRepository) GetByID(ctx context.Context, id int64) (*TimeEntry, error) {
+	query := `SELECT ` + entryColumns + ` FROM time_entries WHERE id = $1`
+
+	entry, err := scanEntry(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrEntryNotFound
+		}
+		return nil, fmt.Errorf("failed to get time entry: %w", err)
+	}
+
+	return entry, nil
+}
+
+// GetByTask retrieves every time entry of a task, most recent first
+func (r *PostgresRepository) GetByTask(ctx context.Context, taskID int64) ([]*TimeEntry, error) {
+	query := `SELECT ` + entryColumns + ` FROM time_entries WHERE task_id = $1 ORDER BY started_at DESC, id DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get time entries: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []*TimeEntry{}
+	for rows.Next() {
+		entry, err := scanEntry(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan time entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating time entries: %w", err)
+	}
+
+	return entries, nil
+}
+
+// Update replaces the times and note of a time entry
+func (r *PostgresRepository) Update(ctx context.Context, entry *TimeEntry) (*TimeEntry, error) {
+	query := `
+		UPDATE time_entries SET started_at = $2, stopped_at = $3, note = $4
+		WHERE id = $1
+		RETURNING ` + entryColumns
+
+	updated, err := scanEntry(r.db.QueryRowContext(ctx, query, entry.ID, entry.StartedAt, entry.StoppedAt, entry.Note))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrEntryNotFound
+		}
+		return nil, fmt.Errorf("failed to update time entry: %w", err)
+	}
+
+	return updated, nil
+}
+
+// Delete removes a time entry by ID
+func (r *PostgresRepository) Delete(ctx context.Context, id int64) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM time_entries WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete time entry: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrEntryNotFound
+	}
+
+	return nil
+}
+
+// Totals sums the user's tracked time on the tasks of the request scope within [filter.From, filter.To),
+// grouped by task, category or UTC day. Entries crossing the range boundaries (or midnight, when
+// grouping by day) only count the part inside; running timers count up to now.
+func (r *PostgresRepository) Totals(ctx context.Context, userID int64, filter TotalsFilter) ([]TotalGroup, error) {
+	clipped := `
+		WITH clipped AS (
+			SELECT e.task_id, t.task_text, t.id_category,
+				GREATEST(e.started_at, $2) AT TIME ZONE 'UTC' AS s,
+				LEAST(COALESCE(e.stopped_at, NOW()), $3) AT TIME ZONE 'UTC' AS f
+			FROM time_entries e
+			JOIN tasks t ON t.id = e.task_id AND t.deleted_at IS NULL
+			WHERE e.user_id = $1 AND e.started_at < $3 AND COALESCE(e.stopped_at, NOW()) > $2
+			  AND ($4::BIGINT IS NULL OR e.task_id = $4)
+			  AND ($5::BIGINT IS NULL OR t.id_category = $5)
+			  AND ` + workspaces.Condition(ctx, "t.workspace_id") + `
+		)`
+
+	var query string
+	switch filter.GroupBy {
+	case GroupByCategory:
+		query = clipped + `
+			SELECT COALESCE(c.id::TEXT, 'none'), COALESCE(c.name, ''), SUM(EXTRACT(EPOCH FROM f - s))::BIGINT
+			FROM clipped
+			LEFT JOIN categories c ON c.id = clipped.id_category AND c.deleted_at IS NULL
+			GROUP BY c.id, c.name
+			ORDER BY 3 DESC, 1`
+	case GroupByDay:
+		query = clipped + `
+			SELECT to_char(d, 'YYYY-MM-DD'), '', SUM(EXTRACT(EPOCH FROM LEAST(f, d + INTERVAL '1 day') - GREATEST(s, d)))::BIGINT
+			FROM clipped
+			CROSS JOIN LATERAL generate_series(date_trunc('day', s), f, INTERVAL '1 day') AS d
+			WHERE d < f
+			GROUP BY d
+			ORDER BY d`
+	default:
+		query = clipped + `
+			SELECT task_id::TEXT, task_text, SUM(EXTRACT(EPOCH FROM f - s))::BIGINT
+			FROM clipped
+			GROUP BY task_id, task_text
+			ORDER BY 3 DESC, task_id`
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, userID, filter.From, filter.To, filter.TaskID, filter.CategoryID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute time totals: %w", err)
+	}
+	defer rows.Close()
+
+	groups := []TotalGroup{}
+	for rows.Next() {
+		var group TotalGroup
+		if err := rows.Scan(&group.Key, &group.Name, &group.Seconds); err != nil {
+			return nil, fmt.Errorf("failed to scan time totals: %w", err)
+		}
+		groups = append(groups, group)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating time totals: %w", err)
+	}
+
+	return groups, nil
+}
@@ -0,0 +1,235 @@
+package timetracking
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"backend/root/internal/i18n"
+	"backend/root/internal/tasks"
+)
+
+// MaxTotalsRange is the longest date range a totals request may cover
+const MaxTotalsRange = 366 * 24 * time.Hour
+
+// Service defines the interface for time tracking business logic
+type Service interface {
+	Start(ctx context.Context, taskID int64, userID int64, req StartTimerRequest) (*TimeEntry, error)
+	Stop(ctx context.Context, userID int64) (*TimeEntry, error)
+	GetRunning(ctx context.Context, userID int64) (*TimeEntry, error)
+	Create(ctx context.Context, taskID int64, userID int64, req CreateEntryRequest) (*TimeEntry, error)
+	GetByTask(ctx context.Context, taskID int64, userID int64) ([]*TimeEntry, error)
+	Update(ctx context.Context, id int64, userID int64, req UpdateEntryRequest) (*TimeEntry, error)
+	Delete(ctx context.Context, id int64, userID int64) error
+	Totals(ctx context.Context, userID int64, filter TotalsFilter) (*Totals, error)
+}
+
+// TaskRepository defines the minimal interface needed to validate task ownership
+type TaskRepository interface {
+	GetByID(ctx context.Context, id int64) (*tasks.Task, error)
+}
+
+// service implements the Service interface
+type service struct {
+	repo     Repository
+	taskRepo TaskRepository
+}
+
+// NewService creates a new time tracking service
+func NewService(repo Repository, taskRepo TaskRepository) Service {
+	return &service{
+		repo:     repo,
+		taskRepo: taskRepo,
+	}
+}
+
+// Start starts a timer on one of the user's tasks; only one timer may run at a time
+func (s *service) Start(ctx context.Context, taskID int64, userID int64, req StartTimerRequest) (*TimeEntry, error) {
+	if err := s.checkTask(ctx, taskID, userID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.Start(ctx, taskID, userID, strings.TrimSpace(req.Note))
+}
+
+// Stop stops the user's running timer
+func (s *service) Stop(ctx context.Context, userID int64) (*TimeEntry, error) {
+	return s.repo.Stop(ctx, userID)
+}
+
+// GetRunning retrieves the user's running timer, or nil if none is running
+func (s *service) GetRunning(ctx context.Context, userID int64) (*TimeEntry, error) {
+	return s.repo.GetRunning(ctx, userID)
+}
+
+// Create logs a finished time entry on one of the user's tasks
+func (s *service) Create(ctx context.Context, taskID int64, userID int64, req CreateEntryRequest) (*TimeEntry, error) {
+	if err := s.checkTask(ctx, taskID, userID); err != nil {
+		return nil, err
+	}
+
+	startedAt, err := parseTime(req.StartedAt, "started_at")
+	if err != nil {
+		return nil, err
+	}
+	stoppedAt, err := parseTime(req.StoppedAt, "stopped_at")
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &TimeEntry{
+		TaskID:    taskID,
+		UserID:    userID,
+		StartedAt: startedAt,
+		StoppedAt: &stoppedAt,
+		Note:      strings.TrimSpace(req.Note),
+	}
+	if err := validateTimes(entry); err != nil {
+		return nil, err
+	}
+
+	return s.repo.Create(ctx, entry)
+}
+
+// GetByTask retrieves the time entries of one of the user's tasks
+func (s *service) GetByTask(ctx context.Context, taskID int64, userID int64) ([]*TimeEntry, error) {
+	if err := s.checkTask(ctx, taskID, userID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetByTask(ctx, taskID)
+}
+
+// Update edits the times or note of one of the user's time entries
+func (s *service) Update(ctx context.Context, id int64, userID int64, req UpdateEntryRequest) (*TimeEntry, error) {
+	entry, err := s.getOwnedEntry(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.StartedAt != nil {
+		if entry.StartedAt, err = parseTime(*req.StartedAt, "started_at"); err != nil {
+			return nil, err
+		}
+	}
+	if req.StoppedAt != nil {
+		stoppedAt, err := parseTime(*req.StoppedAt, "stopped_at")
+		if err != nil {
+			return nil, err
+		}
+		entry.StoppedAt = &stoppedAt
+	}
+	if req.Note != nil {
+		entry.Note = strings.TrimSpace(*req.Note)
+	}
+
+	if err := validateTimes(entry); err != nil {
+		return nil, err
+	}
+
+	return s.repo.Update(ctx, entry)
+}
+
+// Delete removes one of the user's time entries
+func (s *service) Delete(ctx context.Context, id int64, userID int64) error {
+	if _, err := s.getOwnedEntry(ctx, id, userID); err != nil {
+		return err
+	}
+
+	return s.repo.Delete(ctx, id)
+}
+
+// Totals sums the user's tracked time in a date range, overall and per group
+func (s *service) Totals(ctx context.Context, userID int64, filter TotalsFilter) (*Totals, error) {
+	if filter.GroupBy == "" {
+		filter.GroupBy = GroupByTask
+	}
+	if filter.GroupBy != GroupByTask && filter.GroupBy != GroupByCategory && filter.GroupBy != GroupByDay {
+		return nil, i18n.NewError("time_group_by_invalid")
+	}
+	if !filter.To.After(filter.From) {
+		return nil, i18n.NewError("time_range_order")
+	}
+	if filter.To.Sub(filter.From) > MaxTotalsRange {
+		return nil, i18n.NewError("time_range_too_long")
+	}
+
+	groups, err := s.repo.Totals(ctx, userID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	totals := &Totals{
+		From:    filter.From.Format("2006-01-02"),
+		To:      filter.To.AddDate(0, 0, -1).Format("2006-01-02"),
+		GroupBy: filter.GroupBy,
+		Groups:  groups,
+	}
+	for _, group := range groups {
+		totals.TotalSeconds += group.Seconds
+	}
+
+	return totals, nil
+}
+
+// getOwnedEntry loads a time entry and checks that it belongs to the user
+func (s *service) getOwnedEntry(ctx context.Context, id int64, userID int64) (*TimeEntry, error) {
+	if id <= 0 {
+		return nil, i18n.NewError("time_entry_id_invalid")
+	}
+
+	entry, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if entry.UserID != userID {
+		return nil, ErrEntryNotFound
+	}
+
+	return entry, nil
+}
+
+// checkTask checks that a task exists and belongs to the user
+func (s *service) checkTask(ctx context.Context, taskID int64, userID int64) error {
+	if taskID <= 0 {
+		return i18n.NewError("task_id_invalid")
+	}
+
+	task, err := s.taskRepo.GetByID(ctx, taskID)
+	if err != nil {
+		return err
+	}
+	if task == nil || task.UserID != userID {
+		return tasks.ErrTaskNotFound
+	}
+
+	return nil
+}
+
+// parseTime parses an RFC 3339 timestamp sent in the named field
+func parseTime(value string, field string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339, strings.TrimSpace(value))
+	if err != nil {
+		return time.Time{}, i18n.NewError("time_format_invalid", field)
+	}
+
+	return t, nil
+}
+
+// validateTimes checks that an entry does not start in the future or stop before it starts
+func validateTimes(entry *TimeEntry) error {
+	now := time.Now()
+	if entry.StartedAt.After(now) {
+		return i18n.NewError("time_started_future")
+	}
+	if entry.StoppedAt != nil {
+		if entry.StoppedAt.Before(entry.StartedAt) {
+			return i18n.NewError("time_stopped_before_start")
+		}
+		if entry.StoppedAt.After(now) {
+			return i18n.NewError("time_stopped_future")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,80 @@
+package trash
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+
+	"backend/root/internal/categories"
+	"backend/root/internal/i18n"
+	"backend/root/internal/tasks"
+)
+
+// Handler lists the deleted tasks and categories waiting to be purged
+type Handler struct {
+	tasks      tasks.Service
+	categories categories.Service
+}
+
+// NewHandler creates a new trash handler
+func NewHandler(taskService tasks.Service, categoryService categories.Service) *Handler {
+	return &Handler{
+		tasks:      taskService,
+		categories: categoryService,
+	}
+}
+
+// GetAll handles GET /trash - lists the user's deleted tasks and the deleted categories
+func (h *Handler) GetAll(c *gin.Context) {
+	userID, err := getUserIDFromClaims(c)
+	if err != nil {
+		i18n.RespondError(c, http.StatusUnauthorized, err)
+		return
+	}
+
+	deletedTasks, err := h.tasks.GetTrash(c.Request.Context(), userID)
+	if err != nil {
+		i18n.RespondError(c, http.StatusInternalServerError, i18n.NewError("tasks_trash_failed"))
+		return
+	}
+	if deletedTasks == nil {
+		deletedTasks = []*tasks.TaskResponse{}
+	}
+
+	deletedCategories, err := h.categories.GetTrash(c.Request.Context())
+	if err != nil {
+		i18n.RespondError(c, http.StatusInternalServerError, i18n.NewError("categories_trash_failed"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"tasks":      deletedTasks,
+		"categories": deletedCategories,
+	})
+}
+
+// getUserIDFromClaims extracts the user ID from the JWT claims set by the auth middleware
+func getUserIDFromClaims(c *gin.Context) (int64, error) {
+	claims, exists := c.Get("claims")
+	if !exists {
+		return 0, i18n.NewError("user_not_authenticated")
+	}
+
+	claimsMap, ok := claims.(jwt.MapClaims)
+	if !ok {
+		return 0, errors.New("invalid claims")
+	}
+
+	switch v := claimsMap["uid"].(type) {
+	case float64:
+		return int64(v), nil
+	case int64:
+		return v, nil
+	case int:
+		return int64(v), nil
+	default:
+		return 0, i18n.NewError("user_id_missing")
+	}
+}
@@ -0,0 +1,78 @@
+package trash
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Purgeable is implemented by repositories that keep soft-deleted rows
+type Purgeable interface {
+	Purge(ctx context.Context, before time.Time) (int64, error)
+}
+
+// Purger periodically and permanently removes rows that have been in the trash longer than the retention period
+type Purger struct {
+	tasks      Purgeable
+	categories Purgeable
+	retention  time.Duration
+	interval   time.Duration
+}
+
+// NewPurger creates a new trash purger
+func NewPurger(tasks Purgeable, categories Purgeable, retention time.Duration, interval time.Duration) *Purger {
+	if retention <= 0 {
+		retention = 30 * 24 * time.Hour
+	}
+	if interval <= 0 {
+		interval = time.Hour
+	}
+
+	return &Purger{
+		tasks:      tasks,
+		categories: categories,
+		retention:  retention,
+		interval:   interval,
+	}
+}
+
+// Run purges expired rows every interval until ctx is cancelled
+func (p *Purger) Run(ctx context.Context) {
+	log.Printf("Trash purger started (retention %s, every %s)", p.retention, p.interval)
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := p.Purge(ctx, time.Now()); err != nil {
+			log.Printf("Trash purger: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			log.Printf("Trash purger stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Purge removes every task and category deleted more than the retention period before now
+func (p *Purger) Purge(ctx context.Context, now time.Time) error {
+	before := now.Add(-p.retention)
+
+	purgedTasks, err := p.tasks.Purge(ctx, before)
+	if err != nil {
+		return err
+	}
+
+	purgedCategories, err := p.categories.Purge(ctx, before)
+	if err != nil {
+		return err
+	}
+
+	if purgedTasks > 0 || purgedCategories > 0 {
+		log.Printf("Trash purger: removed %d tasks and %d categories", purgedTasks, purgedCategories)
+	}
+
+	return nil
+}
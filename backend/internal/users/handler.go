@@ -1,12 +1,15 @@
 package users
 
 import (
+	"errors"
 	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
 
 	"backend/root/internal/auth"
+	"backend/root/internal/i18n"
 )
 
 // Handler wires HTTP with user and auth services.
@@ -48,6 +51,10 @@ type registerRequest struct {
     Password string `json:"password"`
 }
 
+type localeRequest struct {
+    Locale string `json:"locale"`
+}
+
 type loginRequest struct {
     Username string `json:"username"`
     Password string `json:"password"`
@@ -57,12 +64,12 @@ type loginRequest struct {
 func (h *Handler) Register(c *gin.Context) {
     var req registerRequest
     if err := c.ShouldBindJSON(&req); err != nil {
-        c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+        i18n.RespondError(c, http.StatusBadRequest, i18n.NewError("request_invalid"))
         return
     }
     user, err := h.users.Register(c.Request.Context(), req.Username, req.Password)
     if err != nil {
-        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        i18n.RespondError(c, http.StatusBadRequest, err)
         return
     }
     c.JSON(http.StatusCreated, gin.H{
@@ -76,12 +83,12 @@ func (h *Handler) Register(c *gin.Context) {
 func (h *Handler) Login(c *gin.Context) {
     var req loginRequest
     if err := c.ShouldBindJSON(&req); err != nil {
-        c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+        i18n.RespondError(c, http.StatusBadRequest, i18n.NewError("request_invalid"))
         return
     }
     user, err := h.users.Authenticate(c.Request.Context(), req.Username, req.Password)
     if err != nil {
-        c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid credentials"})
+        i18n.RespondError(c, http.StatusUnauthorized, i18n.NewError("credentials_invalid"))
         return
     }
     token, err := h.tokens.CreateToken(
@@ -90,7 +97,7 @@ func (h *Handler) Login(c *gin.Context) {
         map[string]any{"uid": user.ID, "username": user.Username},
     )
     if err != nil {
-        c.JSON(http.StatusInternalServerError, gin.H{"error": "could not create token"})
+        i18n.RespondError(c, http.StatusInternalServerError, i18n.NewError("token_create_failed"))
         return
     }
     c.JSON(http.StatusOK, gin.H{
@@ -108,13 +115,13 @@ func (h *Handler) Logout(c *gin.Context) {
     // Here we simulate that using an in-memory map.
     authHeader := c.GetHeader("Authorization")
     if authHeader == "" {
-        c.JSON(http.StatusBadRequest, gin.H{"error": "authorization header required"})
+        i18n.RespondError(c, http.StatusBadRequest, i18n.NewError("auth_header_required"))
         return
     }
     // Expecting format: "Bearer <token>"
     const prefix = "Bearer "
     if len(authHeader) <= len(prefix) || authHeader[:len(prefix)] != prefix {
-        c.JSON(http.StatusBadRequest, gin.H{"error": "invalid authorization header"})
+        i18n.RespondError(c, http.StatusBadRequest, i18n.NewError("auth_header_invalid"))
         return
     }
     token := authHeader[len(prefix):]
@@ -125,3 +132,59 @@ func (h *Handler) Logout(c *gin.Context) {
 
 
 
+
+
+// SetLocale stores the caller's preferred language for API messages. An empty locale
+// clears the preference so the Accept-Language header is used again.
+func (h *Handler) SetLocale(c *gin.Context) {
+    userID, err := getUserIDFromClaims(c)
+    if err != nil {
+        i18n.RespondError(c, http.StatusUnauthorized, err)
+        return
+    }
+
+    var req localeRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        i18n.RespondError(c, http.StatusBadRequest, i18n.NewError("request_invalid"))
+        return
+    }
+
+    locale, err := h.users.SetLocale(c.Request.Context(), userID, req.Locale)
+    if err != nil {
+        switch {
+        case errors.Is(err, ErrUnsupportedLocale):
+            i18n.RespondError(c, http.StatusBadRequest, err)
+        case errors.Is(err, ErrUserNotFound):
+            i18n.RespondError(c, http.StatusNotFound, err)
+        default:
+            i18n.RespondError(c, http.StatusInternalServerError, errors.New("failed to update locale"))
+        }
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"locale": locale})
+}
+
+// getUserIDFromClaims extracts the user ID from the JWT claims
+func getUserIDFromClaims(c *gin.Context) (int64, error) {
+    claims, exists := c.Get("claims")
+    if !exists {
+        return 0, i18n.NewError("user_not_authenticated")
+    }
+
+    claimsMap, ok := claims.(jwt.MapClaims)
+    if !ok {
+        return 0, errors.New("invalid claims")
+    }
+
+    switch v := claimsMap["uid"].(type) {
+    case float64:
+        return int64(v), nil
+    case int64:
+        return v, nil
+    case int:
+        return int64(v), nil
+    default:
+        return 0, i18n.NewError("user_id_missing")
+    }
+}
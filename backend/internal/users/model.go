@@ -1,5 +1,7 @@
 package users
 
+import "backend/root/internal/i18n"
+
 // User represents an application user. In a real implementation,
 // this would be persisted to a database. The Password field contains the
 // bcrypt hash of the user's password.
@@ -8,4 +10,11 @@ type User struct {
     Username     string `json:"username"`
     Password     string `json:"-"`
     ProfileImg   string `json:"profile_img"`
-}
\ No newline at end of file
+    Locale       string `json:"locale,omitempty"` // preferred language of API messages, "" to follow Accept-Language
+}
+
+// Errors returned when a user cannot be found or updated
+var (
+    ErrUserNotFound      = i18n.NewError("user_not_found")
+    ErrUnsupportedLocale = i18n.NewError("locale_unsupported")
+)
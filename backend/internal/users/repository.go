@@ -3,14 +3,17 @@ package users
 import (
 	"context"
 	"database/sql"
-	"errors"
 	"sync"
+
+	"backend/root/internal/i18n"
 )
 
 // Repository abstracts user persistence. Swap this in-memory implementation with DB-backed repo later.
 type Repository interface {
     Create(ctx context.Context, username, passwordHash, profileImg string) (*User, error)
     FindByUsername(ctx context.Context, username string) (*User, error)
+    GetLocale(ctx context.Context, userID int64) (string, error)
+    SetLocale(ctx context.Context, userID int64, locale string) error
 }
 
 // InMemoryRepository is a goroutine-safe in-memory user repository. Replace with Postgres implementation later.
@@ -32,7 +35,7 @@ func (r *InMemoryRepository) Create(ctx context.Context, username, passwordHash,
     defer r.mu.Unlock()
 
     if _, exists := r.usersByUsername[username]; exists {
-        return nil, errors.New("username already exists")
+        return nil, i18n.NewError("username_taken")
     }
 
     user := &User{
@@ -54,12 +57,39 @@ func (r *InMemoryRepository) FindByUsername(ctx context.Context, username string
 
     user, ok := r.usersByUsername[username]
     if !ok {
-        return nil, errors.New("user not found")
+        return nil, ErrUserNotFound
     }
     // DB NOTE: Here you would perform a SELECT query to retrieve the user by username.
     return user, nil
 }
 
+// GetLocale returns the preferred locale of a user, "" when none is set
+func (r *InMemoryRepository) GetLocale(ctx context.Context, userID int64) (string, error) {
+    r.mu.RLock()
+    defer r.mu.RUnlock()
+
+    for _, user := range r.usersByUsername {
+        if user.ID == userID {
+            return user.Locale, nil
+        }
+    }
+    return "", ErrUserNotFound
+}
+
+// SetLocale stores the preferred locale of a user, "" clears it
+func (r *InMemoryRepository) SetLocale(ctx context.Context, userID int64, locale string) error {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+
+    for _, user := range r.usersByUsername {
+        if user.ID == userID {
+            user.Locale = locale
+            return nil
+        }
+    }
+    return ErrUserNotFound
+}
+
 // PostgresRepository implements Repository using PostgreSQL
 type PostgresRepository struct {
     db *sql.DB
@@ -81,7 +111,7 @@ func (r *PostgresRepository) Create(ctx context.Context, username, passwordHash,
     )
     if err != nil {
         if err.Error() == `pq: duplicate key value violates unique constraint "users_username_key"` {
-            return nil, errors.New("username already exists")
+            return nil, i18n.NewError("username_taken")
         }
         return nil, err
     }
@@ -98,10 +128,42 @@ func (r *PostgresRepository) FindByUsername(ctx context.Context, username string
     )
     if err != nil {
         if err == sql.ErrNoRows {
-            return nil, errors.New("user not found")
+            return nil, ErrUserNotFound
         }
         return nil, err
     }
     
     return &user, nil
-}
\ No newline at end of file
+}
+
+// GetLocale returns the preferred locale of a user, "" when none is set
+func (r *PostgresRepository) GetLocale(ctx context.Context, userID int64) (string, error) {
+    var locale sql.NullString
+    err := r.db.QueryRowContext(ctx, `SELECT locale FROM users WHERE id = $1`, userID).Scan(&locale)
+    if err != nil {
+        if err == sql.ErrNoRows {
+            return "", ErrUserNotFound
+        }
+        return "", err
+    }
+
+    return locale.String, nil
+}
+
+// SetLocale stores the preferred locale of a user, "" clears it
+func (r *PostgresRepository) SetLocale(ctx context.Context, userID int64, locale string) error {
+    result, err := r.db.ExecContext(ctx, `UPDATE users SET locale = NULLIF($2, '') WHERE id = $1`, userID, locale)
+    if err != nil {
+        return err
+    }
+
+    rowsAffected, err := result.RowsAffected()
+    if err != nil {
+        return err
+    }
+    if rowsAffected == 0 {
+        return ErrUserNotFound
+    }
+
+    return nil
+}
@@ -2,9 +2,10 @@ package users
 
 import (
 	"context"
-	"errors"
+	"strings"
 
 	"backend/root/internal/auth"
+	"backend/root/internal/i18n"
 	"backend/root/internal/storage"
 )
 
@@ -12,6 +13,8 @@ import (
 type Service interface {
     Register(ctx context.Context, username, password string) (*User, error)
     Authenticate(ctx context.Context, username, password string) (*User, error)
+    GetLocale(ctx context.Context, userID int64) (string, error)
+    SetLocale(ctx context.Context, userID int64, locale string) (string, error)
 }
 
 type service struct {
@@ -28,7 +31,7 @@ func NewService(repo Repository, profilePicService *storage.ProfilePictureServic
 
 func (s *service) Register(ctx context.Context, username, password string) (*User, error) {
     if username == "" || password == "" {
-        return nil, errors.New("username and password are required")
+        return nil, i18n.NewError("credentials_required")
     }
     hash, err := auth.HashPassword(password)
     if err != nil {
@@ -41,14 +44,31 @@ func (s *service) Register(ctx context.Context, username, password string) (*Use
 
 func (s *service) Authenticate(ctx context.Context, username, password string) (*User, error) {
     if username == "" || password == "" {
-        return nil, errors.New("username and password are required")
+        return nil, i18n.NewError("credentials_required")
     }
     user, err := s.repo.FindByUsername(ctx, username)
     if err != nil {
-        return nil, errors.New("invalid credentials")
+        return nil, i18n.NewError("credentials_invalid")
     }
     if err := auth.CheckPassword(user.Password, password); err != nil {
-        return nil, errors.New("invalid credentials")
+        return nil, i18n.NewError("credentials_invalid")
     }
     return user, nil
-}
\ No newline at end of file
+}
+
+// GetLocale returns the preferred locale of a user, "" when the user follows Accept-Language
+func (s *service) GetLocale(ctx context.Context, userID int64) (string, error) {
+    return s.repo.GetLocale(ctx, userID)
+}
+
+// SetLocale validates and stores the preferred locale of a user; an empty locale clears the preference
+func (s *service) SetLocale(ctx context.Context, userID int64, locale string) (string, error) {
+    locale = strings.ToLower(strings.TrimSpace(locale))
+    if locale != "" && !i18n.IsSupported(locale) {
+        return "", ErrUnsupportedLocale
+    }
+    if err := s.repo.SetLocale(ctx, userID, locale); err != nil {
+        return "", err
+    }
+    return locale, nil
+}
@@ -0,0 +1,381 @@
+package workspaces
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+
+	"backend/root/internal/i18n"
+	"backend/root/internal/users"
+)
+
+// Handler handles HTTP requests for workspaces, their members and invitations
+type Handler struct {
+	service Service
+}
+
+// NewHandler creates a new workspaces handler
+func NewHandler(service Service) *Handler {
+	return &Handler{
+		service: service,
+	}
+}
+
+// Create handles POST /workspaces - creates a workspace owned by the caller
+func (h *Handler) Create(c *gin.Context) {
+	var req CreateWorkspaceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, i18n.NewError("request_invalid_detail", err))
+		return
+	}
+
+	userID, err := getUserIDFromClaims(c)
+	if err != nil {
+		i18n.RespondError(c, http.StatusUnauthorized, err)
+		return
+	}
+
+	workspace, err := h.service.Create(c.Request.Context(), userID, req)
+	if err != nil {
+		i18n.RespondError(c, statusFor(err), err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, workspace)
+}
+
+// GetAll handles GET /workspaces - lists the workspaces the caller belongs to
+func (h *Handler) GetAll(c *gin.Context) {
+	userID, err := getUserIDFromClaims(c)
+	if err != nil {
+		i18n.RespondError(c, http.StatusUnauthorized, err)
+		return
+	}
+
+	workspaces, err := h.service.GetAll(c.Request.Context(), userID)
+	if err != nil {
+		i18n.RespondError(c, http.StatusInternalServerError, i18n.NewError("workspaces_get_failed"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"workspaces": workspaces,
+		"count":      len(workspaces),
+	})
+}
+
+// GetByID handles GET /workspaces/:id - retrieves a workspace with its members
+func (h *Handler) GetByID(c *gin.Context) {
+	id, userID, ok := parseWorkspaceRequest(c)
+	if !ok {
+		return
+	}
+
+	workspace, err := h.service.GetByID(c.Request.Context(), id, userID)
+	if err != nil {
+		i18n.RespondError(c, statusFor(err), err)
+		return
+	}
+
+	members, err := h.service.GetMembers(c.Request.Context(), id, userID)
+	if err != nil {
+		i18n.RespondError(c, statusFor(err), err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"workspace": workspace,
+		"members":   members,
+	})
+}
+
+// Rename handles PATCH /workspaces/:id - renames a workspace
+func (h *Handler) Rename(c *gin.Context) {
+	id, userID, ok := parseWorkspaceRequest(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateWorkspaceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, i18n.NewError("request_invalid_detail", err))
+		return
+	}
+
+	workspace, err := h.service.Rename(c.Request.Context(), id, userID, req)
+	if err != nil {
+		i18n.RespondError(c, statusFor(err), err)
+		return
+	}
+
+	c.JSON(http.StatusOK, workspace)
+}
+
+// Delete handles DELETE /workspaces/:id - deletes a workspace with its tasks and categories
+func (h *Handler) Delete(c *gin.Context) {
+	id, userID, ok := parseWorkspaceRequest(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(c.Request.Context(), id, userID); err != nil {
+		i18n.RespondError(c, statusFor(err), err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetMembers handles GET /workspaces/:id/members - lists the members of a workspace
+func (h *Handler) GetMembers(c *gin.Context) {
+	id, userID, ok := parseWorkspaceRequest(c)
+	if !ok {
+		return
+	}
+
+	members, err := h.service.GetMembers(c.Request.Context(), id, userID)
+	if err != nil {
+		i18n.RespondError(c, statusFor(err), err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"members": members,
+		"count":   len(members),
+	})
+}
+
+// UpdateMember handles PATCH /workspaces/:id/members/:user_id - changes the role of a member
+func (h *Handler) UpdateMember(c *gin.Context) {
+	id, userID, ok := parseWorkspaceRequest(c)
+	if !ok {
+		return
+	}
+
+	memberID, err := strconv.ParseInt(c.Param("user_id"), 10, 64)
+	if err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, i18n.NewError("member_id_invalid"))
+		return
+	}
+
+	var req UpdateMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, i18n.NewError("request_invalid_detail", err))
+		return
+	}
+
+	members, err := h.service.UpdateMemberRole(c.Request.Context(), id, userID, memberID, req)
+	if err != nil {
+		i18n.RespondError(c, statusFor(err), err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"members": members,
+		"count":   len(members),
+	})
+}
+
+// RemoveMember handles DELETE /workspaces/:id/members/:user_id - removes a member, or leaves the workspace
+func (h *Handler) RemoveMember(c *gin.Context) {
+	id, userID, ok := parseWorkspaceRequest(c)
+	if !ok {
+		return
+	}
+
+	memberID, err := strconv.ParseInt(c.Param("user_id"), 10, 64)
+	if err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, i18n.NewError("member_id_invalid"))
+		return
+	}
+
+	if err := h.service.RemoveMember(c.Request.Context(), id, userID, memberID); err != nil {
+		i18n.RespondError(c, statusFor(err), err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// Invite handles POST /workspaces/:id/invitations - invites a user by username or creates an invitation link
+func (h *Handler) Invite(c *gin.Context) {
+	id, userID, ok := parseWorkspaceRequest(c)
+	if !ok {
+		return
+	}
+
+	var req CreateInvitationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, i18n.NewError("request_invalid_detail", err))
+		return
+	}
+
+	invitation, err := h.service.Invite(c.Request.Context(), id, userID, req)
+	if err != nil {
+		i18n.RespondError(c, statusFor(err), err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, invitation)
+}
+
+// GetInvitations handles GET /workspaces/:id/invitations - lists the pending invitations of a workspace
+func (h *Handler) GetInvitations(c *gin.Context) {
+	id, userID, ok := parseWorkspaceRequest(c)
+	if !ok {
+		return
+	}
+
+	invitations, err := h.service.GetInvitations(c.Request.Context(), id, userID)
+	if err != nil {
+		i18n.RespondError(c, statusFor(err), err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"invitations": invitations,
+		"count":       len(invitations),
+	})
+}
+
+// RevokeInvitation handles DELETE /workspaces/:id/invitations/:invitation_id - revokes a pending invitation
+func (h *Handler) RevokeInvitation(c *gin.Context) {
+	id, userID, ok := parseWorkspaceRequest(c)
+	if !ok {
+		return
+	}
+
+	invitationID, err := strconv.ParseInt(c.Param("invitation_id"), 10, 64)
+	if err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, ErrInvalidInvitationID)
+		return
+	}
+
+	if err := h.service.RevokeInvitation(c.Request.Context(), id, userID, invitationID); err != nil {
+		i18n.RespondError(c, statusFor(err), err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetMyInvitations handles GET /invitations - lists the invitations addressed to the caller
+func (h *Handler) GetMyInvitations(c *gin.Context) {
+	userID, err := getUserIDFromClaims(c)
+	if err != nil {
+		i18n.RespondError(c, http.StatusUnauthorized, err)
+		return
+	}
+
+	invitations, err := h.service.GetMyInvitations(c.Request.Context(), userID)
+	if err != nil {
+		i18n.RespondError(c, http.StatusInternalServerError, i18n.NewError("invitations_get_failed"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"invitations": invitations,
+		"count":       len(invitations),
+	})
+}
+
+// Accept handles POST /invitations/:token/accept - joins the workspace of an invitation
+func (h *Handler) Accept(c *gin.Context) {
+	userID, err := getUserIDFromClaims(c)
+	if err != nil {
+		i18n.RespondError(c, http.StatusUnauthorized, err)
+		return
+	}
+
+	workspace, err := h.service.Accept(c.Request.Context(), c.Param("token"), userID)
+	if err != nil {
+		i18n.RespondError(c, statusFor(err), err)
+		return
+	}
+
+	c.JSON(http.StatusOK, workspace)
+}
+
+// Decline handles POST /invitations/:token/decline - declines an invitation addressed to the caller
+func (h *Handler) Decline(c *gin.Context) {
+	userID, err := getUserIDFromClaims(c)
+	if err != nil {
+		i18n.RespondError(c, http.StatusUnauthorized, err)
+		return
+	}
+
+	if err := h.service.Decline(c.Request.Context(), c.Param("token"), userID); err != nil {
+		i18n.RespondError(c, statusFor(err), err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// parseWorkspaceRequest reads the workspace ID path parameter and the caller, writing the
+// error response itself when either is invalid
+func parseWorkspaceRequest(c *gin.Context) (int64, int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		i18n.RespondError(c, http.StatusBadRequest, ErrInvalidWorkspaceID)
+		return 0, 0, false
+	}
+
+	userID, err := getUserIDFromClaims(c)
+	if err != nil {
+		i18n.RespondError(c, http.StatusUnauthorized, err)
+		return 0, 0, false
+	}
+
+	return id, userID, true
+}
+
+// statusFor maps workspace errors to HTTP status codes
+func statusFor(err error) int {
+	if errors.Is(err, ErrPermissionDenied) {
+		return http.StatusForbidden
+	}
+
+	switch {
+	case errors.Is(err, ErrWorkspaceNotFound), errors.Is(err, ErrMemberNotFound), errors.Is(err, ErrInvitationNotFound),
+		errors.Is(err, users.ErrUserNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrAdminsOwnerOnly), errors.Is(err, ErrOwnerRole), errors.Is(err, ErrOwnerLeave),
+		errors.Is(err, ErrOwnerRemove):
+		return http.StatusForbidden
+	case errors.Is(err, ErrMemberExists), errors.Is(err, ErrInvitationPending):
+		return http.StatusConflict
+	case errors.Is(err, ErrInvalidWorkspaceID), errors.Is(err, ErrInvalidInvitationID), errors.Is(err, ErrInvalidRole),
+		errors.Is(err, ErrNameEmpty), errors.Is(err, ErrNameTooLong):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// getUserIDFromClaims extracts the user ID from the JWT claims set by the auth middleware
+func getUserIDFromClaims(c *gin.Context) (int64, error) {
+	claims, exists := c.Get("claims")
+	if !exists {
+		return 0, i18n.NewError("user_not_authenticated")
+	}
+
+	claimsMap, ok := claims.(jwt.MapClaims)
+	if !ok {
+		return 0, errors.New("invalid claims")
+	}
+
+	switch v := claimsMap["uid"].(type) {
+	case float64:
+		return int64(v), nil
+	case int64:
+		return v, nil
+	case int:
+		return int64(v), nil
+	default:
+		return 0, i18n.NewError("user_id_missing")
+	}
+}
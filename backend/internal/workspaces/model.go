@@ -0,0 +1,115 @@
+package workspaces
+
+import (
+	"time"
+
+	"backend/root/internal/i18n"
+)
+
+// Membership roles, from the strongest to the weakest
+const (
+	RoleOwner  = "owner"  // Created the workspace; also renames and deletes it and manages admins
+	RoleAdmin  = "admin"  // Also invites and removes members and guests, and owns every task
+	RoleMember = "member" // Creates tasks and categories and edits every task
+	RoleGuest  = "guest"  // Reads the tasks and categories of the workspace
+)
+
+// roleRank orders the roles so that a stronger role satisfies a weaker requirement
+var roleRank = map[string]int{
+	RoleGuest:  1,
+	RoleMember: 2,
+	RoleAdmin:  3,
+	RoleOwner:  4,
+}
+
+// HasRole reports whether granted satisfies the required role
+func HasRole(granted string, required string) bool {
+	return roleRank[granted] >= roleRank[required]
+}
+
+// IsValidInviteRole checks if a role can be granted by an invitation or a role change
+func IsValidInviteRole(role string) bool {
+	return role == RoleAdmin || role == RoleMember || role == RoleGuest
+}
+
+// InvitationTTL is how long an invitation can be accepted
+const InvitationTTL = 7 * 24 * time.Hour
+
+// Workspace is a shared space whose members work on the same tasks and categories
+type Workspace struct {
+	ID          int64     `json:"id" db:"id"`
+	Name        string    `json:"name" db:"name"`
+	OwnerID     int64     `json:"owner_id" db:"owner_id"`
+	Role        string    `json:"role"` // Role of the requesting user
+	MemberCount int       `json:"member_count"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
+// Member is a user belonging to a workspace
+type Member struct {
+	UserID   int64     `json:"user_id" db:"user_id"`
+	Username string    `json:"username"`
+	Role     string    `json:"role" db:"role"`
+	JoinedAt time.Time `json:"joined_at" db:"joined_at"`
+}
+
+// Invitation lets a user, or anyone holding the link, join a workspace
+type Invitation struct {
+	ID            int64     `json:"id" db:"id"`
+	WorkspaceID   int64     `json:"workspace_id" db:"workspace_id"`
+	WorkspaceName string    `json:"workspace_name"`
+	UserID        *int64    `json:"user_id" db:"user_id"` // nil for a link invitation
+	Username      *string   `json:"username"`
+	Role          string    `json:"role" db:"role"`
+	Token         string    `json:"token" db:"token"` // Secret used to accept the invitation
+	CreatedBy     *int64    `json:"created_by" db:"created_by"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+	ExpiresAt     time.Time `json:"expires_at" db:"expires_at"`
+}
+
+// CreateWorkspaceRequest represents the request payload for creating a workspace
+type CreateWorkspaceRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+// UpdateWorkspaceRequest represents the request payload for renaming a workspace
+type UpdateWorkspaceRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+// CreateInvitationRequest represents the request payload for inviting users to a workspace
+type CreateInvitationRequest struct {
+	Username string `json:"username,omitempty"`      // Omit to create a link anyone can use
+	Role     string `json:"role" binding:"required"` // admin, member or guest
+}
+
+// UpdateMemberRequest represents the request payload for changing the role of a member
+type UpdateMemberRequest struct {
+	Role string `json:"role" binding:"required"` // admin, member or guest
+}
+
+// Errors returned when a workspace, member or invitation does not exist
+var (
+	ErrWorkspaceNotFound  = i18n.NewError("workspace_not_found")
+	ErrMemberNotFound     = i18n.NewError("workspace_member_not_found")
+	ErrInvitationNotFound = i18n.NewError("invitation_not_found")
+)
+
+// Errors returned when a membership change is not allowed
+var (
+	ErrAdminsOwnerOnly   = i18n.NewError("workspace_admins_owner_only")
+	ErrOwnerRole         = i18n.NewError("workspace_owner_role")
+	ErrOwnerLeave        = i18n.NewError("workspace_owner_leave")
+	ErrOwnerRemove       = i18n.NewError("workspace_owner_remove")
+	ErrMemberExists      = i18n.NewError("workspace_member_exists")
+	ErrInvitationPending = i18n.NewError("invitation_pending")
+)
+
+// Errors returned for invalid workspace requests
+var (
+	ErrInvalidWorkspaceID  = i18n.NewError("workspace_id_invalid")
+	ErrInvalidInvitationID = i18n.NewError("invitation_id_invalid")
+	ErrInvalidRole         = i18n.NewError("workspace_role_invalid")
+	ErrNameEmpty           = i18n.NewError("workspace_name_empty")
+	ErrNameTooLong         = i18n.NewError("workspace_name_long")
+)
@@ -0,0 +1,356 @@
+package workspaces
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+// Repository defines the interface for workspace data operations
+type Repository interface {
+	Create(ctx context.Context, name string, ownerID int64) (*Workspace, error)
+	GetByID(ctx context.Context, id int64, userID int64) (*Workspace, error)
+	GetForUser(ctx context.Context, userID int64) ([]*Workspace, error)
+	Rename(ctx context.Context, id int64, name string) error
+	Delete(ctx context.Context, id int64) error
+	GetRole(ctx context.Context, id int64, userID int64) (string, error)
+	GetMembers(ctx context.Context, id int64) ([]*Member, error)
+	UpdateMemberRole(ctx context.Context, id int64, userID int64, role string) error
+	RemoveMember(ctx context.Context, id int64, userID int64) error
+	CreateInvitation(ctx context.Context, invitation *Invitation) (*Invitation, error)
+	GetInvitations(ctx context.Context, id int64) ([]*Invitation, error)
+	GetInvitationsForUser(ctx context.Context, userID int64) ([]*Invitation, error)
+	GetInvitationByToken(ctx context.Context, token string) (*Invitation, error)
+	DeleteInvitation(ctx context.Context, id int64, invitationID int64) error
+	Accept(ctx context.Context, invitation *Invitation, userID int64) error
+	Decline(ctx context.Context, token string, userID int64) error
+}
+
+// uniqueViolation is the PostgreSQL error code for a unique constraint violation
+const uniqueViolation = "23505"
+
+// workspaceSelect selects a workspace with the role of member m, in the order expected by scanWorkspace
+const workspaceSelect = `
+	SELECT w.id, w.name, w.owner_id, m.role, w.created_at,
+		(SELECT COUNT(*) FROM workspace_members wm WHERE wm.workspace_id = w.id) as member_count
+	FROM workspaces w
+	JOIN workspace_members m ON m.workspace_id = w.id`
+
+// invitationSelect selects an invitation with its workspace and invited user names, in the order expected by scanInvitation
+const invitationSelect = `
+	SELECT i.id, i.workspace_id, w.name, i.user_id, u.username, i.role, i.token, i.created_by, i.created_at, i.expires_at
+	FROM workspace_invitations i
+	JOIN workspaces w ON w.id = i.workspace_id
+	LEFT JOIN users u ON u.id = i.user_id`
+
+// PostgresRepository implements Repository using PostgreSQL
+type PostgresRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresRepository creates a new PostgreSQL workspaces repository
+func NewPostgresRepository(db *sql.DB) *PostgresRepository {
+	return &PostgresRepository{db: db}
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanWorkspace(row rowScanner) (*Workspace, error) {
+	var workspace Workspace
+	err := row.Scan(
+		&workspace.ID, &workspace.Name, &workspace.OwnerID, &workspace.Role, &workspace.CreatedAt, &workspace.MemberCount,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &workspace, nil
+}
+
+func scanInvitation(row rowScanner) (*Invitation, error) {
+	var invitation Invitation
+	err := row.Scan(
+		&invitation.ID, &invitation.WorkspaceID, &invitation.WorkspaceName, &invitation.UserID, &invitation.Username,
+		&invitation.Role, &invitation.Token, &invitation.CreatedBy, &invitation.CreatedAt, &invitation.ExpiresAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+// isUniqueViolation reports whether err was caused by a unique constraint
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == uniqueViolation
+}
+
+// withTx runs fn inside a transaction, committing it if fn returns nil
+func (r *PostgresRepository) withTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// Create stores a new workspace and makes its creator the owner
+func (r *PostgresRepository) Create(ctx context.Context, name string, ownerID int64) (*Workspace, error) {
+	var id int64
+	err := r.withTx(ctx, func(tx *sql.Tx) error {
+		err := tx.QueryRowContext(ctx, `INSERT INTO workspaces (name, owner_id) VALUES ($1, $2) RETURNING id`, name, ownerID).Scan(&id)
+		if err != nil {
+			return fmt.Errorf("failed to create workspace: %w", err)
+		}
+
+		_, err = tx.ExecContext(ctx, `INSERT INTO workspace_members (workspace_id, user_id, role) VALUES ($1, $2, $3)`, id, ownerID, RoleOwner)
+		if err != nil {
+			return fmt.Errorf("failed to add workspace owner: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetByID(ctx, id, ownerID)
+}
+
+// GetByID retrieves a workspace the user belongs to
+func (r *PostgresRepository) GetByID(ctx context.Context, id int64, userID int64) (*Workspace, error) {
+	workspace, err := scanWorkspace(r.db.QueryRowContext(ctx, workspaceSelect+` WHERE w.id = $1 AND m.user_id = $2`, id, userID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrWorkspaceNotFound
+		}
+		return nil, fmt.Errorf("failed to get workspace: %w", err)
+	}
+
+	return workspace, nil
+}
+
+// GetForUser retrieves the workspaces the user belongs to, by name
+func (r *PostgresRepository) GetForUser(ctx context.Context, userID int64) ([]*Workspace, error) {
+	query := workspaceSelect + ` WHERE m.user_id = $1 ORDER BY w.name, w.id`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workspaces: %w", err)
+	}
+	defer rows.Close()
+
+	workspaces := []*Workspace{}
+	for rows.Next() {
+		workspace, err := scanWorkspace(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan workspace: %w", err)
+		}
+		workspaces = append(workspaces, workspace)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating workspaces: %w", err)
+	}
+
+	return workspaces, nil
+}
+
+// Rename changes the name of a workspace
+func (r *PostgresRepository) Rename(ctx context.Context, id int64, name string) error {
+	return r.exec(ctx, ErrWorkspaceNotFound, `UPDATE workspaces SET name = $2 WHERE id = $1`, id, name)
+}
+
+// Delete removes a workspace together with its members, invitations, categories and tasks
+func (r *PostgresRepository) Delete(ctx context.Context, id int64) error {
+	return r.exec(ctx, ErrWorkspaceNotFound, `DELETE FROM workspaces WHERE id = $1`, id)
+}
+
+// GetRole returns the membership role of the user in a workspace, "" when they are not a member
+func (r *PostgresRepository) GetRole(ctx context.Context, id int64, userID int64) (string, error) {
+	var role string
+	err := r.db.QueryRowContext(ctx, `SELECT role FROM workspace_members WHERE workspace_id = $1 AND user_id = $2`, id, userID).Scan(&role)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to get workspace role: %w", err)
+	}
+
+	return role, nil
+}
+
+// GetMembers retrieves the members of a workspace, strongest role first
+func (r *PostgresRepository) GetMembers(ctx context.Context, id int64) ([]*Member, error) {
+	query := `
+		SELECT m.user_id, u.username, m.role, m.joined_at
+		FROM workspace_members m
+		JOIN users u ON u.id = m.user_id
+		WHERE m.workspace_id = $1
+		ORDER BY CASE m.role WHEN 'owner' THEN 1 WHEN 'admin' THEN 2 WHEN 'member' THEN 3 ELSE 4 END, u.username`
+
+	rows, err := r.db.QueryContext(ctx, query, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get members: %w", err)
+	}
+	defer rows.Close()
+
+	members := []*Member{}
+	for rows.Next() {
+		var member Member
+		if err := rows.Scan(&member.UserID, &member.Username, &member.Role, &member.JoinedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan member: %w", err)
+		}
+		members = append(members, &member)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating members: %w", err)
+	}
+
+	return members, nil
+}
+
+// UpdateMemberRole changes the role of a member
+func (r *PostgresRepository) UpdateMemberRole(ctx context.Context, id int64, userID int64, role string) error {
+	return r.exec(ctx, ErrMemberNotFound, `UPDATE workspace_members SET role = $3 WHERE workspace_id = $1 AND user_id = $2`, id, userID, role)
+}
+
+// RemoveMember removes a user from a workspace. Their tasks stay in the workspace.
+func (r *PostgresRepository) RemoveMember(ctx context.Context, id int64, userID int64) error {
+	return r.exec(ctx, ErrMemberNotFound, `DELETE FROM workspace_members WHERE workspace_id = $1 AND user_id = $2`, id, userID)
+}
+
+// CreateInvitation stores a new invitation. A user has at most one pending invitation per workspace.
+func (r *PostgresRepository) CreateInvitation(ctx context.Context, invitation *Invitation) (*Invitation, error) {
+	query := `
+		INSERT INTO workspace_invitations (workspace_id, user_id, role, token, created_by, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id`
+
+	var id int64
+	err := r.db.QueryRowContext(ctx, query,
+		invitation.WorkspaceID, invitation.UserID, invitation.Role, invitation.Token, invitation.CreatedBy, invitation.ExpiresAt,
+	).Scan(&id)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrInvitationPending
+		}
+		return nil, fmt.Errorf("failed to create invitation: %w", err)
+	}
+
+	invitations, err := r.queryInvitations(ctx, invitationSelect+` WHERE i.id = $1`, id)
+	if err != nil {
+		return nil, err
+	}
+	return invitations[0], nil
+}
+
+// GetInvitations retrieves the pending invitations of a workspace, newest first
+func (r *PostgresRepository) GetInvitations(ctx context.Context, id int64) ([]*Invitation, error) {
+	query := invitationSelect + ` WHERE i.workspace_id = $1 AND i.expires_at > NOW() ORDER BY i.created_at DESC, i.id DESC`
+
+	return r.queryInvitations(ctx, query, id)
+}
+
+// GetInvitationsForUser retrieves the pending invitations addressed to a user, newest first
+func (r *PostgresRepository) GetInvitationsForUser(ctx context.Context, userID int64) ([]*Invitation, error) {
+	query := invitationSelect + ` WHERE i.user_id = $1 AND i.expires_at > NOW() ORDER BY i.created_at DESC, i.id DESC`
+
+	return r.queryInvitations(ctx, query, userID)
+}
+
+// GetInvitationByToken retrieves a pending invitation by its secret token
+func (r *PostgresRepository) GetInvitationByToken(ctx context.Context, token string) (*Invitation, error) {
+	invitations, err := r.queryInvitations(ctx, invitationSelect+` WHERE i.token = $1 AND i.expires_at > NOW()`, token)
+	if err != nil {
+		return nil, err
+	}
+	if len(invitations) == 0 {
+		return nil, ErrInvitationNotFound
+	}
+
+	return invitations[0], nil
+}
+
+// DeleteInvitation revokes an invitation of a workspace
+func (r *PostgresRepository) DeleteInvitation(ctx context.Context, id int64, invitationID int64) error {
+	return r.exec(ctx, ErrInvitationNotFound, `DELETE FROM workspace_invitations WHERE workspace_id = $1 AND id = $2`, id, invitationID)
+}
+
+// Accept adds the user to the workspace of an invitation with its role. An invitation
+// addressed to the user is used up; a link invitation stays valid until it expires.
+func (r *PostgresRepository) Accept(ctx context.Context, invitation *Invitation, userID int64) error {
+	return r.withTx(ctx, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO workspace_members (workspace_id, user_id, role) VALUES ($1, $2, $3)`,
+			invitation.WorkspaceID, userID, invitation.Role)
+		if err != nil {
+			if isUniqueViolation(err) {
+				return ErrMemberExists
+			}
+			return fmt.Errorf("failed to add member: %w", err)
+		}
+
+		if invitation.UserID != nil {
+			if _, err := tx.ExecContext(ctx, `DELETE FROM workspace_invitations WHERE id = $1`, invitation.ID); err != nil {
+				return fmt.Errorf("failed to delete invitation: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// Decline deletes a pending invitation addressed to the user
+func (r *PostgresRepository) Decline(ctx context.Context, token string, userID int64) error {
+	return r.exec(ctx, ErrInvitationNotFound, `DELETE FROM workspace_invitations WHERE token = $1 AND user_id = $2`, token, userID)
+}
+
+// exec runs a statement that must affect at least one row, returning notFound otherwise
+func (r *PostgresRepository) exec(ctx context.Context, notFound error, query string, args ...interface{}) error {
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to update workspace: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return notFound
+	}
+
+	return nil
+}
+
+// queryInvitations runs an invitationSelect based query and scans every invitation it returns
+func (r *PostgresRepository) queryInvitations(ctx context.Context, query string, args ...interface{}) ([]*Invitation, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get invitations: %w", err)
+	}
+	defer rows.Close()
+
+	invitations := []*Invitation{}
+	for rows.Next() {
+		invitation, err := scanInvitation(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan invitation: %w", err)
+		}
+		invitations = append(invitations, invitation)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating invitations: %w", err)
+	}
+
+	return invitations, nil
+}
@@ -0,0 +1,70 @@
+package workspaces
+
+import (
+	"context"
+	"strconv"
+
+	"backend/root/internal/i18n"
+)
+
+// Scope is the tenant a request works in: the user's personal space, or a workspace they belong to
+type Scope struct {
+	WorkspaceID int64  // 0 for the personal space
+	Role        string // Membership role in the workspace, "" for the personal space
+}
+
+// InWorkspace reports whether the scope is a workspace rather than the personal space
+func (s Scope) InWorkspace() bool {
+	return s.WorkspaceID != 0
+}
+
+// ErrReadOnly is returned when a guest tries to change the content of a workspace
+var ErrReadOnly = i18n.NewError("workspace_read_only")
+
+type scopeKey struct{}
+
+// WithScope returns a copy of ctx carrying the tenant scope of a request
+func WithScope(ctx context.Context, scope Scope) context.Context {
+	return context.WithValue(ctx, scopeKey{}, scope)
+}
+
+// FromContext returns the tenant scope carried by ctx. ok is false outside of
+// authenticated requests, e.g. in background jobs, which are not tenant bound.
+func FromContext(ctx context.Context) (scope Scope, ok bool) {
+	scope, ok = ctx.Value(scopeKey{}).(Scope)
+	return scope, ok
+}
+
+// IDFromContext returns the workspace of the request scope, nil in the personal space
+// and outside of requests
+func IDFromContext(ctx context.Context) *int64 {
+	scope, ok := FromContext(ctx)
+	if !ok || !scope.InWorkspace() {
+		return nil
+	}
+	return &scope.WorkspaceID
+}
+
+// CheckWritable returns ErrReadOnly when the request scope is a workspace the user is only a guest of
+func CheckWritable(ctx context.Context) error {
+	if scope, ok := FromContext(ctx); ok && scope.Role == RoleGuest {
+		return ErrReadOnly
+	}
+	return nil
+}
+
+// Condition returns the SQL predicate restricting the tenant-scoped rows of a query to the
+// request scope, given the qualified workspace_id column of the table. Every query on tasks
+// and categories includes it, so rows of other tenants are never read or written. The
+// workspace ID is inlined as an integer, which keeps the predicate free of placeholders.
+func Condition(ctx context.Context, column string) string {
+	scope, ok := FromContext(ctx)
+	switch {
+	case !ok:
+		return "TRUE"
+	case scope.InWorkspace():
+		return column + " = " + strconv.FormatInt(scope.WorkspaceID, 10)
+	default:
+		return column + " IS NULL"
+	}
+}
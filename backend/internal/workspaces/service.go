@@ -0,0 +1,334 @@
+package workspaces
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"backend/root/internal/i18n"
+	"backend/root/internal/users"
+)
+
+// Service defines the interface for workspace business logic
+type Service interface {
+	Create(ctx context.Context, userID int64, req CreateWorkspaceRequest) (*Workspace, error)
+	GetAll(ctx context.Context, userID int64) ([]*Workspace, error)
+	GetByID(ctx context.Context, id int64, userID int64) (*Workspace, error)
+	Rename(ctx context.Context, id int64, userID int64, req UpdateWorkspaceRequest) (*Workspace, error)
+	Delete(ctx context.Context, id int64, userID int64) error
+	GetRole(ctx context.Context, id int64, userID int64) (string, error)
+	GetMembers(ctx context.Context, id int64, userID int64) ([]*Member, error)
+	UpdateMemberRole(ctx context.Context, id int64, userID int64, memberID int64, req UpdateMemberRequest) ([]*Member, error)
+	RemoveMember(ctx context.Context, id int64, userID int64, memberID int64) error
+	Invite(ctx context.Context, id int64, userID int64, req CreateInvitationRequest) (*Invitation, error)
+	GetInvitations(ctx context.Context, id int64, userID int64) ([]*Invitation, error)
+	RevokeInvitation(ctx context.Context, id int64, userID int64, invitationID int64) error
+	GetMyInvitations(ctx context.Context, userID int64) ([]*Invitation, error)
+	Accept(ctx context.Context, token string, userID int64) (*Workspace, error)
+	Decline(ctx context.Context, token string, userID int64) error
+}
+
+// UserRepository defines the minimal interface needed to find the users to invite
+type UserRepository interface {
+	FindByUsername(ctx context.Context, username string) (*users.User, error)
+}
+
+// ErrPermissionDenied is returned when a member's role does not allow managing the workspace
+var ErrPermissionDenied = i18n.NewError("workspace_permission_denied")
+
+// service implements the Service interface
+type service struct {
+	repo     Repository
+	userRepo UserRepository
+}
+
+// NewService creates a new workspace service
+func NewService(repo Repository, userRepo UserRepository) Service {
+	return &service{
+		repo:     repo,
+		userRepo: userRepo,
+	}
+}
+
+// Create creates a workspace owned by the user
+func (s *service) Create(ctx context.Context, userID int64, req CreateWorkspaceRequest) (*Workspace, error) {
+	name, err := normalizeName(req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.Create(ctx, name, userID)
+}
+
+// GetAll lists the workspaces the user belongs to, each with the user's role
+func (s *service) GetAll(ctx context.Context, userID int64) ([]*Workspace, error) {
+	return s.repo.GetForUser(ctx, userID)
+}
+
+// GetByID retrieves a workspace the user belongs to
+func (s *service) GetByID(ctx context.Context, id int64, userID int64) (*Workspace, error) {
+	if id <= 0 {
+		return nil, ErrInvalidWorkspaceID
+	}
+
+	return s.repo.GetByID(ctx, id, userID)
+}
+
+// Rename changes the name of a workspace; admins and the owner may do so
+func (s *service) Rename(ctx context.Context, id int64, userID int64, req UpdateWorkspaceRequest) (*Workspace, error) {
+	if _, err := s.authorize(ctx, id, userID, RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	name, err := normalizeName(req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Rename(ctx, id, name); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(ctx, id, userID)
+}
+
+// Delete removes a workspace with all of its tasks and categories; only the owner may do so
+func (s *service) Delete(ctx context.Context, id int64, userID int64) error {
+	if _, err := s.authorize(ctx, id, userID, RoleOwner); err != nil {
+		return err
+	}
+
+	return s.repo.Delete(ctx, id)
+}
+
+// GetRole returns the membership role of the user in a workspace, "" when they are not a member
+func (s *service) GetRole(ctx context.Context, id int64, userID int64) (string, error) {
+	if id <= 0 {
+		return "", nil
+	}
+
+	return s.repo.GetRole(ctx, id, userID)
+}
+
+// GetMembers lists the members of a workspace the user belongs to
+func (s *service) GetMembers(ctx context.Context, id int64, userID int64) ([]*Member, error) {
+	if _, err := s.authorize(ctx, id, userID, RoleGuest); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetMembers(ctx, id)
+}
+
+// UpdateMemberRole changes the role of a member. Admins manage members and guests;
+// only the owner promotes or demotes admins, and the owner's role never changes.
+func (s *service) UpdateMemberRole(ctx context.Context, id int64, userID int64, memberID int64, req UpdateMemberRequest) ([]*Member, error) {
+	if !IsValidInviteRole(req.Role) {
+		return nil, ErrInvalidRole
+	}
+
+	actorRole, err := s.authorize(ctx, id, userID, RoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+
+	memberRole, err := s.memberRole(ctx, id, memberID)
+	if err != nil {
+		return nil, err
+	}
+	if memberRole == RoleOwner {
+		return nil, ErrOwnerRole
+	}
+	if (memberRole == RoleAdmin || req.Role == RoleAdmin) && actorRole != RoleOwner {
+		return nil, ErrAdminsOwnerOnly
+	}
+
+	if err := s.repo.UpdateMemberRole(ctx, id, memberID, req.Role); err != nil {
+		return nil, err
+	}
+	return s.repo.GetMembers(ctx, id)
+}
+
+// RemoveMember removes a user from a workspace. Members can leave on their own; admins
+// remove members and guests, and only the owner removes admins. The owner cannot leave.
+func (s *service) RemoveMember(ctx context.Context, id int64, userID int64, memberID int64) error {
+	if memberID == userID {
+		role, err := s.authorize(ctx, id, userID, RoleGuest)
+		if err != nil {
+			return err
+		}
+		if role == RoleOwner {
+			return ErrOwnerLeave
+		}
+		return s.repo.RemoveMember(ctx, id, userID)
+	}
+
+	actorRole, err := s.authorize(ctx, id, userID, RoleAdmin)
+	if err != nil {
+		return err
+	}
+
+	memberRole, err := s.memberRole(ctx, id, memberID)
+	if err != nil {
+		return err
+	}
+	if memberRole == RoleOwner {
+		return ErrOwnerRemove
+	}
+	if memberRole == RoleAdmin && actorRole != RoleOwner {
+		return ErrAdminsOwnerOnly
+	}
+
+	return s.repo.RemoveMember(ctx, id, memberID)
+}
+
+// Invite invites a user by username, or creates a link anyone can use until it expires when
+// no username is given. Admins and the owner invite; only the owner invites admins.
+func (s *service) Invite(ctx context.Context, id int64, userID int64, req CreateInvitationRequest) (*Invitation, error) {
+	if !IsValidInviteRole(req.Role) {
+		return nil, ErrInvalidRole
+	}
+
+	actorRole, err := s.authorize(ctx, id, userID, RoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+	if req.Role == RoleAdmin && actorRole != RoleOwner {
+		return nil, ErrAdminsOwnerOnly
+	}
+
+	token, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+	invitation := &Invitation{
+		WorkspaceID: id,
+		Role:        req.Role,
+		Token:       token,
+		CreatedBy:   &userID,
+		ExpiresAt:   time.Now().Add(InvitationTTL),
+	}
+
+	if username := strings.TrimSpace(req.Username); username != "" {
+		user, err := s.userRepo.FindByUsername(ctx, username)
+		if err != nil {
+			return nil, users.ErrUserNotFound
+		}
+		role, err := s.repo.GetRole(ctx, id, user.ID)
+		if err != nil {
+			return nil, err
+		}
+		if role != "" {
+			return nil, ErrMemberExists
+		}
+		invitation.UserID = &user.ID
+	}
+
+	return s.repo.CreateInvitation(ctx, invitation)
+}
+
+// GetInvitations lists the pending invitations of a workspace for its admins
+func (s *service) GetInvitations(ctx context.Context, id int64, userID int64) ([]*Invitation, error) {
+	if _, err := s.authorize(ctx, id, userID, RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetInvitations(ctx, id)
+}
+
+// RevokeInvitation deletes a pending invitation of a workspace
+func (s *service) RevokeInvitation(ctx context.Context, id int64, userID int64, invitationID int64) error {
+	if invitationID <= 0 {
+		return ErrInvalidInvitationID
+	}
+	if _, err := s.authorize(ctx, id, userID, RoleAdmin); err != nil {
+		return err
+	}
+
+	return s.repo.DeleteInvitation(ctx, id, invitationID)
+}
+
+// GetMyInvitations lists the pending invitations addressed to the user
+func (s *service) GetMyInvitations(ctx context.Context, userID int64) ([]*Invitation, error) {
+	return s.repo.GetInvitationsForUser(ctx, userID)
+}
+
+// Accept joins the workspace of an invitation. An invitation addressed to another user
+// is reported as not found.
+func (s *service) Accept(ctx context.Context, token string, userID int64) (*Workspace, error) {
+	invitation, err := s.repo.GetInvitationByToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if invitation.UserID != nil && *invitation.UserID != userID {
+		return nil, ErrInvitationNotFound
+	}
+
+	if err := s.repo.Accept(ctx, invitation, userID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(ctx, invitation.WorkspaceID, userID)
+}
+
+// Decline deletes an invitation addressed to the user
+func (s *service) Decline(ctx context.Context, token string, userID int64) error {
+	return s.repo.Decline(ctx, token, userID)
+}
+
+// authorize checks that the user belongs to a workspace with at least the required role,
+// returning the role actually held. Workspaces the user does not belong to are reported as not found.
+func (s *service) authorize(ctx context.Context, id int64, userID int64, required string) (string, error) {
+	if id <= 0 {
+		return "", ErrInvalidWorkspaceID
+	}
+
+	role, err := s.repo.GetRole(ctx, id, userID)
+	if err != nil {
+		return "", err
+	}
+	if role == "" {
+		return "", ErrWorkspaceNotFound
+	}
+	if !HasRole(role, required) {
+		return "", ErrPermissionDenied
+	}
+
+	return role, nil
+}
+
+// memberRole returns the role of a member of a workspace
+func (s *service) memberRole(ctx context.Context, id int64, memberID int64) (string, error) {
+	role, err := s.repo.GetRole(ctx, id, memberID)
+	if err != nil {
+		return "", err
+	}
+	if role == "" {
+		return "", ErrMemberNotFound
+	}
+
+	return role, nil
+}
+
+// normalizeName trims a workspace name and validates its length
+func normalizeName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", ErrNameEmpty
+	}
+	if utf8.RuneCountInString(name) > 100 {
+		return "", ErrNameTooLong
+	}
+
+	return name, nil
+}
+
+// newToken returns a random secret for an invitation
+func newToken() (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", i18n.NewError("invitation_create_failed")
+	}
+
+	return hex.EncodeToString(buf), nil
+}
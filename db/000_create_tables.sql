@@ -2,13 +2,27 @@
 -- * DROP EXISTING OBJECTS     *
 -- *******************************
 
--- NOTE: Enable the following 4 lines if you need to completely drop tables 
+-- NOTE: Enable the following lines if you need to completely drop tables 
 --       and recreate them from scratch.
 
+-- DROP TABLE IF EXISTS calendar_objects;
+-- DROP TABLE IF EXISTS calendar_feeds;
+-- DROP TABLE IF EXISTS task_shares;
+-- DROP TABLE IF EXISTS time_entries;
+-- DROP TABLE IF EXISTS task_events;
+-- DROP TABLE IF EXISTS task_comments;
+-- DROP TABLE IF EXISTS task_tags;
+-- DROP TABLE IF EXISTS tags;
+-- DROP TABLE IF EXISTS notifications;
+-- DROP TABLE IF EXISTS reminders;
+-- DROP TABLE IF EXISTS task_dependencies;
 -- DROP TABLE IF EXISTS tasks;
--- DROP TABLE IF EXISTS users;
--- DROP TABLE IF EXISTS categories;
 -- DROP TABLE IF EXISTS states;
+-- DROP TABLE IF EXISTS categories;
+-- DROP TABLE IF EXISTS workspace_invitations;
+-- DROP TABLE IF EXISTS workspace_members;
+-- DROP TABLE IF EXISTS workspaces;
+-- DROP TABLE IF EXISTS users;
 
 
 -- -----------------------------------------------------------------------------
@@ -20,15 +34,83 @@ CREATE TABLE IF NOT EXISTS users (
     id             SERIAL       PRIMARY KEY, 
     username       VARCHAR(50)  NOT NULL UNIQUE,
     password       VARCHAR(255) NOT NULL, 
-    profile_img    TEXT NULL
+    profile_img    TEXT NULL,
+    locale         VARCHAR(10)  NULL
 );
 
+-- Columns added after the table was first released; CREATE TABLE IF NOT EXISTS skips existing databases
+ALTER TABLE users ADD COLUMN IF NOT EXISTS locale VARCHAR(10) NULL;
+
 COMMENT ON TABLE users                 IS 'Contains user information';
 -- COLUMN COMMENTS
 COMMENT ON COLUMN users.id             IS 'Unique user identifier';
 COMMENT ON COLUMN users.username       IS 'Unique name identifying the user';
 COMMENT ON COLUMN users.password       IS 'User password hash';
 COMMENT ON COLUMN users.profile_img    IS 'User profile picture URL';
+COMMENT ON COLUMN users.locale         IS 'Preferred language of API messages (en, es), NULL to follow Accept-Language';
+
+-- -----------------------------------------------------------------------------
+
+-- ****************************
+-- * CREATE WORKSPACES TABLES *
+-- ****************************
+CREATE TABLE IF NOT EXISTS workspaces (
+    id          SERIAL PRIMARY KEY,
+    name        VARCHAR(100) NOT NULL,
+    owner_id    INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE  workspaces            IS 'Shared spaces whose members work on the same tasks and categories';
+-- COLUMN COMMENTS
+COMMENT ON COLUMN workspaces.id         IS 'Unique workspace identifier, sent by clients in the X-Workspace-ID header';
+COMMENT ON COLUMN workspaces.name       IS 'Workspace name';
+COMMENT ON COLUMN workspaces.owner_id   IS 'Foreign key referencing the user who created and owns the workspace';
+COMMENT ON COLUMN workspaces.created_at IS 'Workspace creation time';
+
+CREATE TABLE IF NOT EXISTS workspace_members (
+    workspace_id  INT NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    user_id       INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role          VARCHAR(10) NOT NULL,
+    joined_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (workspace_id, user_id),
+    CONSTRAINT workspace_members_role CHECK (role IN ('owner', 'admin', 'member', 'guest'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_workspace_members_user_id ON workspace_members(user_id);
+
+COMMENT ON TABLE  workspace_members              IS 'Users belonging to a workspace and their membership role';
+-- COLUMN COMMENTS
+COMMENT ON COLUMN workspace_members.workspace_id IS 'Foreign key referencing the workspace';
+COMMENT ON COLUMN workspace_members.user_id      IS 'Foreign key referencing the member';
+COMMENT ON COLUMN workspace_members.role         IS 'owner, admin (manage members), member (edit tasks) or guest (read only)';
+COMMENT ON COLUMN workspace_members.joined_at    IS 'When the user joined the workspace';
+
+CREATE TABLE IF NOT EXISTS workspace_invitations (
+    id            SERIAL PRIMARY KEY,
+    workspace_id  INT NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    user_id       INT REFERENCES users(id) ON DELETE CASCADE,
+    role          VARCHAR(10) NOT NULL,
+    token         VARCHAR(64) NOT NULL UNIQUE,
+    created_by    INT REFERENCES users(id) ON DELETE SET NULL,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at    TIMESTAMPTZ NOT NULL,
+    CONSTRAINT workspace_invitations_role CHECK (role IN ('admin', 'member', 'guest'))
+);
+
+-- A user has at most one pending invitation per workspace
+CREATE UNIQUE INDEX IF NOT EXISTS idx_workspace_invitations_user ON workspace_invitations(workspace_id, user_id) WHERE user_id IS NOT NULL;
+
+COMMENT ON TABLE  workspace_invitations              IS 'Pending invitations to join a workspace, addressed to a user or shared as a link';
+-- COLUMN COMMENTS
+COMMENT ON COLUMN workspace_invitations.id           IS 'Unique invitation identifier';
+COMMENT ON COLUMN workspace_invitations.workspace_id IS 'Foreign key referencing the workspace';
+COMMENT ON COLUMN workspace_invitations.user_id      IS 'Foreign key referencing the invited user, NULL for a link anyone can use until it expires';
+COMMENT ON COLUMN workspace_invitations.role         IS 'Role granted on acceptance: admin, member or guest';
+COMMENT ON COLUMN workspace_invitations.token        IS 'Random secret used to accept the invitation';
+COMMENT ON COLUMN workspace_invitations.created_by   IS 'Foreign key referencing the user who sent the invitation';
+COMMENT ON COLUMN workspace_invitations.created_at   IS 'Invitation creation time';
+COMMENT ON COLUMN workspace_invitations.expires_at   IS 'Time after which the invitation can no longer be accepted';
 
 -- -----------------------------------------------------------------------------
 
@@ -36,16 +118,29 @@ COMMENT ON COLUMN users.profile_img    IS 'User profile picture URL';
 -- * CREATE CATEGORIES TABLE *
 -- ****************************
 CREATE TABLE IF NOT EXISTS categories (
-    id          SERIAL PRIMARY KEY, 
-    name        VARCHAR(100) NOT NULL, 
-    description TEXT 
+    id           SERIAL PRIMARY KEY, 
+    name         VARCHAR(100) NOT NULL, 
+    description  TEXT,
+    workspace_id INT REFERENCES workspaces(id) ON DELETE CASCADE,
+    deleted_at   TIMESTAMPTZ,
+    version      BIGINT NOT NULL DEFAULT 1
 );
 
+-- Columns added after the table was first released; CREATE TABLE IF NOT EXISTS skips existing databases
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS workspace_id INT REFERENCES workspaces(id) ON DELETE CASCADE;
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS deleted_at   TIMESTAMPTZ;
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS version      BIGINT NOT NULL DEFAULT 1;
+
+CREATE INDEX IF NOT EXISTS idx_categories_workspace_id ON categories(workspace_id);
+
 COMMENT ON TABLE categories              IS 'Categories for classifying tasks';
 -- COLUMN COMMENTS
 COMMENT ON COLUMN categories.id          IS 'Unique category identifier';
 COMMENT ON COLUMN categories.name        IS 'Category name';
 COMMENT ON COLUMN categories.description IS 'Optional category description';
+COMMENT ON COLUMN categories.workspace_id IS 'Foreign key referencing the workspace the category belongs to, NULL for personal categories';
+COMMENT ON COLUMN categories.deleted_at  IS 'When the category was moved to the trash, NULL if active';
+COMMENT ON COLUMN categories.version     IS 'Incremented on every write, used as the ETag for optimistic concurrency';
 
 -- -----------------------------------------------------------------------------
 
@@ -54,20 +149,38 @@ COMMENT ON COLUMN categories.description IS 'Optional category description';
 -- *************************
 CREATE TABLE IF NOT EXISTS states (
     id                 SERIAL PRIMARY KEY,
-    description        VARCHAR(100) NOT NULL
+    description        VARCHAR(100) NOT NULL,
+    user_id            INT REFERENCES users(id) ON DELETE CASCADE,
+    position           INT NOT NULL DEFAULT 0,
+    is_done            BOOLEAN NOT NULL DEFAULT FALSE
 );
 
+-- Columns added after the table was first released; CREATE TABLE IF NOT EXISTS skips existing databases
+ALTER TABLE states ADD COLUMN IF NOT EXISTS user_id  INT REFERENCES users(id) ON DELETE CASCADE;
+ALTER TABLE states ADD COLUMN IF NOT EXISTS position INT NOT NULL DEFAULT 0;
+ALTER TABLE states ADD COLUMN IF NOT EXISTS is_done  BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- User-defined state names are unique per user, ignoring case
+CREATE UNIQUE INDEX IF NOT EXISTS idx_states_user_description ON states(user_id, LOWER(description)) WHERE user_id IS NOT NULL;
+
 COMMENT ON TABLE  states             IS 'States for classifying task statuses';
 -- COLUMN COMMENTS
 COMMENT ON COLUMN states.id          IS 'Unique status identifier';
 COMMENT ON COLUMN states.description IS 'Status description';
+COMMENT ON COLUMN states.user_id     IS 'Foreign key referencing the user who defined the state, NULL for built-in states';
+COMMENT ON COLUMN states.position    IS 'Order of the state in the workflow, ascending';
+COMMENT ON COLUMN states.is_done     IS 'Whether tasks in this state count as finished';
 
--- Insert basic states (only once during schema creation)
-INSERT INTO states (description) 
-VALUES ('Sin Empezar'),
-       ('En Progreso'),
-       ('Completada')
-ON CONFLICT DO NOTHING;
+-- Insert basic states (only once during schema creation); ids 1-3 are referenced by the application
+INSERT INTO states (description, position, is_done) 
+SELECT * FROM (VALUES ('Sin Empezar', 10, FALSE),
+                      ('En Progreso', 20, FALSE),
+                      ('Completada',  30, TRUE)) AS built_in (description, position, is_done)
+WHERE NOT EXISTS (SELECT 1 FROM states);
+
+-- Databases created before workflow states were configurable get the order and done flag of the basic states
+UPDATE states SET position = id * 10, is_done = (id = 3)
+WHERE id IN (1, 2, 3) AND user_id IS NULL AND position = 0;
 
 -- -----------------------------------------------------------------------------
 
@@ -81,9 +194,46 @@ CREATE TABLE IF NOT EXISTS tasks (
     end_date           DATE,
     id_state           INT REFERENCES states(id)     ON DELETE SET NULL,
     id_category        INT REFERENCES categories(id) ON DELETE SET NULL,
-    id_user            INT REFERENCES users(id)      ON DELETE CASCADE 
+    id_user            INT REFERENCES users(id)      ON DELETE CASCADE,
+    parent_id          INT REFERENCES tasks(id)      ON DELETE CASCADE,
+    recurrence         TEXT,
+    series_id          INT,
+    occurrence         INT NOT NULL DEFAULT 1,
+    priority           SMALLINT NOT NULL DEFAULT 0 CHECK (priority BETWEEN 0 AND 3),
+    important          BOOLEAN NOT NULL DEFAULT FALSE,
+    started_at         TIMESTAMPTZ,
+    completed_at       TIMESTAMPTZ,
+    created_by         INT REFERENCES users(id)      ON DELETE SET NULL,
+    assignee_id        INT REFERENCES users(id)      ON DELETE SET NULL,
+    workspace_id       INT REFERENCES workspaces(id) ON DELETE CASCADE,
+    position           TEXT COLLATE "C",
+    deleted_at         TIMESTAMPTZ,
+    version            BIGINT NOT NULL DEFAULT 1
 );
 
+-- Columns added after the table was first released; CREATE TABLE IF NOT EXISTS skips existing databases
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS parent_id    INT REFERENCES tasks(id)      ON DELETE CASCADE;
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS recurrence   TEXT;
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS series_id    INT;
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS occurrence   INT NOT NULL DEFAULT 1;
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS priority     SMALLINT NOT NULL DEFAULT 0 CHECK (priority BETWEEN 0 AND 3);
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS important    BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS started_at   TIMESTAMPTZ;
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS completed_at TIMESTAMPTZ;
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS created_by   INT REFERENCES users(id)      ON DELETE SET NULL;
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS assignee_id  INT REFERENCES users(id)      ON DELETE SET NULL;
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS workspace_id INT REFERENCES workspaces(id) ON DELETE CASCADE;
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS position     TEXT COLLATE "C";
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS deleted_at   TIMESTAMPTZ;
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS version      BIGINT NOT NULL DEFAULT 1;
+
+CREATE INDEX IF NOT EXISTS idx_tasks_parent_id ON tasks(parent_id);
+CREATE INDEX IF NOT EXISTS idx_tasks_series_id ON tasks(series_id);
+CREATE INDEX IF NOT EXISTS idx_tasks_deleted_at ON tasks(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_tasks_assignee_id ON tasks(assignee_id) WHERE assignee_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_tasks_workspace_id ON tasks(workspace_id) WHERE workspace_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_tasks_board ON tasks(id_state, position) WHERE deleted_at IS NULL;
+
 COMMENT ON TABLE  tasks                    IS 'Contains tasks created by users';
 -- COLUMN COMMENTS
 COMMENT ON COLUMN tasks.id                 IS 'Unique task identifier';
@@ -92,4 +242,294 @@ COMMENT ON COLUMN tasks.creation_date      IS 'Task creation date';
 COMMENT ON COLUMN tasks.end_date           IS 'Tentative task completion date';
 COMMENT ON COLUMN tasks.id_state           IS 'Foreign key referencing the status';
 COMMENT ON COLUMN tasks.id_category        IS 'Foreign key referencing the task category';
-COMMENT ON COLUMN tasks.id_user            IS 'Foreign key referencing the user who created the task';
+COMMENT ON COLUMN tasks.id_user            IS 'Foreign key referencing the user who owns the task';
+COMMENT ON COLUMN tasks.parent_id          IS 'Optional parent task, making this task a subtask';
+COMMENT ON COLUMN tasks.recurrence         IS 'RFC 5545 RRULE used to generate the next occurrence on completion';
+COMMENT ON COLUMN tasks.series_id          IS 'Identifier shared by all occurrences of a recurring task (id of the first one)';
+COMMENT ON COLUMN tasks.occurrence         IS 'Position of the task within its recurring series, starting at 1';
+COMMENT ON COLUMN tasks.priority           IS 'Task priority: 0 none, 1 low, 2 medium, 3 high';
+COMMENT ON COLUMN tasks.important          IS 'Whether the user flagged the task as important';
+COMMENT ON COLUMN tasks.started_at         IS 'First time the task went In Progress, cleared when it goes back to Not Started';
+COMMENT ON COLUMN tasks.completed_at       IS 'When the task was completed, NULL while it is open';
+COMMENT ON COLUMN tasks.created_by         IS 'Foreign key referencing the user who created the task, NULL for tasks created before it was recorded';
+COMMENT ON COLUMN tasks.assignee_id        IS 'Foreign key referencing the user responsible for the task, NULL when unassigned';
+COMMENT ON COLUMN tasks.workspace_id       IS 'Foreign key referencing the workspace the task belongs to, NULL for personal tasks';
+COMMENT ON COLUMN tasks.position           IS 'Fractional index ordering the task within its board column, compared byte by byte';
+COMMENT ON COLUMN tasks.deleted_at         IS 'When the task was moved to the trash, NULL if active';
+COMMENT ON COLUMN tasks.version            IS 'Incremented on every write, used as the ETag for optimistic concurrency';
+
+
+-- -----------------------------------------------------------------------------
+
+-- ************************************
+-- * CREATE TASK DEPENDENCIES TABLE  *
+-- ************************************
+CREATE TABLE IF NOT EXISTS task_dependencies (
+    task_id            INT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    blocker_id         INT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    PRIMARY KEY (task_id, blocker_id),
+    CHECK (task_id <> blocker_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_dependencies_blocker_id ON task_dependencies(blocker_id);
+
+COMMENT ON TABLE  task_dependencies            IS 'Tasks that must be completed before another task can progress';
+-- COLUMN COMMENTS
+COMMENT ON COLUMN task_dependencies.task_id    IS 'Foreign key referencing the blocked task';
+COMMENT ON COLUMN task_dependencies.blocker_id IS 'Foreign key referencing the task that must be completed first';
+
+-- -----------------------------------------------------------------------------
+
+-- **************************
+-- * CREATE REMINDERS TABLE *
+-- **************************
+CREATE TABLE IF NOT EXISTS reminders (
+    id                 SERIAL PRIMARY KEY,
+    task_id            INT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    user_id            INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    remind_at          TIMESTAMPTZ,
+    offset_minutes     INT,
+    channel            VARCHAR(20) NOT NULL DEFAULT 'in_app',
+    target             TEXT NOT NULL DEFAULT '',
+    status             VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts           INT NOT NULL DEFAULT 0,
+    next_attempt_at    TIMESTAMPTZ,
+    locked_until       TIMESTAMPTZ,
+    last_error         TEXT,
+    sent_at            TIMESTAMPTZ,
+    created_at         TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK ((remind_at IS NULL) <> (offset_minutes IS NULL))
+);
+
+CREATE INDEX IF NOT EXISTS idx_reminders_task_id ON reminders(task_id);
+CREATE INDEX IF NOT EXISTS idx_reminders_status  ON reminders(status);
+
+COMMENT ON TABLE  reminders                 IS 'Scheduled reminders about tasks, delivered by the background scheduler';
+-- COLUMN COMMENTS
+COMMENT ON COLUMN reminders.id              IS 'Unique reminder identifier';
+COMMENT ON COLUMN reminders.task_id         IS 'Foreign key referencing the task';
+COMMENT ON COLUMN reminders.user_id         IS 'Foreign key referencing the user to notify';
+COMMENT ON COLUMN reminders.remind_at       IS 'Absolute reminder time (exclusive with offset_minutes)';
+COMMENT ON COLUMN reminders.offset_minutes  IS 'Minutes before the task end_date (exclusive with remind_at)';
+COMMENT ON COLUMN reminders.channel         IS 'Delivery channel: in_app, email or webhook';
+COMMENT ON COLUMN reminders.target          IS 'Email address or webhook URL for the channel';
+COMMENT ON COLUMN reminders.status          IS 'pending, processing, sent or failed';
+COMMENT ON COLUMN reminders.attempts        IS 'Number of delivery attempts made';
+COMMENT ON COLUMN reminders.next_attempt_at IS 'When a failed delivery will be retried';
+COMMENT ON COLUMN reminders.locked_until    IS 'Lease held by the scheduler instance that claimed the reminder';
+COMMENT ON COLUMN reminders.last_error      IS 'Error returned by the last failed delivery';
+COMMENT ON COLUMN reminders.sent_at         IS 'When the reminder was delivered';
+COMMENT ON COLUMN reminders.created_at      IS 'Reminder creation time';
+
+-- -----------------------------------------------------------------------------
+
+-- ******************************
+-- * CREATE NOTIFICATIONS TABLE *
+-- ******************************
+CREATE TABLE IF NOT EXISTS notifications (
+    id                 SERIAL PRIMARY KEY,
+    user_id            INT NOT NULL REFERENCES users(id)     ON DELETE CASCADE,
+    task_id            INT          REFERENCES tasks(id)     ON DELETE SET NULL,
+    reminder_id        INT          REFERENCES reminders(id) ON DELETE SET NULL,
+    message            TEXT NOT NULL,
+    created_at         TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    read_at            TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_notifications_user_id ON notifications(user_id);
+
+COMMENT ON TABLE  notifications             IS 'In-app notifications shown to users';
+-- COLUMN COMMENTS
+COMMENT ON COLUMN notifications.id          IS 'Unique notification identifier';
+COMMENT ON COLUMN notifications.user_id     IS 'Foreign key referencing the notified user';
+COMMENT ON COLUMN notifications.task_id     IS 'Foreign key referencing the related task';
+COMMENT ON COLUMN notifications.reminder_id IS 'Foreign key referencing the reminder that produced it';
+COMMENT ON COLUMN notifications.message     IS 'Notification text';
+COMMENT ON COLUMN notifications.created_at  IS 'Notification creation time';
+COMMENT ON COLUMN notifications.read_at     IS 'When the user read the notification';
+-- -----------------------------------------------------------------------------
+
+-- *********************
+-- * CREATE TAGS TABLE *
+-- *********************
+CREATE TABLE IF NOT EXISTS tags (
+    id                 SERIAL PRIMARY KEY,
+    user_id            INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name               VARCHAR(50) NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tags_user_name ON tags(user_id, LOWER(name));
+
+COMMENT ON TABLE  tags         IS 'User-owned labels that can be attached to any number of tasks';
+-- COLUMN COMMENTS
+COMMENT ON COLUMN tags.id      IS 'Unique tag identifier';
+COMMENT ON COLUMN tags.user_id IS 'Foreign key referencing the user who owns the tag';
+COMMENT ON COLUMN tags.name    IS 'Tag name, unique per user regardless of case';
+
+-- -----------------------------------------------------------------------------
+
+-- **************************
+-- * CREATE TASK TAGS TABLE *
+-- **************************
+CREATE TABLE IF NOT EXISTS task_tags (
+    task_id            INT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    tag_id             INT NOT NULL REFERENCES tags(id)  ON DELETE CASCADE,
+    PRIMARY KEY (task_id, tag_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_tags_tag_id ON task_tags(tag_id);
+
+COMMENT ON TABLE  task_tags         IS 'Tags attached to each task';
+-- COLUMN COMMENTS
+COMMENT ON COLUMN task_tags.task_id IS 'Foreign key referencing the tagged task';
+COMMENT ON COLUMN task_tags.tag_id  IS 'Foreign key referencing the tag';
+
+-- -----------------------------------------------------------------------------
+
+-- ******************************
+-- * CREATE TASK COMMENTS TABLE *
+-- ******************************
+CREATE TABLE IF NOT EXISTS task_comments (
+    id                 SERIAL PRIMARY KEY,
+    task_id            INT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    author_id          INT          REFERENCES users(id) ON DELETE SET NULL,
+    body               TEXT NOT NULL,
+    created_at         TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at         TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_comments_task_id ON task_comments(task_id, created_at);
+
+COMMENT ON TABLE  task_comments            IS 'Notes and discussion left on tasks';
+-- COLUMN COMMENTS
+COMMENT ON COLUMN task_comments.id         IS 'Unique comment identifier';
+COMMENT ON COLUMN task_comments.task_id    IS 'Foreign key referencing the commented task';
+COMMENT ON COLUMN task_comments.author_id  IS 'Foreign key referencing the user who wrote the comment';
+COMMENT ON COLUMN task_comments.body       IS 'Comment text in Markdown';
+COMMENT ON COLUMN task_comments.created_at IS 'Comment creation time';
+COMMENT ON COLUMN task_comments.updated_at IS 'Time of the last edit, NULL if never edited';
+
+-- -----------------------------------------------------------------------------
+
+-- ****************************
+-- * CREATE TASK EVENTS TABLE *
+-- ****************************
+CREATE TABLE IF NOT EXISTS task_events (
+    id                 SERIAL PRIMARY KEY,
+    task_id            INT NOT NULL,
+    user_id            INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    actor_id           INT          REFERENCES users(id) ON DELETE SET NULL,
+    action             VARCHAR(20) NOT NULL,
+    changes            JSONB NOT NULL DEFAULT '[]',
+    created_at         TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_events_task_id ON task_events(task_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_task_events_user_id ON task_events(user_id, id);
+
+COMMENT ON TABLE  task_events            IS 'Change history of tasks, kept after the task is deleted';
+-- COLUMN COMMENTS
+COMMENT ON COLUMN task_events.id         IS 'Unique event identifier';
+COMMENT ON COLUMN task_events.task_id    IS 'Identifier of the changed task (not a foreign key so history survives deletion)';
+COMMENT ON COLUMN task_events.user_id    IS 'Foreign key referencing the owner of the task';
+COMMENT ON COLUMN task_events.actor_id   IS 'Foreign key referencing the user who made the change';
+COMMENT ON COLUMN task_events.action     IS 'created, updated or deleted';
+COMMENT ON COLUMN task_events.changes    IS 'Field-level differences: [{"field", "old", "new"}]';
+COMMENT ON COLUMN task_events.created_at IS 'When the change was made';
+
+-- -----------------------------------------------------------------------------
+
+-- ****************************
+-- * CREATE TIME ENTRIES TABLE *
+-- ****************************
+CREATE TABLE IF NOT EXISTS time_entries (
+    id                 SERIAL PRIMARY KEY,
+    task_id            INT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    user_id            INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    started_at         TIMESTAMPTZ NOT NULL,
+    stopped_at         TIMESTAMPTZ,
+    note               TEXT NOT NULL DEFAULT '',
+    created_at         TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT time_entries_stopped_after_started CHECK (stopped_at IS NULL OR stopped_at >= started_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_time_entries_task_id ON time_entries(task_id, started_at);
+CREATE INDEX IF NOT EXISTS idx_time_entries_user_started ON time_entries(user_id, started_at);
+-- At most one running timer per user
+CREATE UNIQUE INDEX IF NOT EXISTS idx_time_entries_running ON time_entries(user_id) WHERE stopped_at IS NULL;
+
+COMMENT ON TABLE  time_entries            IS 'Time spent by users on tasks, from timers or logged manually';
+-- COLUMN COMMENTS
+COMMENT ON COLUMN time_entries.id         IS 'Unique time entry identifier';
+COMMENT ON COLUMN time_entries.task_id    IS 'Foreign key referencing the task worked on';
+COMMENT ON COLUMN time_entries.user_id    IS 'Foreign key referencing the user who tracked the time';
+COMMENT ON COLUMN time_entries.started_at IS 'Start of the tracked interval';
+COMMENT ON COLUMN time_entries.stopped_at IS 'End of the tracked interval, NULL while the timer is running';
+COMMENT ON COLUMN time_entries.note       IS 'Optional description of the work done';
+COMMENT ON COLUMN time_entries.created_at IS 'Entry creation time';
+
+
+-- -----------------------------------------------------------------------------
+
+-- ****************************
+-- * CREATE TASK SHARES TABLE *
+-- ****************************
+CREATE TABLE IF NOT EXISTS task_shares (
+    id                 SERIAL PRIMARY KEY,
+    owner_id           INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    user_id            INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    task_id            INT REFERENCES tasks(id) ON DELETE CASCADE,
+    category_id        INT REFERENCES categories(id) ON DELETE CASCADE,
+    role               VARCHAR(10) NOT NULL,
+    created_at         TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT task_shares_role CHECK (role IN ('viewer', 'editor')),
+    CONSTRAINT task_shares_target CHECK ((task_id IS NULL) <> (category_id IS NULL)),
+    CONSTRAINT task_shares_not_self CHECK (owner_id <> user_id)
+);
+
+-- A user holds at most one share per task and per owner's category
+CREATE UNIQUE INDEX IF NOT EXISTS idx_task_shares_task_user ON task_shares(task_id, user_id) WHERE task_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_task_shares_category_user ON task_shares(owner_id, category_id, user_id) WHERE category_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_task_shares_user_id ON task_shares(user_id);
+
+COMMENT ON TABLE  task_shares             IS 'Tasks, or all of an owner''s tasks in a category, shared with other users';
+-- COLUMN COMMENTS
+COMMENT ON COLUMN task_shares.id          IS 'Unique share identifier';
+COMMENT ON COLUMN task_shares.owner_id    IS 'Foreign key referencing the user who owns the shared tasks';
+COMMENT ON COLUMN task_shares.user_id     IS 'Foreign key referencing the user the tasks are shared with';
+COMMENT ON COLUMN task_shares.task_id     IS 'Foreign key referencing the shared task, NULL for a category share';
+COMMENT ON COLUMN task_shares.category_id IS 'Foreign key referencing the category of the owner''s tasks being shared, NULL for a task share';
+COMMENT ON COLUMN task_shares.role        IS 'Access granted: viewer (read and comment) or editor (also change fields and state)';
+COMMENT ON COLUMN task_shares.created_at  IS 'Share creation time';
+
+-- -----------------------------------------------------------------------------
+
+-- *******************************
+-- * CREATE CALENDAR FEEDS TABLE *
+-- *******************************
+CREATE TABLE IF NOT EXISTS calendar_feeds (
+    user_id            INT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    token              VARCHAR(64) NOT NULL UNIQUE,
+    created_at         TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE  calendar_feeds            IS 'Secret addresses of the users'' iCalendar feeds of due tasks';
+-- COLUMN COMMENTS
+COMMENT ON COLUMN calendar_feeds.user_id    IS 'Foreign key referencing the user whose tasks the feed lists';
+COMMENT ON COLUMN calendar_feeds.token      IS 'Random secret in the feed URL, replaced when the user regenerates it';
+COMMENT ON COLUMN calendar_feeds.created_at IS 'When the current token was created';
+
+CREATE TABLE IF NOT EXISTS calendar_objects (
+    task_id            INT PRIMARY KEY REFERENCES tasks(id) ON DELETE CASCADE,
+    user_id            INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name               VARCHAR(255) NOT NULL,
+    uid                VARCHAR(255) NOT NULL,
+    CONSTRAINT calendar_objects_name UNIQUE (user_id, name)
+);
+
+COMMENT ON TABLE  calendar_objects         IS 'Resource names and UIDs CalDAV clients chose for the tasks they created';
+-- COLUMN COMMENTS
+COMMENT ON COLUMN calendar_objects.task_id IS 'Foreign key referencing the task';
+COMMENT ON COLUMN calendar_objects.user_id IS 'Foreign key referencing the owner of the task';
+COMMENT ON COLUMN calendar_objects.name    IS 'Last path segment of the resource, unique per user';
+COMMENT ON COLUMN calendar_objects.uid     IS 'iCalendar UID the client gave the to-do';